@@ -0,0 +1,14 @@
+#!/bin/bash
+set -e
+
+# Create directories
+mkdir -p ../dist/daemon
+mkdir -p ../dispense/pkg/daemon
+
+# Build the daemon for Linux ARM64
+GOOS=linux GOARCH=arm64 CGO_ENABLED=0 go build -o ../dist/daemon/daemon-linux-arm64 ./cmd/main.go
+
+# Copy to Dispense package
+cp ../dist/daemon/daemon-linux-arm64 ../dispense/pkg/daemon/daemon-linux-arm64
+
+echo "Linux ARM64 build completed and binary copied to Dispense package"
\ No newline at end of file
@@ -7,10 +7,13 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"daemon/internal/server"
+	"daemon/internal/supervisor"
 )
 
 // Build metadata - set during compilation
@@ -22,8 +25,19 @@ var (
 
 func main() {
 	// Parse command line flags
-	var showVersion bool
+	var showVersion, supervise bool
+	var maxConcurrentTasks int
+	var taskTimeout, idleTimeout, waitReady time.Duration
+	var logFile, listenAddress, credentialsDir string
 	flag.BoolVar(&showVersion, "version", false, "Print version information and exit")
+	flag.BoolVar(&supervise, "supervise", false, "Run the daemon as a child process, restart it when it exits and write its log to --log-file")
+	flag.StringVar(&logFile, "log-file", defaultLogFile(), "Rotated log file of the daemon when running with --supervise")
+	flag.StringVar(&listenAddress, "listen", stringFromEnv("DISPENSE_DAEMON_LISTEN", server.DefaultListenAddress), "Address to listen on, such as :28080, 127.0.0.1:28080 or unix:///run/dispensed.sock (env DISPENSE_DAEMON_LISTEN)")
+	flag.StringVar(&credentialsDir, "credentials-dir", defaultCredentialsDir(), "Directory with the token and TLS files clients are authenticated with, authentication is disabled if they do not exist")
+	flag.DurationVar(&waitReady, "wait-ready", 0, "Wait up to this long for the daemon on this machine to be ready, then exit (non-zero if it is not)")
+	flag.IntVar(&maxConcurrentTasks, "max-concurrent-tasks", server.DefaultMaxConcurrentTasks, "Maximum number of Claude tasks to run at once, further tasks are queued")
+	flag.DurationVar(&taskTimeout, "task-timeout", durationFromEnv("DISPENSE_TASK_TIMEOUT"), "Default time limit for a Claude task, 0 for no limit (env DISPENSE_TASK_TIMEOUT)")
+	flag.DurationVar(&idleTimeout, "idle-timeout", durationFromEnv("DISPENSE_IDLE_TIMEOUT"), "Default time a Claude task may go without output, 0 for no limit (env DISPENSE_IDLE_TIMEOUT)")
 	flag.Parse()
 
 	// Handle version flag
@@ -34,6 +48,26 @@ func main() {
 		return
 	}
 
+	auth, err := server.LoadAuthConfig(credentialsDir)
+	if err != nil {
+		log.Fatalf("Failed to load credentials: %v", err)
+	}
+
+	// Handle wait-ready flag, used by installers instead of sleeping
+	if waitReady > 0 {
+		if err := supervisor.WaitReady(server.DialTarget(listenAddress), waitReady, auth.ClientDialOptions()...); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Daemon is ready")
+		return
+	}
+
+	if supervise {
+		runSupervisor(logFile)
+		return
+	}
+
 	// Create a context that can be cancelled
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -46,11 +80,15 @@ func main() {
 	log.Println("Starting daemon...")
 	
 	// Create and start gRPC server
-	grpcServer := server.NewGRPCServer()
+	grpcServer := server.NewGRPCServer(maxConcurrentTasks, taskTimeout, idleTimeout, server.BuildInfo{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+	}, auth)
 	
 	// Start gRPC server in a goroutine
 	go func() {
-		if err := grpcServer.Start("28080"); err != nil {
+		if err := grpcServer.Start(listenAddress); err != nil {
 			log.Fatalf("Failed to start gRPC server: %v", err)
 		}
 	}()
@@ -73,6 +111,82 @@ func main() {
 	log.Println("Daemon stopped")
 }
 
+// durationFromEnv reads a duration such as "30m" from an environment variable,
+// returning 0 if it is unset or invalid
+func durationFromEnv(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: Ignoring invalid %s %q: %v", name, value, err)
+		return 0
+	}
+	return duration
+}
+
+// stringFromEnv reads a string from an environment variable, returning
+// fallback if it is unset
+func stringFromEnv(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// defaultCredentialsDir returns the directory the CLI writes the daemon's credentials to, ~/.dispense
+func defaultCredentialsDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".dispense")
+}
+
+// defaultLogFile returns the log file of a supervised daemon, ~/.dispense/dispensed.log
+func defaultLogFile() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".dispense", "dispensed.log")
+}
+
+// runSupervisor runs the daemon under the supervisor, which restarts it when
+// it exits, writing the output of both to a rotated log file
+func runSupervisor(logFile string) {
+	out, err := supervisor.OpenRotatingFile(logFile, supervisor.LogMaxSize, supervisor.LogBackups)
+	if err != nil {
+		log.Fatalf("Failed to open log file: %v", err)
+	}
+	defer out.Close()
+	log.SetOutput(out)
+
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Failed to find the daemon executable: %v", err)
+	}
+
+	log.Printf("Supervisor: starting daemon %s (version %s)", executable, Version)
+	if err := supervisor.Run(executable, withoutFlag(os.Args[1:], "supervise"), out); err != nil {
+		log.Fatalf("Supervisor failed: %v", err)
+	}
+	log.Println("Supervisor: stopped")
+}
+
+// withoutFlag removes a boolean flag, in any of the forms the flag package
+// accepts, from command line arguments
+func withoutFlag(args []string, name string) []string {
+	var result []string
+	for _, arg := range args {
+		flagName := strings.TrimLeft(arg, "-")
+		if i := strings.Index(flagName, "="); i >= 0 {
+			flagName = flagName[:i]
+		}
+		if strings.HasPrefix(arg, "-") && flagName == name {
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
 func runDaemon(ctx context.Context) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
@@ -83,7 +197,7 @@ func runDaemon(ctx context.Context) {
 			log.Println("Daemon context cancelled, stopping...")
 			return
 		case <-ticker.C:
-			log.Println("Daemon is running... (gRPC server available on port 28080)")
+			log.Println("Daemon is running... (gRPC server available)")
 			// Add your daemon logic here
 		}
 	}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithoutFlag(t *testing.T) {
+	if got := withoutFlag(nil, "supervise"); len(got) != 0 {
+		t.Errorf("withoutFlag(nil) = %q, want no arguments", got)
+	}
+
+	// Both dash styles and explicit values are removed
+	for _, args := range [][]string{
+		{"--supervise", "--port", "28080"},
+		{"-supervise", "--port", "28080"},
+		{"--port", "28080", "--supervise=true"},
+		{"-supervise=false", "--port", "28080"},
+	} {
+		if got := withoutFlag(args, "supervise"); !reflect.DeepEqual(got, []string{"--port", "28080"}) {
+			t.Errorf("withoutFlag(%q) = %q, want %q", args, got, []string{"--port", "28080"})
+		}
+	}
+
+	// Flags that only share the prefix and positional arguments are kept
+	args := []string{"--supervise-log", "x.log", "supervise"}
+	if got := withoutFlag(args, "supervise"); !reflect.DeepEqual(got, args) {
+		t.Errorf("withoutFlag(%q) = %q, want the arguments unchanged", args, got)
+	}
+}
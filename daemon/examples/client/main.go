@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 
 	"daemon/proto"
@@ -23,15 +24,24 @@ func main() {
 	projectClient := proto.NewProjectServiceClient(conn)
 	agentClient := proto.NewAgentServiceClient(conn)
 
-	// Test ProjectService.Init
-	fmt.Println("Testing ProjectService.Init...")
-	projectInitResp, err := projectClient.Init(context.Background(), &proto.InitRequest{
-		ProjectType: "web-application",
+	// Test ProjectService.Init (streaming)
+	fmt.Println("Testing ProjectService.Init (streaming)...")
+	initStream, err := projectClient.Init(context.Background(), &proto.InitRequest{
+		WorkingDirectory: "/workspace",
 	})
 	if err != nil {
 		log.Printf("ProjectService.Init failed: %v", err)
 	} else {
-		fmt.Printf("ProjectService.Init response: %+v\n", projectInitResp)
+		for {
+			initResp, err := initStream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					log.Printf("Error receiving init progress: %v", err)
+				}
+				break
+			}
+			fmt.Printf("ProjectService.Init %s: %s\n", initResp.Type, initResp.Content)
+		}
 	}
 
 	// Test AgentService.Init
@@ -7,6 +7,7 @@ toolchain go1.23.5
 require (
 	google.golang.org/grpc v1.75.1
 	google.golang.org/protobuf v1.36.9
+	workspacediff v0.0.0
 )
 
 require (
@@ -15,3 +16,5 @@ require (
 	golang.org/x/text v0.26.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
 )
+
+replace workspacediff => ../libs/workspace-diff-go
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Files in the credentials directory, written by the CLI when it creates the sandbox
+const (
+	TokenFile      = "daemon.token"
+	CACertFile     = "tls/ca.pem"
+	ServerCertFile = "tls/server.pem"
+	ServerKeyFile  = "tls/server-key.pem"
+)
+
+// ServerName is the name in the daemon's certificate that clients verify
+const ServerName = "dispensed"
+
+// AuthConfig is how the daemon authenticates its clients. Without a token
+// and TLS every client is accepted, as for sandboxes created before
+// credentials were introduced.
+type AuthConfig struct {
+	// Token has to be sent by clients as "authorization: Bearer <token>"
+	Token string
+	// TLS serves the daemon over TLS and requires clients to present a
+	// certificate signed by the sandbox's CA
+	TLS *tls.Config
+
+	caPool *x509.CertPool
+}
+
+// LoadAuthConfig reads the token and TLS files from dir. Each is optional:
+// authentication is only enabled for the files that exist.
+func LoadAuthConfig(dir string) (*AuthConfig, error) {
+	auth := &AuthConfig{}
+
+	token, err := os.ReadFile(filepath.Join(dir, TokenFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	auth.Token = strings.TrimSpace(string(token))
+
+	caCert, err := os.ReadFile(filepath.Join(dir, CACertFile))
+	if os.IsNotExist(err) {
+		return auth, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	auth.caPool = x509.NewCertPool()
+	if !auth.caPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificate found in %s", filepath.Join(dir, CACertFile))
+	}
+
+	certificate, err := tls.LoadX509KeyPair(filepath.Join(dir, ServerCertFile), filepath.Join(dir, ServerKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	// Clients without a certificate may still complete the handshake so
+	// that health checks work; every other call is refused by authorize
+	auth.TLS = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientCAs:    auth.caPool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		MinVersion:   tls.VersionTLS12,
+	}
+	return auth, nil
+}
+
+// Enabled reports whether clients have to authenticate
+func (a *AuthConfig) Enabled() bool {
+	return a != nil && (a.Token != "" || a.TLS != nil)
+}
+
+// Description describes the authentication for the daemon's log
+func (a *AuthConfig) Description() string {
+	switch {
+	case a == nil || !a.Enabled():
+		return "no client authentication"
+	case a.Token != "" && a.TLS != nil:
+		return "token and mutual TLS authentication"
+	case a.TLS != nil:
+		return "mutual TLS authentication"
+	default:
+		return "token authentication"
+	}
+}
+
+// serverOptions returns the options that make a gRPC server authenticate clients
+func (a *AuthConfig) serverOptions() []grpc.ServerOption {
+	if !a.Enabled() {
+		return nil
+	}
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := a.authorize(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := a.authorize(stream.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}),
+	}
+	if a.TLS != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(a.TLS)))
+	}
+	return options
+}
+
+// ClientDialOptions returns the options for connecting to the daemon from
+// within the sandbox. They are enough for the health service only, which
+// does not require a client certificate or token.
+func (a *AuthConfig) ClientDialOptions() []grpc.DialOption {
+	if a == nil || a.TLS == nil {
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		RootCAs:    a.caPool,
+		ServerName: ServerName,
+		MinVersion: tls.VersionTLS12,
+	}))}
+}
+
+// authorize checks the credentials of a call to method. The health service
+// is open to every client so that readiness can be checked without them.
+func (a *AuthConfig) authorize(ctx context.Context, method string) error {
+	if strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+
+	if a.TLS != nil {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return status.Error(codes.Unauthenticated, "missing client certificate")
+		}
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+			return status.Error(codes.Unauthenticated, "missing client certificate")
+		}
+	}
+
+	if a.Token != "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var token string
+		for _, value := range md.Get("authorization") {
+			if strings.HasPrefix(value, "Bearer ") {
+				token = strings.TrimPrefix(value, "Bearer ")
+				break
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.Token)) != 1 {
+			return status.Error(codes.Unauthenticated, "invalid or missing token")
+		}
+	}
+
+	return nil
+}
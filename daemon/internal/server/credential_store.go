@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// DefaultCredentialsName is used by tasks that do not name their credentials
+const DefaultCredentialsName = "default"
+
+// credentialsNamePattern restricts names to ones that are safe to log
+var credentialsNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// storedCredentials are the credentials kept under one name
+type storedCredentials struct {
+	AnthropicAPIKey string `json:"anthropic_api_key"`
+	persist         bool
+}
+
+// credentialStore keeps the API keys set through SetCredentials in memory.
+// Credentials set with persist are also written to a file only the daemon's
+// user can read, and are loaded from it again when the daemon restarts.
+type credentialStore struct {
+	mutex       sync.RWMutex
+	path        string
+	credentials map[string]*storedCredentials
+	logs        *LogBus
+}
+
+// newCredentialStore creates a credential store persisting to path, loading
+// the credentials already in it. Every key is registered with the log bus
+// so that it never shows up in a log.
+func newCredentialStore(path string, logs *LogBus) *credentialStore {
+	store := &credentialStore{
+		path:        path,
+		credentials: make(map[string]*storedCredentials),
+		logs:        logs,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to read stored credentials: %v", err)
+		}
+		return store
+	}
+
+	var saved map[string]*storedCredentials
+	if err := json.Unmarshal(data, &saved); err != nil {
+		log.Printf("Warning: Ignoring invalid stored credentials in %s: %v", path, err)
+		return store
+	}
+	for name, credentials := range saved {
+		credentials.persist = true
+		store.credentials[name] = credentials
+		logs.AddSecret(credentials.AnthropicAPIKey)
+	}
+	log.Printf("Loaded %d stored credentials", len(saved))
+	return store
+}
+
+// set stores an API key under name, an empty key removes the credentials
+func (s *credentialStore) set(name, apiKey string, persist bool) error {
+	if !credentialsNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid credentials name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	s.logs.AddSecret(apiKey)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous := s.credentials[name]
+	if apiKey == "" {
+		delete(s.credentials, name)
+	} else {
+		s.credentials[name] = &storedCredentials{AnthropicAPIKey: apiKey, persist: persist}
+	}
+
+	// Rewrite the file if it holds or held these credentials
+	if persist || (previous != nil && previous.persist) {
+		return s.save()
+	}
+	return nil
+}
+
+// apiKey returns the API key stored under name
+func (s *credentialStore) apiKey(name string) (string, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	credentials, exists := s.credentials[name]
+	if !exists {
+		return "", false
+	}
+	return credentials.AnthropicAPIKey, true
+}
+
+// names returns the names of the stored credentials in order
+func (s *credentialStore) names() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	names := make([]string, 0, len(s.credentials))
+	for name := range s.credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// save writes the persisted credentials to the file, replacing it
+// atomically (mutex must be held)
+func (s *credentialStore) save() error {
+	saved := make(map[string]*storedCredentials)
+	for name, credentials := range s.credentials {
+		if credentials.persist {
+			saved[name] = credentials
+		}
+	}
+
+	if len(saved) == 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stored credentials: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	return nil
+}
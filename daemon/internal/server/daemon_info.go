@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// claudeVersionTimeout limits how long claude --version may take
+const claudeVersionTimeout = 10 * time.Second
+
+// BuildInfo identifies the build of the daemon
+type BuildInfo struct {
+	Version   string
+	GitCommit string
+	BuildTime string
+}
+
+// claudeVersionCache remembers the version of the Claude CLI once it was
+// read successfully, so that readiness polling does not start a process for
+// every request
+type claudeVersionCache struct {
+	mutex   sync.Mutex
+	version string
+}
+
+// get returns the version of the Claude CLI, reading it if it is not known yet
+func (c *claudeVersionCache) get(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.version != "" {
+		return c.version, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, claudeVersionTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "claude", "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run claude --version: %w", err)
+	}
+
+	c.version = strings.TrimSpace(string(output))
+	return c.version, nil
+}
@@ -4,58 +4,123 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"daemon/proto"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
+// gracefulStopTimeout is how long running requests may take to finish when the daemon stops
+const gracefulStopTimeout = 10 * time.Second
+
+// taskShutdownGracePeriod is how long a running task gets to exit after each
+// signal when the daemon stops
+const taskShutdownGracePeriod = 2 * time.Second
+
 type GRPCServer struct {
-	server *grpc.Server
+	server             *grpc.Server
+	health             *health.Server
+	taskManager        *TaskManager
+	build              BuildInfo
+	auth               *AuthConfig
+	maxConcurrentTasks int
+	taskTimeout        time.Duration
+	idleTimeout        time.Duration
 }
 
 type ProjectServiceServer struct {
 	proto.UnimplementedProjectServiceServer
+	logs      *LogBus
+	initMutex sync.Mutex
 }
 
 type AgentServiceServer struct {
 	proto.UnimplementedAgentServiceServer
 	taskManager *TaskManager
+
+	// taskTimeout and idleTimeout apply to tasks that do not set their own
+	taskTimeout time.Duration
+	idleTimeout time.Duration
+
+	// credentials holds the API keys set through SetCredentials
+	credentials *credentialStore
+
+	// build, startedAt and claudeVersion are reported by DaemonInfo
+	build         BuildInfo
+	startedAt     time.Time
+	claudeVersion claudeVersionCache
 }
 
-// NewGRPCServer creates a new gRPC server instance
-func NewGRPCServer() *GRPCServer {
-	return &GRPCServer{}
+// NewGRPCServer creates a new gRPC server instance that runs at most
+// maxConcurrentTasks Claude tasks at once. taskTimeout and idleTimeout are the
+// default limits for tasks that do not set their own, zero means no limit.
+// build is reported to clients asking for the daemon's version and auth is
+// how clients have to authenticate, nil accepts every client.
+func NewGRPCServer(maxConcurrentTasks int, taskTimeout, idleTimeout time.Duration, build BuildInfo, auth *AuthConfig) *GRPCServer {
+	return &GRPCServer{
+		build:              build,
+		auth:               auth,
+		maxConcurrentTasks: maxConcurrentTasks,
+		taskTimeout:        taskTimeout,
+		idleTimeout:        idleTimeout,
+	}
 }
 
-// Start starts the gRPC server on the specified port
-func (s *GRPCServer) Start(port string) error {
-	lis, err := net.Listen("tcp", ":"+port)
+// Start starts the gRPC server on the specified address, a TCP address such
+// as ":28080" or a unix socket such as "unix:///run/dispensed.sock"
+func (s *GRPCServer) Start(address string) error {
+	lis, err := listen(address)
 	if err != nil {
-		return fmt.Errorf("failed to listen on port %s: %v", port, err)
+		return fmt.Errorf("failed to listen on %s: %v", address, err)
 	}
 
-	s.server = grpc.NewServer()
+	s.server = grpc.NewServer(s.auth.serverOptions()...)
+
+	// Route the daemon's log output through the log bus so it can be streamed
+	logs := NewLogBus(log.Writer())
+	log.SetOutput(logs)
 	
 	// Create log directory for Claude tasks
 	homeDir, _ := os.UserHomeDir()
 	logDir := filepath.Join(homeDir, ".dispense", "logs")
-	taskManager := NewTaskManager(logDir)
+	taskDir := filepath.Join(homeDir, ".dispense", "tasks")
+	taskManager := NewTaskManager(logDir, taskDir, s.maxConcurrentTasks, logs)
+	s.taskManager = taskManager
 
 	// Register services
-	projectServer := &ProjectServiceServer{}
+	projectServer := &ProjectServiceServer{
+		logs: logs,
+	}
 	agentServer := &AgentServiceServer{
 		taskManager: taskManager,
+		credentials: newCredentialStore(filepath.Join(homeDir, ".dispense", "credentials.json"), logs),
+		taskTimeout: s.taskTimeout,
+		idleTimeout: s.idleTimeout,
+		build:       s.build,
+		startedAt:   time.Now(),
 	}
 	
 	proto.RegisterProjectServiceServer(s.server, projectServer)
 	proto.RegisterAgentServiceServer(s.server, agentServer)
 
-	log.Printf("Starting gRPC server on port %s", port)
+	// The standard health service reports the daemon as serving once the
+	// task history is loaded and the services are registered
+	s.health = health.NewServer()
+	healthpb.RegisterHealthServer(s.server, s.health)
+	for _, service := range []string{"", proto.ProjectService_ServiceDesc.ServiceName, proto.AgentService_ServiceDesc.ServiceName} {
+		s.health.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	log.Printf("Starting gRPC server on %s with %s", address, s.auth.Description())
 	
 	if err := s.server.Serve(lis); err != nil {
 		return fmt.Errorf("failed to serve gRPC server: %v", err)
@@ -68,46 +133,138 @@ func (s *GRPCServer) Start(port string) error {
 func (s *GRPCServer) Stop() {
 	if s.server != nil {
 		log.Println("Stopping gRPC server...")
-		s.server.GracefulStop()
+		// Report the daemon as not serving while running requests finish
+		if s.health != nil {
+			s.health.Shutdown()
+		}
+
+		// Claude runs in its own process group, which would outlive the daemon
+		if s.taskManager != nil {
+			s.taskManager.Shutdown(taskShutdownGracePeriod)
+		}
+
+		// Streams such as followed logs never finish on their own, so they
+		// are closed once the grace period is over
+		stopped := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(gracefulStopTimeout):
+			log.Printf("Requests still running after %s, closing them", gracefulStopTimeout)
+			s.server.Stop()
+		}
 		log.Println("gRPC server stopped")
 	}
 }
 
 // ProjectService implementation
 
-// Init initializes the project
-func (s *ProjectServiceServer) Init(ctx context.Context, req *proto.InitRequest) (*proto.InitResponse, error) {
-	log.Printf("ProjectService.Init called with project_type: %s", req.ProjectType)
-	
-	// Add your project initialization logic here
-	// You can now use req.ProjectType to determine how to initialize the project
-	
-	return &proto.InitResponse{
-		Success: true,
-		Message: fmt.Sprintf("Project initialization started for type: %s", req.ProjectType),
-	}, nil
+// Init detects the toolchains of the project, installs their dependencies and
+// builds it, streaming the progress. The build and test commands are recorded
+// so that Claude tasks started in the project know them.
+func (s *ProjectServiceServer) Init(req *proto.InitRequest, stream proto.ProjectService_InitServer) error {
+	log.Printf("ProjectService.Init called with project_type: %s, working_directory: %s", req.ProjectType, req.WorkingDirectory)
+
+	// Two initializations would run package managers over the same files
+	if !s.initMutex.TryLock() {
+		return status.Error(codes.FailedPrecondition, "project initialization is already running")
+	}
+	defer s.initMutex.Unlock()
+
+	dir := req.WorkingDirectory
+	if dir == "" {
+		dir = defaultProjectDir
+	}
+
+	toolchains, err := detectToolchains(dir, req.ProjectType)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if len(toolchains) == 0 {
+		log.Printf("No known toolchain found in %s", dir)
+	} else {
+		var names []string
+		for _, toolchain := range toolchains {
+			names = append(names, toolchain.Name)
+		}
+		log.Printf("Detected toolchains in %s: %s", dir, strings.Join(names, ", "))
+		if err := sendProjectInit(stream.Send, proto.ProjectInitResponse_STATUS, fmt.Sprintf("Detected %s in %s", strings.Join(names, ", "), dir)); err != nil {
+			return err
+		}
+	}
+
+	record, err := initProject(stream.Context(), dir, req.SetupCommands, toolchains, stream.Send)
+	if err != nil {
+		log.Printf("Error sending project initialization progress: %v", err)
+		return err
+	}
+
+	// Commands configured for the project replace the detected ones
+	if len(req.TestCommands) > 0 {
+		record.TestCommands = req.TestCommands
+	}
+	record.LintCommands = req.LintCommands
+
+	if err := saveProjectRecord(projectRecordPath(), record); err != nil {
+		log.Printf("Warning: Failed to save project record: %v", err)
+	}
+
+	var message string
+	switch {
+	case len(toolchains) == 0 && len(req.SetupCommands) == 0:
+		message = fmt.Sprintf("No known toolchain found in %s, nothing to set up", dir)
+	case len(toolchains) == 0 && record.Success:
+		message = "Project setup commands finished"
+	case len(toolchains) == 0:
+		message = "Project setup commands finished with errors"
+	case record.Success:
+		message = fmt.Sprintf("Project initialized: %s", strings.Join(record.Toolchains, ", "))
+	default:
+		message = fmt.Sprintf("Project initialized with errors: %s", strings.Join(record.Toolchains, ", "))
+	}
+	log.Print(message)
+
+	return stream.Send(&proto.ProjectInitResponse{
+		Type:       proto.ProjectInitResponse_DONE,
+		Content:    message,
+		Timestamp:  time.Now().Unix(),
+		IsFinished: true,
+		Success:    record.Success,
+		Project:    record.info(),
+	})
 }
 
-// Logs streams project logs
+// Logs streams the daemon's log lines and task events matching the request,
+// starting with the retained entries and following new ones if requested
 func (s *ProjectServiceServer) Logs(req *proto.LogsRequest, stream proto.ProjectService_LogsServer) error {
-	log.Println("ProjectService.Logs called - starting log stream")
-	
-	// Simulate log streaming
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	log.Printf("ProjectService.Logs called (task: %q, min level: %s, since: %d, follow: %v)", req.TaskId, req.MinLevel, req.Since, req.Follow)
+
+	sub, backlog := s.logs.subscribe(req)
+	if sub != nil {
+		defer s.logs.unsubscribe(sub)
+	}
+
+	for _, entry := range backlog {
+		if err := stream.Send(entry); err != nil {
+			return err
+		}
+	}
+
+	if sub == nil {
+		return nil
+	}
 
 	for {
 		select {
 		case <-stream.Context().Done():
 			log.Println("Log stream context cancelled")
 			return nil
-		case <-ticker.C:
-			logEntry := fmt.Sprintf("Project log entry at %s", time.Now().Format(time.RFC3339))
-			
-			if err := stream.Send(&proto.LogsResponse{
-				LogEntry: logEntry,
-				Timestamp: time.Now().Unix(),
-			}); err != nil {
+		case entry := <-sub.ch:
+			if err := stream.Send(entry); err != nil {
 				log.Printf("Error sending log: %v", err)
 				return err
 			}
@@ -117,16 +274,35 @@ func (s *ProjectServiceServer) Logs(req *proto.LogsRequest, stream proto.Project
 
 // AgentService implementation
 
-// Init initializes the agent
+// Init reports the project recorded by the last ProjectService.Init, which
+// Claude tasks are told about. It does not set anything up itself.
 func (s *AgentServiceServer) Init(ctx context.Context, req *proto.InitRequest) (*proto.InitResponse, error) {
-	log.Printf("AgentService.Init called with project_type: %s", req.ProjectType)
-	
-	// Add your agent initialization logic here
-	// You can now use req.ProjectType to determine how to initialize the agent
-	
+	log.Printf("AgentService.Init called")
+
+	record, err := loadProjectRecord(projectRecordPath())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load project record: %v", err)
+	}
+
+	if record == nil {
+		return &proto.InitResponse{
+			Success: true,
+			Message: "Agent ready, no project initialized yet",
+		}, nil
+	}
+
+	message := fmt.Sprintf("Agent ready, project in %s initialized", record.WorkingDir)
+	if !record.Success {
+		message = fmt.Sprintf("Agent ready, project in %s initialized with errors", record.WorkingDir)
+	}
+	if len(record.Toolchains) > 0 {
+		message += fmt.Sprintf(": %s", strings.Join(record.Toolchains, ", "))
+	}
+
 	return &proto.InitResponse{
-		Success: true,
-		Message: fmt.Sprintf("Agent initialized successfully for type: %s", req.ProjectType),
+		Success: record.Success,
+		Message: message,
+		Project: record.info(),
 	}, nil
 }
 
@@ -134,8 +310,21 @@ func (s *AgentServiceServer) Init(ctx context.Context, req *proto.InitRequest) (
 func (s *AgentServiceServer) CreateTask(ctx context.Context, req *proto.CreateTaskRequest) (*proto.CreateTaskResponse, error) {
 	log.Printf("AgentService.CreateTask called with prompt: %s", req.Prompt)
 
+	apiKey, err := s.resolveAPIKey(req.AnthropicApiKey, req.CredentialsName)
+	var resumeTaskID string
+	if err == nil {
+		resumeTaskID, err = s.resolveResumeTask(req.ResumeTaskId, req.ContinueSession)
+	}
+	if err != nil {
+		return &proto.CreateTaskResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to start Claude task: %v", err),
+		}, nil
+	}
+
 	// Start Claude task using the task manager
-	taskID, err := s.taskManager.StartClaudeTask(req.Prompt, req.WorkingDirectory, req.AnthropicApiKey, req.Model, req.EnvironmentVars)
+	timeout, idleTimeout := s.taskTimeouts(req.TimeoutSeconds, req.IdleTimeoutSeconds)
+	taskID, err := s.taskManager.StartClaudeTask(req.Prompt, req.WorkingDirectory, apiKey, req.Model, req.EnvironmentVars, resumeTaskID, timeout, idleTimeout, req.VerifyCommands, int(req.MaxFixAttempts))
 	if err != nil {
 		log.Printf("Failed to start Claude task: %v", err)
 		return &proto.CreateTaskResponse{
@@ -156,7 +345,15 @@ func (s *AgentServiceServer) ExecuteClaude(req *proto.ExecuteClaudeRequest, stre
 	log.Printf("AgentService.ExecuteClaude called with prompt: %s", req.Prompt)
 
 	// Start Claude task
-	taskID, err := s.taskManager.StartClaudeTask(req.Prompt, req.WorkingDirectory, req.AnthropicApiKey, req.Model, req.EnvironmentVars)
+	var taskID, resumeTaskID string
+	apiKey, err := s.resolveAPIKey(req.AnthropicApiKey, req.CredentialsName)
+	if err == nil {
+		resumeTaskID, err = s.resolveResumeTask(req.ResumeTaskId, req.ContinueSession)
+	}
+	if err == nil {
+		timeout, idleTimeout := s.taskTimeouts(req.TimeoutSeconds, req.IdleTimeoutSeconds)
+		taskID, err = s.taskManager.StartClaudeTask(req.Prompt, req.WorkingDirectory, apiKey, req.Model, req.EnvironmentVars, resumeTaskID, timeout, idleTimeout, req.VerifyCommands, int(req.MaxFixAttempts))
+	}
 	if err != nil {
 		log.Printf("Failed to start Claude task: %v", err)
 		return stream.Send(&proto.ExecuteClaudeResponse{
@@ -169,7 +366,97 @@ func (s *AgentServiceServer) ExecuteClaude(req *proto.ExecuteClaudeRequest, stre
 	log.Printf("Started Claude task %s, streaming output...", taskID)
 
 	// Stream the task output
-	return s.taskManager.StreamTaskOutput(taskID, stream)
+	return s.taskManager.StreamTaskOutput(taskID, 0, stream)
+}
+
+// SetCredentials stores an API key under a name that tasks refer to
+func (s *AgentServiceServer) SetCredentials(ctx context.Context, req *proto.SetCredentialsRequest) (*proto.SetCredentialsResponse, error) {
+	name := req.Name
+	if name == "" {
+		name = DefaultCredentialsName
+	}
+	log.Printf("AgentService.SetCredentials called for %q (persist: %v)", name, req.Persist)
+
+	if err := s.credentials.set(name, req.AnthropicApiKey, req.Persist); err != nil {
+		log.Printf("Failed to store credentials %q: %v", name, err)
+		return &proto.SetCredentialsResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to store credentials: %v", err),
+		}, nil
+	}
+
+	if req.AnthropicApiKey == "" {
+		return &proto.SetCredentialsResponse{
+			Success: true,
+			Message: fmt.Sprintf("Credentials %q removed", name),
+		}, nil
+	}
+	return &proto.SetCredentialsResponse{
+		Success: true,
+		Message: fmt.Sprintf("Credentials %q stored", name),
+	}, nil
+}
+
+// resolveAPIKey returns the API key a new task runs with. A key sent with the
+// request, as older CLIs do, is used as it is. Otherwise the key is looked up
+// by credentialsName; without a name the default credentials are used if they
+// were set, and Claude's own login if not.
+func (s *AgentServiceServer) resolveAPIKey(inlineKey, credentialsName string) (string, error) {
+	if inlineKey != "" {
+		return inlineKey, nil
+	}
+
+	name := credentialsName
+	if name == "" {
+		name = DefaultCredentialsName
+	}
+	apiKey, exists := s.credentials.apiKey(name)
+	if !exists && credentialsName != "" {
+		return "", fmt.Errorf("credentials %q are not set", credentialsName)
+	}
+	return apiKey, nil
+}
+
+// resolveResumeTask returns the task whose Claude session a new task continues,
+// which is the most recent task if continueSession is set without a task ID
+func (s *AgentServiceServer) resolveResumeTask(resumeTaskID string, continueSession bool) (string, error) {
+	if resumeTaskID == "" && !continueSession {
+		return "", nil
+	}
+
+	taskID, err := s.taskManager.ResolveTaskID(resumeTaskID)
+	if err != nil {
+		return "", fmt.Errorf("no task to continue: %w", err)
+	}
+	return taskID, nil
+}
+
+// taskTimeouts returns the timeouts of a new task, falling back to the daemon
+// defaults for limits the request does not set
+func (s *AgentServiceServer) taskTimeouts(timeoutSeconds, idleTimeoutSeconds int32) (time.Duration, time.Duration) {
+	timeout := s.taskTimeout
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+
+	idleTimeout := s.idleTimeout
+	if idleTimeoutSeconds > 0 {
+		idleTimeout = time.Duration(idleTimeoutSeconds) * time.Second
+	}
+
+	return timeout, idleTimeout
+}
+
+// AttachTask replays the output of an existing task from an offset and follows it until the task finishes
+func (s *AgentServiceServer) AttachTask(req *proto.AttachTaskRequest, stream proto.AgentService_AttachTaskServer) error {
+	log.Printf("AgentService.AttachTask called for task: %s (offset %d)", req.TaskId, req.FromOffset)
+
+	taskID, err := s.taskManager.ResolveTaskID(req.TaskId)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	return s.taskManager.StreamTaskOutput(taskID, req.FromOffset, stream)
 }
 
 // GetTaskStatus returns the status of a specific task
@@ -192,12 +479,7 @@ func (s *AgentServiceServer) GetTaskStatus(ctx context.Context, req *proto.TaskS
 func (s *AgentServiceServer) ListTasks(ctx context.Context, req *proto.ListTasksRequest) (*proto.ListTasksResponse, error) {
 	log.Printf("AgentService.ListTasks called")
 
-	var stateFilter *proto.TaskStatusResponse_TaskState
-	if req.StateFilter != proto.TaskStatusResponse_PENDING || req.StateFilter != 0 {
-		stateFilter = &req.StateFilter
-	}
-
-	tasks, err := s.taskManager.ListTasks(stateFilter)
+	tasks, err := s.taskManager.ListTasks(req.StateFilter)
 	if err != nil {
 		log.Printf("Failed to list tasks: %v", err)
 		return &proto.ListTasksResponse{
@@ -209,3 +491,108 @@ func (s *AgentServiceServer) ListTasks(ctx context.Context, req *proto.ListTasks
 		Tasks: tasks,
 	}, nil
 }
+
+// CancelTask stops a running task and its child processes
+func (s *AgentServiceServer) CancelTask(ctx context.Context, req *proto.CancelTaskRequest) (*proto.CancelTaskResponse, error) {
+	log.Printf("AgentService.CancelTask called for task: %s", req.TaskId)
+
+	taskID, err := s.taskManager.ResolveTaskID(req.TaskId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	gracePeriod := time.Duration(req.GracePeriodSeconds) * time.Second
+	state, err := s.taskManager.CancelTask(taskID, gracePeriod)
+	if err != nil {
+		log.Printf("Failed to cancel task %s: %v", taskID, err)
+		return &proto.CancelTaskResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to cancel task: %v", err),
+			TaskId:  taskID,
+			State:   state,
+		}, nil
+	}
+
+	return &proto.CancelTaskResponse{
+		Success: true,
+		Message: fmt.Sprintf("Task %s cancelled", taskID),
+		TaskId:  taskID,
+		State:   state,
+	}, nil
+}
+
+// MoveTask moves a queued task to a new position in the task queue
+func (s *AgentServiceServer) MoveTask(ctx context.Context, req *proto.MoveTaskRequest) (*proto.MoveTaskResponse, error) {
+	log.Printf("AgentService.MoveTask called for task: %s (position %d)", req.TaskId, req.Position)
+
+	position, err := s.taskManager.MoveTask(req.TaskId, req.Position)
+	if err != nil {
+		log.Printf("Failed to move task %s: %v", req.TaskId, err)
+		return &proto.MoveTaskResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to move task: %v", err),
+		}, nil
+	}
+
+	return &proto.MoveTaskResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Task %s moved to queue position %d", req.TaskId, position),
+		QueuePosition: position,
+	}, nil
+}
+
+// GetWorkspaceDiff reports the files changed in the workspace since the commit
+// it started from, including uncommitted and untracked files
+func (s *AgentServiceServer) GetWorkspaceDiff(ctx context.Context, req *proto.WorkspaceDiffRequest) (*proto.WorkspaceDiffResponse, error) {
+	log.Printf("AgentService.GetWorkspaceDiff called for: %s (base %q)", req.WorkingDirectory, req.BaseRef)
+
+	dir := req.WorkingDirectory
+	if dir == "" {
+		dir = defaultProjectDir
+		if record, err := loadProjectRecord(projectRecordPath()); err == nil && record != nil && record.WorkingDir != "" {
+			dir = record.WorkingDir
+		}
+	}
+
+	diff, err := workspaceDiff(ctx, dir, req.BaseRef, req.IncludePatches)
+	if err != nil {
+		log.Printf("Failed to diff workspace %s: %v", dir, err)
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to diff workspace: %v", err)
+	}
+	return diff, nil
+}
+
+// DaemonInfo reports the build of the daemon, how long it has been running,
+// the Claude CLI it runs tasks with and its current load
+func (s *AgentServiceServer) DaemonInfo(ctx context.Context, req *proto.DaemonInfoRequest) (*proto.DaemonInfoResponse, error) {
+	log.Printf("AgentService.DaemonInfo called")
+
+	info := &proto.DaemonInfoResponse{
+		Version:       s.build.Version,
+		GitCommit:     s.build.GitCommit,
+		BuildTime:     s.build.BuildTime,
+		StartedAt:     s.startedAt.Unix(),
+		UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+		Workspace:     defaultProjectDir,
+	}
+
+	claudeVersion, err := s.claudeVersion.get(ctx)
+	if err != nil {
+		log.Printf("Warning: Could not determine the Claude CLI version: %v", err)
+	}
+	info.ClaudeVersion = claudeVersion
+
+	// The project was initialized in the workspace of the sandbox
+	if record, err := loadProjectRecord(projectRecordPath()); err == nil && record != nil && record.WorkingDir != "" {
+		info.Workspace = record.WorkingDir
+	}
+
+	running, queued, maxConcurrent, configured := s.taskManager.Load()
+	info.RunningTasks = int32(running)
+	info.QueuedTasks = int32(queued)
+	info.MaxConcurrentTasks = int32(maxConcurrent)
+	info.ClaudeConfigured = configured
+	info.Credentials = s.credentials.names()
+
+	return info, nil
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// DefaultListenAddress is where the daemon listens unless configured otherwise
+const DefaultListenAddress = ":28080"
+
+// unixPrefix marks a listen address as a unix socket path, as in unix:///run/dispensed.sock
+const unixPrefix = "unix://"
+
+// listen opens a listener for address, which is a TCP address such as
+// ":28080" or "127.0.0.1:28080", or a unix socket such as "unix:///run/dispensed.sock"
+func listen(address string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(address, unixPrefix); ok {
+		// A socket left behind by a daemon that did not stop cleanly
+		// would make the listen fail
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", address)
+}
+
+// DialTarget returns the gRPC target for reaching the daemon listening on
+// address from the same machine
+func DialTarget(address string) string {
+	if strings.HasPrefix(address, unixPrefix) {
+		return address
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"daemon/proto"
+)
+
+const (
+	// logHistorySize is the number of recent log entries kept for new streams
+	logHistorySize = 2000
+	// logSubscriberBufferSize is the number of log entries buffered per stream
+	logSubscriberBufferSize = 256
+	// logTimestampLayout is the timestamp prefix written by the standard logger
+	logTimestampLayout = "2006/01/02 15:04:05 "
+)
+
+// Task lifecycle events published on the log bus
+const (
+	taskEventCreated  = "created"
+	taskEventStarted  = "started"
+	taskEventOutput   = "output"
+	taskEventFinished = "finished"
+)
+
+// LogBus collects the daemon's log lines and task events, keeps the most
+// recent ones and fans them out to log streams. It is installed as the output
+// of the standard logger and passes everything through to out. Secrets are
+// masked in every entry before it is written or published.
+type LogBus struct {
+	out      io.Writer
+	outMutex sync.Mutex
+	redactor redactor
+
+	mutex       sync.Mutex
+	history     []*proto.LogsResponse
+	subscribers map[*logSubscriber]struct{}
+}
+
+// logSubscriber receives the log entries matching its filter
+type logSubscriber struct {
+	ch     chan *proto.LogsResponse
+	filter *proto.LogsRequest
+}
+
+// NewLogBus creates a log bus that also writes every entry to out
+func NewLogBus(out io.Writer) *LogBus {
+	return &LogBus{
+		out:         out,
+		subscribers: make(map[*logSubscriber]struct{}),
+	}
+}
+
+// Write implements io.Writer for the standard logger. Each call carries one
+// log line, which is published as a daemon log entry.
+func (b *LogBus) Write(p []byte) (int, error) {
+	line := b.Redact(string(p))
+	if _, err := b.writeOut([]byte(line)); err != nil {
+		return 0, err
+	}
+
+	message := strings.TrimRight(line, "\n")
+	if len(message) >= len(logTimestampLayout) {
+		if _, parseErr := time.Parse(logTimestampLayout, message[:len(logTimestampLayout)]); parseErr == nil {
+			message = message[len(logTimestampLayout):]
+		}
+	}
+
+	b.publish(&proto.LogsResponse{
+		LogEntry:  message,
+		Timestamp: time.Now().Unix(),
+		Level:     daemonLogLevel(message),
+	})
+
+	return len(p), nil
+}
+
+// AddSecret registers a value, such as an API key, that is masked wherever it
+// appears in the log
+func (b *LogBus) AddSecret(secret string) {
+	if b != nil {
+		b.redactor.add(secret)
+	}
+}
+
+// Redact masks the secrets in text, both the registered ones and anything
+// that looks like a secret
+func (b *LogBus) Redact(text string) string {
+	if b == nil {
+		var patternsOnly redactor
+		return patternsOnly.redact(text)
+	}
+	return b.redactor.redact(text)
+}
+
+// taskEvent logs and publishes a task lifecycle event
+func (b *LogBus) taskEvent(taskID, event string, level proto.LogsResponse_Level, format string, args ...interface{}) {
+	message := b.Redact(fmt.Sprintf(format, args...))
+	if b == nil {
+		log.Print(message)
+		return
+	}
+
+	now := time.Now()
+	b.writeOut([]byte(now.Format(logTimestampLayout) + message + "\n"))
+	b.publish(&proto.LogsResponse{
+		LogEntry:  message,
+		Timestamp: now.Unix(),
+		Level:     level,
+		TaskId:    taskID,
+		Event:     event,
+	})
+}
+
+// writeOut passes a log line through to the underlying output
+func (b *LogBus) writeOut(p []byte) (int, error) {
+	b.outMutex.Lock()
+	defer b.outMutex.Unlock()
+
+	return b.out.Write(p)
+}
+
+// publish records an entry and forwards it to every stream whose filter it matches
+func (b *LogBus) publish(entry *proto.LogsResponse) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.history = append(b.history, entry)
+	if len(b.history) > logHistorySize {
+		b.history = b.history[len(b.history)-logHistorySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !logEntryMatches(entry, sub.filter) {
+			continue
+		}
+		// Logs are best effort, a stream that falls behind misses entries
+		// rather than blocking the daemon
+		select {
+		case sub.ch <- entry:
+		default:
+		}
+	}
+}
+
+// subscribe returns the retained entries matching filter and, if the filter
+// asks to follow the log, a subscriber receiving new matching entries
+func (b *LogBus) subscribe(filter *proto.LogsRequest) (*logSubscriber, []*proto.LogsResponse) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var backlog []*proto.LogsResponse
+	for _, entry := range b.history {
+		if logEntryMatches(entry, filter) {
+			backlog = append(backlog, entry)
+		}
+	}
+
+	if !filter.Follow {
+		return nil, backlog
+	}
+
+	sub := &logSubscriber{
+		ch:     make(chan *proto.LogsResponse, logSubscriberBufferSize),
+		filter: filter,
+	}
+	b.subscribers[sub] = struct{}{}
+
+	return sub, backlog
+}
+
+// unsubscribe stops delivering entries to a subscriber
+func (b *LogBus) unsubscribe(sub *logSubscriber) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.subscribers, sub)
+}
+
+// logEntryMatches reports whether a log entry passes a stream's filter
+func logEntryMatches(entry *proto.LogsResponse, filter *proto.LogsRequest) bool {
+	if filter.TaskId != "" && entry.TaskId != filter.TaskId {
+		return false
+	}
+	if entry.Level < filter.MinLevel {
+		return false
+	}
+	if filter.Since > 0 && entry.Timestamp < filter.Since {
+		return false
+	}
+	return true
+}
+
+// daemonLogLevel guesses the level of a daemon log line from its wording
+func daemonLogLevel(message string) proto.LogsResponse_Level {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(lower, "warning"):
+		return proto.LogsResponse_WARN
+	case strings.HasPrefix(lower, "error") || strings.Contains(lower, "failed"):
+		return proto.LogsResponse_ERROR
+	default:
+		return proto.LogsResponse_INFO
+	}
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"daemon/proto"
+)
+
+func TestLogBusWritePublishesLines(t *testing.T) {
+	var out bytes.Buffer
+	bus := NewLogBus(&out)
+
+	fmt.Fprint(bus, "2024/05/01 10:00:00 Daemon started\n")
+	fmt.Fprint(bus, "2024/05/01 10:00:01 Warning: Failed to load task history\n")
+	fmt.Fprint(bus, "2024/05/01 10:00:02 Failed to start task claude_1\n")
+
+	if out.String() != "2024/05/01 10:00:00 Daemon started\n2024/05/01 10:00:01 Warning: Failed to load task history\n2024/05/01 10:00:02 Failed to start task claude_1\n" {
+		t.Errorf("lines were not passed through: %q", out.String())
+	}
+
+	_, backlog := bus.subscribe(&proto.LogsRequest{})
+	want := []struct {
+		entry string
+		level proto.LogsResponse_Level
+	}{
+		{"Daemon started", proto.LogsResponse_INFO},
+		{"Warning: Failed to load task history", proto.LogsResponse_WARN},
+		{"Failed to start task claude_1", proto.LogsResponse_ERROR},
+	}
+	if len(backlog) != len(want) {
+		t.Fatalf("published %d entries, want %d", len(backlog), len(want))
+	}
+	for i, entry := range backlog {
+		if entry.LogEntry != want[i].entry || entry.Level != want[i].level || entry.TaskId != "" {
+			t.Errorf("entry %d = %q (%s), want %q (%s)", i, entry.LogEntry, entry.Level, want[i].entry, want[i].level)
+		}
+	}
+}
+
+func TestLogBusSubscribeFilters(t *testing.T) {
+	bus := NewLogBus(&bytes.Buffer{})
+	bus.taskEvent("claude_1", taskEventStarted, proto.LogsResponse_INFO, "Task %s started", "claude_1")
+	bus.taskEvent("claude_2", taskEventFinished, proto.LogsResponse_ERROR, "Task %s failed", "claude_2")
+	fmt.Fprint(bus, "Daemon log line\n")
+
+	_, backlog := bus.subscribe(&proto.LogsRequest{TaskId: "claude_2"})
+	if len(backlog) != 1 || backlog[0].Event != taskEventFinished {
+		t.Errorf("task filter gave %+v", backlog)
+	}
+	_, backlog = bus.subscribe(&proto.LogsRequest{MinLevel: proto.LogsResponse_WARN})
+	if len(backlog) != 1 || backlog[0].TaskId != "claude_2" {
+		t.Errorf("level filter gave %+v", backlog)
+	}
+	_, backlog = bus.subscribe(&proto.LogsRequest{Since: time.Now().Add(time.Hour).Unix()})
+	if len(backlog) != 0 {
+		t.Errorf("since filter gave %+v", backlog)
+	}
+
+	// Without follow only the backlog is sent
+	if sub, _ := bus.subscribe(&proto.LogsRequest{}); sub != nil {
+		t.Error("subscribe() without follow returned a subscriber")
+	}
+
+	sub, _ := bus.subscribe(&proto.LogsRequest{TaskId: "claude_1", Follow: true})
+	defer bus.unsubscribe(sub)
+	bus.taskEvent("claude_2", taskEventOutput, proto.LogsResponse_INFO, "output of another task")
+	bus.taskEvent("claude_1", taskEventFinished, proto.LogsResponse_INFO, "Task claude_1 completed")
+	select {
+	case entry := <-sub.ch:
+		if entry.TaskId != "claude_1" || entry.Event != taskEventFinished {
+			t.Errorf("followed entry %+v", entry)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("followed entry was not delivered")
+	}
+}
+
+func TestLogBusDropsEntriesOfStalledSubscriber(t *testing.T) {
+	bus := NewLogBus(&bytes.Buffer{})
+	sub, _ := bus.subscribe(&proto.LogsRequest{Follow: true})
+	defer bus.unsubscribe(sub)
+
+	// A stream that stopped reading must not block the daemon's logging
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < logSubscriberBufferSize+10; i++ {
+			bus.publish(&proto.LogsResponse{LogEntry: fmt.Sprintf("line %d", i)})
+		}
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publish() blocked on a stalled subscriber")
+	}
+
+	if len(sub.ch) != logSubscriberBufferSize {
+		t.Errorf("subscriber buffered %d entries, want %d", len(sub.ch), logSubscriberBufferSize)
+	}
+	if first := <-sub.ch; first.LogEntry != "line 0" {
+		t.Errorf("first buffered entry %q, want the oldest one", first.LogEntry)
+	}
+
+	// The history keeps what the stream missed
+	_, backlog := bus.subscribe(&proto.LogsRequest{})
+	if len(backlog) != logSubscriberBufferSize+10 {
+		t.Errorf("history has %d entries, want %d", len(backlog), logSubscriberBufferSize+10)
+	}
+}
+
+func TestLogBusHistoryIsBounded(t *testing.T) {
+	bus := NewLogBus(&bytes.Buffer{})
+	for i := 0; i < logHistorySize+5; i++ {
+		bus.publish(&proto.LogsResponse{LogEntry: fmt.Sprintf("line %d", i)})
+	}
+
+	_, backlog := bus.subscribe(&proto.LogsRequest{})
+	if len(backlog) != logHistorySize || backlog[0].LogEntry != "line 5" {
+		t.Errorf("history has %d entries starting with %q, want %d starting with %q", len(backlog), backlog[0].LogEntry, logHistorySize, "line 5")
+	}
+}
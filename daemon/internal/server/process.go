@@ -0,0 +1,28 @@
+package server
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// configureProcessGroup runs the command in its own process group so that
+// signals reach the child shells Claude spawns as well as Claude itself
+func configureProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	// Context cancellation kills the whole group instead of only the leader
+	cmd.Cancel = func() error {
+		return signalProcessGroup(cmd, syscall.SIGKILL)
+	}
+}
+
+// signalProcessGroup sends a signal to every process in the command's process group
+func signalProcessGroup(cmd *exec.Cmd, sig syscall.Signal) error {
+	if cmd.Process == nil {
+		return fmt.Errorf("process not started")
+	}
+
+	// A negative PID addresses the process group led by the process
+	return syscall.Kill(-cmd.Process.Pid, sig)
+}
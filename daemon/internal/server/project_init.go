@@ -0,0 +1,415 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"daemon/proto"
+)
+
+// defaultProjectDir is the project directory used when Init does not name one
+const defaultProjectDir = "/workspace"
+
+// projectToolchain is a toolchain detected in a project with the commands
+// that install its dependencies, build it and test it
+type projectToolchain struct {
+	Name    string
+	Binary  string // Executable the commands need
+	Install []string
+	Build   []string
+	Test    []string
+}
+
+// projectDetector recognizes a toolchain from the files in a project directory
+// and returns nil if the project does not use it
+type projectDetector func(dir string) *projectToolchain
+
+// projectDetectors are tried in order, a project may use several toolchains
+var projectDetectors = []struct {
+	name   string
+	detect projectDetector
+}{
+	{"go", detectGoProject},
+	{"node", detectNodeProject},
+	{"python", detectPythonProject},
+	{"rust", detectRustProject},
+}
+
+// projectRecord is the on-disk record of the last project initialization,
+// read when starting Claude tasks so they know how to build and test the project
+type projectRecord struct {
+	WorkingDir      string    `json:"working_dir"`
+	Toolchains      []string  `json:"toolchains"`
+	SetupCommands   []string  `json:"setup_commands,omitempty"`
+	InstallCommands []string  `json:"install_commands,omitempty"`
+	BuildCommands   []string  `json:"build_commands,omitempty"`
+	TestCommands    []string  `json:"test_commands,omitempty"`
+	LintCommands    []string  `json:"lint_commands,omitempty"`
+	Success         bool      `json:"success"`
+	InitializedAt   time.Time `json:"initialized_at"`
+}
+
+// detectToolchains returns the toolchains used by the project in dir. If
+// projectType is set only that toolchain is considered.
+func detectToolchains(dir, projectType string) ([]*projectToolchain, error) {
+	var toolchains []*projectToolchain
+	known := false
+
+	for _, detector := range projectDetectors {
+		if projectType != "" && detector.name != projectType {
+			continue
+		}
+		known = true
+
+		if toolchain := detector.detect(dir); toolchain != nil {
+			toolchains = append(toolchains, toolchain)
+		}
+	}
+
+	if !known {
+		return nil, fmt.Errorf("unknown project type %q: use go, node, python or rust", projectType)
+	}
+	if projectType != "" && len(toolchains) == 0 {
+		return nil, fmt.Errorf("no %s project found in %s", projectType, dir)
+	}
+
+	return toolchains, nil
+}
+
+// detectGoProject recognizes Go modules
+func detectGoProject(dir string) *projectToolchain {
+	if !fileExists(dir, "go.mod") {
+		return nil
+	}
+
+	return &projectToolchain{
+		Name:    "go",
+		Binary:  "go",
+		Install: []string{"go mod download"},
+		Build:   []string{"go build ./..."},
+		Test:    []string{"go test ./..."},
+	}
+}
+
+// detectNodeProject recognizes Node.js packages, using the package manager
+// whose lockfile is present and the build and test scripts they define
+func detectNodeProject(dir string) *projectToolchain {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		log.Printf("Warning: Failed to parse package.json in %s: %v", dir, err)
+	}
+
+	toolchain := &projectToolchain{Name: "node"}
+	switch {
+	case fileExists(dir, "pnpm-lock.yaml"):
+		toolchain.Binary = "pnpm"
+		toolchain.Install = []string{"pnpm install --frozen-lockfile"}
+	case fileExists(dir, "yarn.lock"):
+		toolchain.Binary = "yarn"
+		toolchain.Install = []string{"yarn install --frozen-lockfile"}
+	case fileExists(dir, "bun.lockb") || fileExists(dir, "bun.lock"):
+		toolchain.Binary = "bun"
+		toolchain.Install = []string{"bun install --frozen-lockfile"}
+	case fileExists(dir, "package-lock.json"):
+		toolchain.Binary = "npm"
+		toolchain.Install = []string{"npm ci"}
+	default:
+		toolchain.Binary = "npm"
+		toolchain.Install = []string{"npm install"}
+	}
+
+	if _, ok := pkg.Scripts["build"]; ok {
+		toolchain.Build = []string{toolchain.Binary + " run build"}
+	}
+	// npm init generates a test script that only fails
+	if test, ok := pkg.Scripts["test"]; ok && !strings.Contains(test, "no test specified") {
+		toolchain.Test = []string{toolchain.Binary + " run test"}
+	}
+
+	return toolchain
+}
+
+// detectPythonProject recognizes Python projects managed by uv, Poetry or pip
+func detectPythonProject(dir string) *projectToolchain {
+	hasPyproject := fileExists(dir, "pyproject.toml")
+	hasRequirements := fileExists(dir, "requirements.txt")
+	if !hasPyproject && !hasRequirements && !fileExists(dir, "setup.py") {
+		return nil
+	}
+
+	toolchain := &projectToolchain{Name: "python"}
+	switch {
+	case fileExists(dir, "uv.lock"):
+		toolchain.Binary = "uv"
+		toolchain.Install = []string{"uv sync"}
+		toolchain.Test = []string{"uv run pytest"}
+	case fileExists(dir, "poetry.lock"):
+		toolchain.Binary = "poetry"
+		toolchain.Install = []string{"poetry install"}
+		toolchain.Test = []string{"poetry run pytest"}
+	default:
+		toolchain.Binary = "pip"
+		if hasRequirements {
+			toolchain.Install = append(toolchain.Install, "pip install -r requirements.txt")
+		}
+		if hasPyproject || fileExists(dir, "setup.py") {
+			toolchain.Install = append(toolchain.Install, "pip install -e .")
+		}
+		toolchain.Test = []string{"python -m pytest"}
+	}
+
+	return toolchain
+}
+
+// detectRustProject recognizes Cargo packages and workspaces
+func detectRustProject(dir string) *projectToolchain {
+	if !fileExists(dir, "Cargo.toml") {
+		return nil
+	}
+
+	return &projectToolchain{
+		Name:    "rust",
+		Binary:  "cargo",
+		Install: []string{"cargo fetch"},
+		Build:   []string{"cargo build"},
+		Test:    []string{"cargo test"},
+	}
+}
+
+// fileExists reports whether name exists in dir
+func fileExists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+// initProject runs the setup commands, then installs the dependencies of each
+// toolchain and builds the project, sending progress to send. A toolchain
+// whose dependencies fail to install is not built. The returned record lists
+// the commands of every toolchain, even if some of its steps failed.
+func initProject(ctx context.Context, dir string, setup []string, toolchains []*projectToolchain, send func(*proto.ProjectInitResponse) error) (*projectRecord, error) {
+	record := &projectRecord{
+		WorkingDir:    dir,
+		SetupCommands: setup,
+		Success:       true,
+		InitializedAt: time.Now(),
+	}
+
+	// Setup commands prepare the workspace, e.g. env files or services, so a
+	// failing one is reported but does not stop the others
+	for _, command := range setup {
+		ok, err := runProjectStep(ctx, dir, command, send)
+		if err != nil {
+			return record, err
+		}
+		if !ok {
+			record.Success = false
+		}
+	}
+
+	for _, toolchain := range toolchains {
+		record.Toolchains = append(record.Toolchains, toolchain.Name)
+		record.InstallCommands = append(record.InstallCommands, toolchain.Install...)
+		record.BuildCommands = append(record.BuildCommands, toolchain.Build...)
+		record.TestCommands = append(record.TestCommands, toolchain.Test...)
+
+		if _, err := exec.LookPath(toolchain.Binary); err != nil {
+			record.Success = false
+			if err := sendProjectInit(send, proto.ProjectInitResponse_ERROR, fmt.Sprintf("%s is not installed, skipping %s setup", toolchain.Binary, toolchain.Name)); err != nil {
+				return record, err
+			}
+			continue
+		}
+
+		steps := append(append([]string{}, toolchain.Install...), toolchain.Build...)
+		for i, command := range steps {
+			ok, err := runProjectStep(ctx, dir, command, send)
+			if err != nil {
+				return record, err
+			}
+			if ok {
+				continue
+			}
+
+			record.Success = false
+			if i < len(toolchain.Install) {
+				if err := sendProjectInit(send, proto.ProjectInitResponse_ERROR, fmt.Sprintf("Skipping the %s build, its dependencies could not be installed", toolchain.Name)); err != nil {
+					return record, err
+				}
+				break
+			}
+		}
+	}
+
+	return record, nil
+}
+
+// runProjectStep runs a setup command in dir and streams its combined output.
+// It reports whether the command succeeded, the error is only set if the
+// progress could not be sent.
+func runProjectStep(ctx context.Context, dir, command string, send func(*proto.ProjectInitResponse) error) (bool, error) {
+	log.Printf("Running project setup step in %s: %s", dir, command)
+	if err := sendProjectInit(send, proto.ProjectInitResponse_STATUS, "$ "+command); err != nil {
+		return false, err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	// Package managers skip interactive prompts and progress bars in CI
+	cmd.Env = append(os.Environ(), "CI=true")
+	configureProcessGroup(cmd)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return false, sendProjectInit(send, proto.ProjectInitResponse_ERROR, fmt.Sprintf("Failed to run %s: %v", command, err))
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return false, sendProjectInit(send, proto.ProjectInitResponse_ERROR, fmt.Sprintf("Failed to run %s: %v", command, err))
+	}
+
+	var sendErr error
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if sendErr != nil {
+			continue
+		}
+		sendErr = sendProjectInit(send, proto.ProjectInitResponse_OUTPUT, scanner.Text())
+	}
+
+	if err := cmd.Wait(); err != nil {
+		log.Printf("Project setup step failed: %s: %v", command, err)
+		if sendErr != nil {
+			return false, sendErr
+		}
+		return false, sendProjectInit(send, proto.ProjectInitResponse_ERROR, fmt.Sprintf("%s failed: %v", command, err))
+	}
+
+	return true, sendErr
+}
+
+// sendProjectInit sends a progress response of an initialization
+func sendProjectInit(send func(*proto.ProjectInitResponse) error, responseType proto.ProjectInitResponse_ResponseType, content string) error {
+	return send(&proto.ProjectInitResponse{
+		Type:      responseType,
+		Content:   content,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// info converts the record to its protobuf representation
+func (r *projectRecord) info() *proto.ProjectInfo {
+	return &proto.ProjectInfo{
+		WorkingDirectory: r.WorkingDir,
+		Toolchains:       r.Toolchains,
+		InstallCommands:  r.InstallCommands,
+		BuildCommands:    r.BuildCommands,
+		TestCommands:     r.TestCommands,
+		SetupCommands:    r.SetupCommands,
+		LintCommands:     r.LintCommands,
+	}
+}
+
+// projectRecordPath returns the file the last project initialization is recorded in
+func projectRecordPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".dispense", "project.json")
+}
+
+// saveProjectRecord records a project initialization, replacing the previous one
+func saveProjectRecord(path string, record *projectRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode project record: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// loadProjectRecord reads the recorded project initialization, returning nil
+// if the project was never initialized
+func loadProjectRecord(path string) (*projectRecord, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var record projectRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	return &record, nil
+}
+
+// projectPrompt describes the initialized project to Claude when a task runs
+// in it, so it knows the dependencies are installed and how to build and
+// test. It returns an empty string if no initialized project contains workingDir.
+func projectPrompt(workingDir string) string {
+	record, err := loadProjectRecord(projectRecordPath())
+	if err != nil {
+		log.Printf("Warning: Failed to load project record: %v", err)
+		return ""
+	}
+	if record == nil || (len(record.Toolchains) == 0 && len(record.BuildCommands) == 0 && len(record.TestCommands) == 0 && len(record.LintCommands) == 0) {
+		return ""
+	}
+
+	rel, err := filepath.Rel(record.WorkingDir, workingDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	var prompt strings.Builder
+	if len(record.Toolchains) > 0 {
+		fmt.Fprintf(&prompt, "The project in %s uses %s.", record.WorkingDir, strings.Join(record.Toolchains, ", "))
+		if record.Success {
+			prompt.WriteString(" Its dependencies are already installed.")
+		}
+	} else {
+		fmt.Fprintf(&prompt, "The project is in %s.", record.WorkingDir)
+	}
+	if len(record.BuildCommands) > 0 {
+		fmt.Fprintf(&prompt, " Build it with `%s`.", strings.Join(record.BuildCommands, "`, `"))
+	}
+	if len(record.TestCommands) > 0 {
+		fmt.Fprintf(&prompt, " Test it with `%s`.", strings.Join(record.TestCommands, "`, `"))
+	}
+	if len(record.LintCommands) > 0 {
+		fmt.Fprintf(&prompt, " Lint it with `%s`.", strings.Join(record.LintCommands, "`, `"))
+	}
+
+	return prompt.String()
+}
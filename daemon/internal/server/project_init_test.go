@@ -0,0 +1,95 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeProject creates a project directory holding the given files
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDetectToolchains(t *testing.T) {
+	goToolchain := &projectToolchain{Name: "go", Binary: "go", Install: []string{"go mod download"}, Build: []string{"go build ./..."}, Test: []string{"go test ./..."}}
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []*projectToolchain
+	}{
+		{"empty directory", nil, nil},
+		{"go module", map[string]string{"go.mod": "module app"}, []*projectToolchain{goToolchain}},
+		{
+			// npm init generates a test script that only fails
+			"npm package without a lockfile",
+			map[string]string{"package.json": `{"scripts":{"build":"tsc","test":"echo \"Error: no test specified\" && exit 1"}}`},
+			[]*projectToolchain{{Name: "node", Binary: "npm", Install: []string{"npm install"}, Build: []string{"npm run build"}}},
+		},
+		{
+			"pnpm package",
+			map[string]string{"package.json": `{"scripts":{"test":"vitest"}}`, "pnpm-lock.yaml": ""},
+			[]*projectToolchain{{Name: "node", Binary: "pnpm", Install: []string{"pnpm install --frozen-lockfile"}, Test: []string{"pnpm run test"}}},
+		},
+		{
+			"package.json that does not parse",
+			map[string]string{"package.json": "{", "package-lock.json": "{}"},
+			[]*projectToolchain{{Name: "node", Binary: "npm", Install: []string{"npm ci"}}},
+		},
+		{
+			"uv project",
+			map[string]string{"pyproject.toml": "", "uv.lock": ""},
+			[]*projectToolchain{{Name: "python", Binary: "uv", Install: []string{"uv sync"}, Test: []string{"uv run pytest"}}},
+		},
+		{
+			"pip project",
+			map[string]string{"requirements.txt": "", "setup.py": ""},
+			[]*projectToolchain{{Name: "python", Binary: "pip", Install: []string{"pip install -r requirements.txt", "pip install -e ."}, Test: []string{"python -m pytest"}}},
+		},
+		{
+			"go and node in one project",
+			map[string]string{"go.mod": "module app", "package.json": "{}", "yarn.lock": ""},
+			[]*projectToolchain{goToolchain, {Name: "node", Binary: "yarn", Install: []string{"yarn install --frozen-lockfile"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		toolchains, err := detectToolchains(writeProject(t, tt.files), "")
+		if err != nil {
+			t.Errorf("%s: detectToolchains() failed: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(toolchains, tt.want) {
+			t.Errorf("%s: detectToolchains() = %+v, want %+v", tt.name, toolchains, tt.want)
+		}
+	}
+}
+
+func TestDetectToolchainsOfProjectType(t *testing.T) {
+	dir := writeProject(t, map[string]string{"go.mod": "module app", "Cargo.toml": ""})
+
+	toolchains, err := detectToolchains(dir, "rust")
+	if err != nil {
+		t.Fatalf("detectToolchains() failed: %v", err)
+	}
+	if len(toolchains) != 1 || toolchains[0].Name != "rust" {
+		t.Errorf("detectToolchains() = %+v, want only the rust toolchain", toolchains)
+	}
+
+	if _, err := detectToolchains(dir, "python"); err == nil || !strings.Contains(err.Error(), "no python project found") {
+		t.Errorf("detectToolchains() of a missing project type error = %v", err)
+	}
+	if _, err := detectToolchains(dir, "cobol"); err == nil || !strings.Contains(err.Error(), `unknown project type "cobol"`) {
+		t.Errorf("detectToolchains() of an unknown project type error = %v", err)
+	}
+}
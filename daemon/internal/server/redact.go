@@ -0,0 +1,68 @@
+package server
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redactedText replaces secrets in log output
+const redactedText = "[REDACTED]"
+
+// minSecretLength is the length below which a registered value is not
+// redacted, as short values would mask ordinary words
+const minSecretLength = 8
+
+// secretPatterns match values that look like secrets. The first group is
+// kept so that the kind of secret is still visible in the log.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(sk-ant-)[A-Za-z0-9_-]{8,}`),
+	regexp.MustCompile(`\b(sk-)[A-Za-z0-9_-]{20,}`),
+	regexp.MustCompile(`\b(gh[pousr]_)[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`\b(github_pat_)[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`\b(AKIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]{16,}`),
+	regexp.MustCompile(`(?i)(basic\s+)[A-Za-z0-9+/=]{16,}`),
+	regexp.MustCompile(`(?i)((?:api[_-]?key|token|secret|password)["']?\s*[:=]\s*["']?)[^\s"',]{8,}`),
+}
+
+// redactor masks secrets in text: values known to be secrets, such as the
+// stored API keys, and anything matching secretPatterns
+type redactor struct {
+	mutex   sync.RWMutex
+	secrets []string
+}
+
+// add registers a value that is always masked
+func (r *redactor) add(secret string) {
+	if len(secret) < minSecretLength {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, known := range r.secrets {
+		if known == secret {
+			return
+		}
+	}
+	r.secrets = append(r.secrets, secret)
+	// Longer secrets first, so one containing another is masked completely
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+}
+
+// redact returns text with every secret in it masked
+func (r *redactor) redact(text string) string {
+	r.mutex.RLock()
+	for _, secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, redactedText)
+	}
+	r.mutex.RUnlock()
+
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+redactedText)
+	}
+	return text
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"daemon/proto"
+)
+
+const (
+	// maxToolResultSize is the longest tool result kept in a task event
+	maxToolResultSize = 8 * 1024
+	// maxSummaryLength is the longest one-line summary shown for an event
+	maxSummaryLength = 120
+)
+
+// streamEvent is a single line of Claude's stream-json output
+type streamEvent struct {
+	Type      string `json:"type"`
+	Subtype   string `json:"subtype"`
+	SessionID string `json:"session_id"`
+	Model     string `json:"model"`
+	Message   struct {
+		Content []streamContentBlock `json:"content"`
+	} `json:"message"`
+
+	// Fields of the final "result" event
+	IsError      bool         `json:"is_error"`
+	DurationMs   int64        `json:"duration_ms"`
+	NumTurns     int32        `json:"num_turns"`
+	Result       string       `json:"result"`
+	TotalCostUSD float64      `json:"total_cost_usd"`
+	Usage        *streamUsage `json:"usage"`
+}
+
+// streamContentBlock is a content block of an assistant or user message
+type streamContentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+	IsError   bool            `json:"is_error"`
+}
+
+// streamUsage is the token usage reported by Claude
+type streamUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+// parseStreamJSONLine converts a line of Claude's stream-json output into
+// typed task responses. It reports false if the line is not a stream-json
+// event, in which case it should be treated as plain output.
+func parseStreamJSONLine(line, workingDir string) ([]*proto.ExecuteClaudeResponse, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+
+	var event streamEvent
+	if err := json.Unmarshal([]byte(trimmed), &event); err != nil || event.Type == "" {
+		return nil, false
+	}
+
+	var responses []*proto.ExecuteClaudeResponse
+	switch event.Type {
+	case "system":
+		if event.Subtype != "init" {
+			return nil, true
+		}
+		responses = append(responses, &proto.ExecuteClaudeResponse{
+			Type:    proto.ExecuteClaudeResponse_SYSTEM,
+			Content: fmt.Sprintf("Session %s started with model %s", event.SessionID, event.Model),
+			Event: &proto.ClaudeEvent{
+				SessionId: event.SessionID,
+				Model:     event.Model,
+			},
+		})
+
+	case "assistant":
+		for _, block := range event.Message.Content {
+			switch block.Type {
+			case "text":
+				if strings.TrimSpace(block.Text) == "" {
+					continue
+				}
+				responses = append(responses, &proto.ExecuteClaudeResponse{
+					Type:    proto.ExecuteClaudeResponse_ASSISTANT,
+					Content: block.Text,
+					Event: &proto.ClaudeEvent{
+						SessionId: event.SessionID,
+						Text:      block.Text,
+					},
+				})
+			case "tool_use":
+				responses = append(responses, &proto.ExecuteClaudeResponse{
+					Type:    proto.ExecuteClaudeResponse_TOOL_USE,
+					Content: summarizeToolUse(block.Name, block.Input, workingDir),
+					Event: &proto.ClaudeEvent{
+						SessionId: event.SessionID,
+						ToolUse: &proto.ClaudeToolUse{
+							Id:        block.ID,
+							Name:      block.Name,
+							InputJson: string(block.Input),
+						},
+					},
+				})
+			}
+		}
+
+	case "user":
+		for _, block := range event.Message.Content {
+			if block.Type != "tool_result" {
+				continue
+			}
+			content := toolResultText(block.Content)
+			summary := "Tool finished"
+			if block.IsError {
+				summary = "Tool failed: " + firstLine(content)
+			}
+			responses = append(responses, &proto.ExecuteClaudeResponse{
+				Type:    proto.ExecuteClaudeResponse_TOOL_RESULT,
+				Content: summary,
+				Event: &proto.ClaudeEvent{
+					SessionId: event.SessionID,
+					ToolResult: &proto.ClaudeToolResult{
+						ToolUseId: block.ToolUseID,
+						Content:   truncate(content, maxToolResultSize),
+						IsError:   block.IsError,
+					},
+				},
+			})
+		}
+
+	case "result":
+		if event.Usage != nil || event.TotalCostUSD > 0 {
+			usage := &proto.ClaudeUsage{TotalCostUsd: event.TotalCostUSD}
+			if event.Usage != nil {
+				usage.InputTokens = event.Usage.InputTokens
+				usage.OutputTokens = event.Usage.OutputTokens
+				usage.CacheCreationInputTokens = event.Usage.CacheCreationInputTokens
+				usage.CacheReadInputTokens = event.Usage.CacheReadInputTokens
+			}
+			responses = append(responses, &proto.ExecuteClaudeResponse{
+				Type: proto.ExecuteClaudeResponse_USAGE,
+				Content: fmt.Sprintf("Tokens: %d input, %d output, %d cache write, %d cache read - cost $%.4f",
+					usage.InputTokens, usage.OutputTokens, usage.CacheCreationInputTokens, usage.CacheReadInputTokens, usage.TotalCostUsd),
+				Event: &proto.ClaudeEvent{
+					SessionId: event.SessionID,
+					Usage:     usage,
+				},
+			})
+		}
+		responses = append(responses, &proto.ExecuteClaudeResponse{
+			Type:    proto.ExecuteClaudeResponse_RESULT,
+			Content: event.Result,
+			Event: &proto.ClaudeEvent{
+				SessionId: event.SessionID,
+				Text:      event.Result,
+				Result: &proto.ClaudeResult{
+					Subtype:    event.Subtype,
+					IsError:    event.IsError,
+					DurationMs: event.DurationMs,
+					NumTurns:   event.NumTurns,
+				},
+			},
+		})
+	}
+
+	return responses, true
+}
+
+// summarizeToolUse describes a tool invocation in a few words, e.g. "Editing main.go"
+func summarizeToolUse(name string, rawInput json.RawMessage, workingDir string) string {
+	var input map[string]interface{}
+	json.Unmarshal(rawInput, &input)
+
+	str := func(key string) string {
+		value, _ := input[key].(string)
+		return value
+	}
+	path := func(key string) string {
+		value := str(key)
+		if rel, err := filepath.Rel(workingDir, value); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return value
+	}
+
+	var summary string
+	switch name {
+	case "Edit", "MultiEdit":
+		summary = "Editing " + path("file_path")
+	case "Write":
+		summary = "Writing " + path("file_path")
+	case "Read":
+		summary = "Reading " + path("file_path")
+	case "NotebookEdit":
+		summary = "Editing " + path("notebook_path")
+	case "Bash":
+		summary = "Running " + firstLine(str("command"))
+	case "Grep":
+		summary = fmt.Sprintf("Searching for %q", str("pattern"))
+	case "Glob":
+		summary = "Finding files matching " + str("pattern")
+	case "LS":
+		summary = "Listing " + path("path")
+	case "WebFetch":
+		summary = "Fetching " + str("url")
+	case "WebSearch":
+		summary = fmt.Sprintf("Searching the web for %q", str("query"))
+	case "TodoWrite":
+		summary = "Updating the todo list"
+	case "Task":
+		summary = "Starting a subagent: " + str("description")
+	default:
+		summary = "Using " + name
+	}
+
+	return truncate(summary, maxSummaryLength)
+}
+
+// toolResultText extracts the text of a tool result, which is either a
+// string or a list of content blocks
+func toolResultText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var blocks []streamContentBlock
+	if err := json.Unmarshal(raw, &blocks); err == nil {
+		var parts []string
+		for _, block := range blocks {
+			if block.Type == "text" {
+				parts = append(parts, block.Text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+
+	return string(raw)
+}
+
+// firstLine returns the first line of s, shortened to the summary length
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " ..."
+	}
+	return truncate(s, maxSummaryLength)
+}
+
+// truncate shortens s to at most max bytes without splitting a character
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max] + "... (truncated)"
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"testing"
+
+	"daemon/proto"
+)
+
+// parseEvent parses a stream-json line that must be recognized as an event
+func parseEvent(t *testing.T, line string) []*proto.ExecuteClaudeResponse {
+	t.Helper()
+	responses, ok := parseStreamJSONLine(line, "/workspace")
+	if !ok {
+		t.Fatalf("parseStreamJSONLine(%q) did not recognize the event", line)
+	}
+	return responses
+}
+
+func TestParseStreamJSONLineKeepsPlainOutput(t *testing.T) {
+	for _, line := range []string{
+		"Compiling main.go",
+		`{"type": "assistant"`,
+		`{"level": "info"}`,
+	} {
+		if responses, ok := parseStreamJSONLine(line, "/workspace"); ok || responses != nil {
+			t.Errorf("parseStreamJSONLine(%q) = %v, %v, want plain output", line, responses, ok)
+		}
+	}
+}
+
+func TestParseStreamJSONLineSessionStart(t *testing.T) {
+	responses := parseEvent(t, `{"type":"system","subtype":"init","session_id":"s1","model":"claude-sonnet"}`)
+	if len(responses) != 1 {
+		t.Fatalf("got %d responses, want 1", len(responses))
+	}
+	resp := responses[0]
+	if resp.Type != proto.ExecuteClaudeResponse_SYSTEM || resp.Content != "Session s1 started with model claude-sonnet" {
+		t.Errorf("got %s %q", resp.Type, resp.Content)
+	}
+	if resp.Event.SessionId != "s1" || resp.Event.Model != "claude-sonnet" {
+		t.Errorf("event %+v", resp.Event)
+	}
+
+	// Other system events carry nothing to show
+	if responses := parseEvent(t, `{"type":"system","subtype":"compact","session_id":"s1"}`); len(responses) != 0 {
+		t.Errorf("compact event gave %d responses", len(responses))
+	}
+}
+
+func TestParseStreamJSONLineAssistantMessage(t *testing.T) {
+	responses := parseEvent(t, `{"type":"assistant","session_id":"s1","message":{"content":[
+		{"type":"text","text":"Let me fix it."},
+		{"type":"text","text":"  "},
+		{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/workspace/cmd/main.go"}},
+		{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./...\ngo vet ./..."}}]}}`)
+
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want the text and both tool uses without the blank text", len(responses))
+	}
+	if responses[0].Type != proto.ExecuteClaudeResponse_ASSISTANT || responses[0].Event.Text != "Let me fix it." {
+		t.Errorf("text response %s %+v", responses[0].Type, responses[0].Event)
+	}
+
+	// Paths in the workspace are shown relative to it, commands by their first line
+	edit, bash := responses[1], responses[2]
+	if edit.Type != proto.ExecuteClaudeResponse_TOOL_USE || edit.Content != "Editing cmd/main.go" {
+		t.Errorf("edit response %s %q", edit.Type, edit.Content)
+	}
+	if use := edit.Event.ToolUse; use.Id != "t1" || use.Name != "Edit" || use.InputJson != `{"file_path":"/workspace/cmd/main.go"}` {
+		t.Errorf("edit tool use %+v", use)
+	}
+	if bash.Content != "Running go test ./... ..." {
+		t.Errorf("bash summary %q", bash.Content)
+	}
+}
+
+func TestParseStreamJSONLineToolResults(t *testing.T) {
+	responses := parseEvent(t, `{"type":"user","session_id":"s1","message":{"content":[
+		{"type":"text","text":"ignored"},
+		{"type":"tool_result","tool_use_id":"t1","content":"ok"},
+		{"type":"tool_result","tool_use_id":"t2","is_error":true,"content":[{"type":"text","text":"FAIL main\nexit 1"}]}]}}`)
+
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want one per tool result", len(responses))
+	}
+	if responses[0].Content != "Tool finished" || responses[0].Event.ToolResult.Content != "ok" {
+		t.Errorf("first result %q %+v", responses[0].Content, responses[0].Event.ToolResult)
+	}
+
+	failed := responses[1]
+	if failed.Content != "Tool failed: FAIL main ..." {
+		t.Errorf("failed result summary %q", failed.Content)
+	}
+	if result := failed.Event.ToolResult; result.ToolUseId != "t2" || !result.IsError || result.Content != "FAIL main\nexit 1" {
+		t.Errorf("failed result %+v", result)
+	}
+}
+
+func TestParseStreamJSONLineResult(t *testing.T) {
+	responses := parseEvent(t, `{"type":"result","subtype":"success","session_id":"s1","result":"Done.","duration_ms":1500,"num_turns":3,
+		"total_cost_usd":0.25,"usage":{"input_tokens":100,"output_tokens":50,"cache_read_input_tokens":7}}`)
+
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want usage and result", len(responses))
+	}
+	usage := responses[0]
+	if usage.Type != proto.ExecuteClaudeResponse_USAGE || usage.Content != "Tokens: 100 input, 50 output, 0 cache write, 7 cache read - cost $0.2500" {
+		t.Errorf("usage response %s %q", usage.Type, usage.Content)
+	}
+	result := responses[1]
+	if result.Type != proto.ExecuteClaudeResponse_RESULT || result.Content != "Done." {
+		t.Errorf("result response %s %q", result.Type, result.Content)
+	}
+	if r := result.Event.Result; r.Subtype != "success" || r.IsError || r.DurationMs != 1500 || r.NumTurns != 3 {
+		t.Errorf("result event %+v", r)
+	}
+
+	// Runs that end before Claude reports usage only give the result
+	responses = parseEvent(t, `{"type":"result","subtype":"error_max_turns","is_error":true,"session_id":"s1"}`)
+	if len(responses) != 1 || !responses[0].Event.Result.IsError {
+		t.Errorf("result without usage gave %+v", responses)
+	}
+}
@@ -9,56 +9,277 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"daemon/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // Task represents a running Claude task
 type Task struct {
-	ID            string
-	Prompt        string
-	WorkingDir    string
-	StartedAt     time.Time
-	FinishedAt    *time.Time
-	State         proto.TaskStatusResponse_TaskState
-	ExitCode      *int32
-	Error         *string
-	Process       *exec.Cmd
-	LogFile       *os.File
-	StdoutPipe    io.ReadCloser
-	StderrPipe    io.ReadCloser
-	cancel        context.CancelFunc
-	ctx           context.Context
+	ID         string
+	Prompt     string
+	Model      string
+	WorkingDir string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	State      proto.TaskStatusResponse_TaskState
+	ExitCode   *int32
+	Error      *string
+	Process    *exec.Cmd
+	LogFile    *os.File
+	LogPath    string
+	StdoutPipe io.ReadCloser
+	StderrPipe io.ReadCloser
+	cancel     context.CancelFunc
+	ctx        context.Context
+
+	// SessionID is the Claude session the task runs in and ResumeTaskID the
+	// task whose session it continues
+	SessionID    string
+	ResumeTaskID string
+
+	// Usage is the token usage and cost Claude reported at the end of the run
+	Usage *proto.ClaudeUsage
+
+	// Result is the final message Claude reported at the end of the run
+	Result string
+
+	// Timeout limits the total runtime of the task and IdleTimeout the time
+	// it may go without output, zero means no limit
+	Timeout       time.Duration
+	IdleTimeout   time.Duration
+	timeoutReason string
+
+	// VerifyCommands run once Claude exits successfully, Verification holds
+	// the results of those that have finished
+	VerifyCommands []string
+	Verification   []*proto.VerificationResult
+
+	// MaxFixAttempts is the number of follow-up Claude runs started to fix
+	// failed verification commands. A fix attempt is a task of its own that
+	// records its ParentTaskID and Attempt number, the parent lists its fix
+	// attempts in FixTaskIDs.
+	MaxFixAttempts int
+	ParentTaskID   string
+	Attempt        int
+	FixTaskIDs     []string
+
+	// run starts the Claude process once the task leaves the queue
+	run func()
+
+	// done is closed once the task has finished and its log is complete
+	done            chan struct{}
+	cancelRequested bool
+
+	// outputMutex guards the log file writes and the set of attached streams
+	outputMutex sync.Mutex
+	subscribers map[*taskSubscriber]struct{}
+	finished    bool
+	logOffset   int64
+	lastOutput  time.Time
+}
+
+// taskOutputStream is a server stream that task output can be sent to
+type taskOutputStream interface {
+	Send(*proto.ExecuteClaudeResponse) error
+	Context() context.Context
 }
 
+// taskSubscriber receives task output lines as they are produced
+type taskSubscriber struct {
+	ch chan *proto.ExecuteClaudeResponse
+
+	// dropped is set when the subscriber fell behind the output and its
+	// channel was closed before the task finished (guarded by outputMutex)
+	dropped bool
+}
+
+const (
+	// subscriberBufferSize is the number of output lines buffered per attached stream, a stream that falls further behind is dropped
+	subscriberBufferSize = 256
+	// defaultCancelGracePeriod is how long a cancelled task gets to exit after each signal
+	defaultCancelGracePeriod = 5 * time.Second
+	// DefaultMaxConcurrentTasks is the number of tasks run at once unless configured otherwise
+	DefaultMaxConcurrentTasks = 1
+	// timeoutCheckInterval is how often running tasks are checked against their timeouts
+	timeoutCheckInterval = time.Second
+)
+
 // TaskManager manages Claude execution tasks
 type TaskManager struct {
 	tasks  map[string]*Task
 	mutex  sync.RWMutex
 	logDir string
+	store  *taskStore
+	logs   *LogBus
+
+	// queue holds the IDs of pending tasks in the order they will run
+	queue         []string
+	maxConcurrent int
+	running       int
+
+	// claudeConfigured is set once every Claude config command succeeded.
+	// configuredDirs holds the working directories Claude was configured for,
+	// configureMutex serializes the configuration, which runs outside mutex.
+	claudeConfigured bool
+	configuredDirs   map[string]bool
+	configureMutex   sync.Mutex
+
+	// shuttingDown is set once the daemon stops, queued tasks are not started anymore
+	shuttingDown bool
 }
 
-// NewTaskManager creates a new task manager
-func NewTaskManager(logDir string) *TaskManager {
+// NewTaskManager creates a new task manager that runs at most maxConcurrent
+// tasks at once and queues the rest. Task records are persisted in taskDir
+// and reloaded from it, so task history survives daemon restarts. Task events
+// are published on logs.
+func NewTaskManager(logDir, taskDir string, maxConcurrent int, logs *LogBus) *TaskManager {
 	// Create log directory if it doesn't exist
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		log.Printf("Warning: Failed to create log directory %s: %v", logDir, err)
 	}
 
-	return &TaskManager{
-		tasks:  make(map[string]*Task),
-		logDir: logDir,
+	if maxConcurrent < 1 {
+		maxConcurrent = DefaultMaxConcurrentTasks
+	}
+
+	tm := &TaskManager{
+		tasks:         make(map[string]*Task),
+		logDir:        logDir,
+		logs:          logs,
+		maxConcurrent: maxConcurrent,
+	}
+
+	store, err := newTaskStore(taskDir)
+	if err != nil {
+		log.Printf("Warning: Task history will not be persisted: %v", err)
+		return tm
+	}
+	tm.store = store
+	tm.loadTasks()
+
+	return tm
+}
+
+// loadTasks restores the task history from the task store. Tasks that were
+// still running when the previous daemon stopped are marked as interrupted.
+func (tm *TaskManager) loadTasks() {
+	records, err := tm.store.load()
+	if err != nil {
+		log.Printf("Warning: Failed to load task history: %v", err)
+		return
+	}
+
+	for _, record := range records {
+		task := taskFromRecord(record)
+
+		if task.State == proto.TaskStatusResponse_RUNNING || task.State == proto.TaskStatusResponse_PENDING {
+			interruptTask(task)
+			tm.persistTask(task)
+		}
+
+		tm.tasks[task.ID] = task
 	}
+
+	log.Printf("Loaded %d tasks from %s", len(records), tm.store.dir)
 }
 
-// StartClaudeTask starts a new Claude execution task
-func (tm *TaskManager) StartClaudeTask(prompt, workingDir, apiKey, model string, envVars map[string]string) (string, error) {
+// interruptTask marks a task whose daemon stopped while it was running as interrupted
+func interruptTask(task *Task) {
+	errorMsg := "daemon stopped while the task was running"
+	exitCode := int32(-1)
+
+	// The log file was last written when the task stopped producing output
+	finishedAt := time.Now()
+	if info, err := os.Stat(task.LogPath); err == nil {
+		finishedAt = info.ModTime()
+
+		if logFile, err := os.OpenFile(task.LogPath, os.O_APPEND|os.O_WRONLY, 0644); err == nil {
+			n, _ := fmt.Fprintf(logFile, "[%s] [STATUS] Task interrupted by daemon restart\n", time.Now().Format(time.RFC3339))
+			task.logOffset += int64(n)
+			logFile.Close()
+		}
+	}
+
+	task.State = proto.TaskStatusResponse_INTERRUPTED
+	task.ExitCode = &exitCode
+	task.Error = &errorMsg
+	task.FinishedAt = &finishedAt
+
+	log.Printf("Task %s was interrupted by a daemon restart", task.ID)
+}
+
+// persistTask saves the task record to the task store (helper method - assumes mutex is already held)
+func (tm *TaskManager) persistTask(task *Task) {
+	if tm.store == nil {
+		return
+	}
+
+	if err := tm.store.save(newTaskRecord(task)); err != nil {
+		log.Printf("Warning: Failed to persist task %s: %v", task.ID, err)
+	}
+}
+
+// StartClaudeTask queues a new Claude execution task. The task starts right
+// away if fewer than the maximum number of tasks are running. If resumeTaskID
+// is set, Claude continues the session of that task instead of starting a new
+// conversation. A running task is stopped once it runs longer than timeout or
+// produces no output for idleTimeout, a zero duration disables either limit.
+// Once Claude exits successfully, verifyCommands run in the working directory
+// and the task fails verification if any of them fails. Up to maxFixAttempts
+// follow-up runs are then started to fix the failures.
+func (tm *TaskManager) StartClaudeTask(prompt, workingDir, apiKey, model string, envVars map[string]string, resumeTaskID string, timeout, idleTimeout time.Duration, verifyCommands []string, maxFixAttempts int) (string, error) {
+	if resumeTaskID != "" {
+		tm.mutex.RLock()
+		resumeTask, exists := tm.tasks[resumeTaskID]
+		tm.mutex.RUnlock()
+		if !exists {
+			return "", fmt.Errorf("task to resume not found: %s", resumeTaskID)
+		}
+		// Claude keeps sessions per project directory, so resume in the same one
+		if workingDir == "" {
+			workingDir = resumeTask.WorkingDir
+		}
+	}
+
+	// Set working directory (default to /workspace if not specified)
+	if workingDir == "" {
+		workingDir = "/workspace"
+	}
+
 	tm.mutex.Lock()
 	defer tm.mutex.Unlock()
 
+	task := &Task{
+		Prompt:         prompt,
+		Model:          model,
+		WorkingDir:     workingDir,
+		ResumeTaskID:   resumeTaskID,
+		Timeout:        timeout,
+		IdleTimeout:    idleTimeout,
+		VerifyCommands: verifyCommands,
+		MaxFixAttempts: maxFixAttempts,
+	}
+
+	if err := tm.queueTask(task, apiKey, envVars); err != nil {
+		return "", err
+	}
+
+	return task.ID, nil
+}
+
+// queueTask sets up the log and Claude process of a new task and adds it to
+// the queue. Fix attempts go to the front of the queue so they run before
+// tasks submitted later (helper method - assumes mutex is already held).
+func (tm *TaskManager) queueTask(task *Task, apiKey string, envVars map[string]string) error {
 	// Generate task ID
 	taskID := fmt.Sprintf("claude_%d", time.Now().UnixNano())
 
@@ -71,39 +292,32 @@ func (tm *TaskManager) StartClaudeTask(prompt, workingDir, apiKey, model string,
 	logFile, err := os.Create(logFilePath)
 	if err != nil {
 		cancel()
-		return "", fmt.Errorf("failed to create log file: %w", err)
+		return fmt.Errorf("failed to create log file: %w", err)
 	}
 
 	// Set working directory (default to /workspace if not specified)
-	if workingDir == "" {
-		workingDir = "/workspace"
-	}
-
-	// Configure Claude on first run
-	if err := tm.ensureClaudeConfigured(workingDir); err != nil {
-		logFile.Close()
-		cancel()
-		return "", fmt.Errorf("failed to configure Claude: %w", err)
-	}
-
-	// Create task (mark as running initially)
-	task := &Task{
-		ID:         taskID,
-		Prompt:     prompt,
-		WorkingDir: workingDir,
-		StartedAt:  time.Now(),
-		State:      proto.TaskStatusResponse_RUNNING,
-		LogFile:    logFile,
-		cancel:     cancel,
-		ctx:        ctx,
+	if task.WorkingDir == "" {
+		task.WorkingDir = "/workspace"
 	}
+	prompt, workingDir, model, resumeTaskID := task.Prompt, task.WorkingDir, task.Model, task.ResumeTaskID
+
+	// Task is pending until it leaves the queue
+	task.ID = taskID
+	task.StartedAt = time.Now()
+	task.State = proto.TaskStatusResponse_PENDING
+	task.LogFile = logFile
+	task.LogPath = logFilePath
+	task.cancel = cancel
+	task.ctx = ctx
+	task.done = make(chan struct{})
 
 	// Store task
 	tm.tasks[taskID] = task
 
-	log.Printf("Starting Claude task %s with prompt: %s", taskID, prompt)
+	tm.logs.taskEvent(taskID, taskEventCreated, proto.LogsResponse_INFO, "Queueing Claude task %s with prompt: %s", taskID, prompt)
 	if apiKey != "" {
-		log.Printf("API key provided: length %d, starts with: %.10s...", len(apiKey), apiKey)
+		tm.logs.AddSecret(apiKey)
+		log.Printf("API key provided")
 	} else {
 		log.Printf("No API key provided")
 	}
@@ -113,17 +327,31 @@ func (tm *TaskManager) StartClaudeTask(prompt, workingDir, apiKey, model string,
 		log.Printf("No model specified")
 	}
 
-	// Execute Claude synchronously and log output
-	go func() {
-		defer func() {
-			logFile.Close()
-			tm.completeTask(taskID)
-			cancel() // Cancel context to signal completion
-		}()
+	// Execute Claude and stream its output event by event
+	task.run = func() {
+		defer cancel() // Cancel context to signal completion
+
+		// Configure Claude on the first run in the working directory. This happens once the task
+		// leaves the queue rather than when it is queued, as fix attempts are queued with the lock held.
+		configErr := tm.ensureClaudeConfigured(workingDir)
+
+		// Prepare Claude command, printing structured events rather than plain text
+		args := []string{"--dangerously-skip-permissions", "--print", "--output-format", "stream-json", "--verbose"}
+
+		// The session to resume is looked up now, the resumed task may still have been running when this one was queued
+		sessionID, err := tm.resumeSessionID(resumeTaskID)
+		if sessionID != "" {
+			log.Printf("Task %s resumes Claude session %s of task %s", taskID, sessionID, resumeTaskID)
+			args = append(args, "--resume", sessionID)
+		}
+		if note := projectPrompt(workingDir); note != "" {
+			args = append(args, "--append-system-prompt", note)
+		}
+		args = append(args, prompt)
 
-		// Prepare Claude command for synchronous execution
-		cmd := exec.CommandContext(ctx, "claude", "--dangerously-skip-permissions", prompt)
+		cmd := exec.CommandContext(ctx, "claude", args...)
 		cmd.Dir = workingDir
+		configureProcessGroup(cmd)
 
 		// Set environment variables
 		cmd.Env = os.Environ()
@@ -148,68 +376,233 @@ func (tm *TaskManager) StartClaudeTask(prompt, workingDir, apiKey, model string,
 			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 		}
 
-		// Execute and capture output
-		output, err := cmd.CombinedOutput()
-		timestamp := time.Now().Format(time.RFC3339)
-
+		if err == nil && configErr != nil {
+			err = fmt.Errorf("failed to configure Claude: %w", configErr)
+		}
+		if err == nil {
+			err = tm.startProcess(task, cmd)
+		}
 		if err != nil {
-			// Log error
-			logEntry := fmt.Sprintf("[%s] [ERROR] Claude execution failed: %v\n", timestamp, err)
-			logFile.WriteString(logEntry)
-			log.Printf("Task %s [ERROR]: Claude execution failed: %v", taskID, err)
-
-			errorMsg := err.Error()
-
-			// Mark task as failed
-			tm.mutex.Lock()
-			if task, exists := tm.tasks[taskID]; exists {
-				task.State = proto.TaskStatusResponse_FAILED
-				exitCode := int32(1)
-				task.ExitCode = &exitCode
-				task.Error = &errorMsg
-				finishedAt := time.Now()
-				task.FinishedAt = &finishedAt
-			}
-			tm.mutex.Unlock()
+			tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_ERROR, "ERROR", fmt.Sprintf("Claude execution failed: %v", err))
+			tm.failTask(task, err)
 		} else {
-			// Log successful output
-			outputStr := string(output)
-			if outputStr != "" {
-				logEntry := fmt.Sprintf("[%s] [STDOUT] %s\n", timestamp, outputStr)
-				logFile.WriteString(logEntry)
-				log.Printf("Task %s [STDOUT]: %s", taskID, outputStr)
+			go tm.watchTimeouts(task)
+
+			// Pipes must be fully drained before waiting on the process
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				tm.streamAndLogPipe(task, task.StdoutPipe, proto.ExecuteClaudeResponse_STDOUT, "STDOUT")
+			}()
+			go func() {
+				defer wg.Done()
+				tm.streamAndLogPipe(task, task.StderrPipe, proto.ExecuteClaudeResponse_STDERR, "STDERR")
+			}()
+			wg.Wait()
+
+			tm.monitorProcess(task)
+
+			// A task with verification commands is still running once Claude succeeded
+			tm.mutex.RLock()
+			verify := task.State == proto.TaskStatusResponse_RUNNING
+			tm.mutex.RUnlock()
+			if verify {
+				tm.verifyTask(task, apiKey, envVars)
 			}
+		}
 
-			// Mark task as completed
-			tm.mutex.Lock()
-			if task, exists := tm.tasks[taskID]; exists {
-				task.State = proto.TaskStatusResponse_COMPLETED
-				exitCode := int32(0)
-				task.ExitCode = &exitCode
-				finishedAt := time.Now()
-				task.FinishedAt = &finishedAt
-			}
-			tm.mutex.Unlock()
+		tm.completeTask(taskID)
+		tm.releaseSlot()
+	}
+
+	if task.ParentTaskID != "" {
+		tm.queue = append([]string{taskID}, tm.queue...)
+	} else {
+		tm.queue = append(tm.queue, taskID)
+	}
+	tm.persistTask(task)
+	tm.scheduleTasks()
+
+	if position := tm.queuePosition(taskID); position > 0 {
+		log.Printf("Task %s queued at position %d", taskID, position)
+		tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", fmt.Sprintf("Task queued at position %d", position))
+	}
+
+	return nil
+}
+
+// resumeSessionID returns the Claude session a task resuming resumeTaskID continues
+func (tm *TaskManager) resumeSessionID(resumeTaskID string) (string, error) {
+	if resumeTaskID == "" {
+		return "", nil
+	}
+
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+
+	resumeTask, exists := tm.tasks[resumeTaskID]
+	if !exists {
+		return "", fmt.Errorf("task to resume not found: %s", resumeTaskID)
+	}
+	if resumeTask.SessionID == "" {
+		return "", fmt.Errorf("task %s has no Claude session to resume", resumeTaskID)
+	}
+
+	return resumeTask.SessionID, nil
+}
+
+// setSessionID records the Claude session a task runs in
+func (tm *TaskManager) setSessionID(task *Task, sessionID string) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if task.SessionID == sessionID {
+		return
+	}
+	task.SessionID = sessionID
+	tm.persistTask(task)
+}
+
+// setUsage records the token usage and cost of a task
+func (tm *TaskManager) setUsage(task *Task, usage *proto.ClaudeUsage) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	task.Usage = usage
+	tm.persistTask(task)
+}
+
+// setResult records the final message Claude reported at the end of a task
+func (tm *TaskManager) setResult(task *Task, result string) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	task.Result = tm.logs.Redact(result)
+	tm.persistTask(task)
+}
+
+// scheduleTasks starts queued tasks until the concurrency limit is reached (helper method - assumes mutex is already held)
+func (tm *TaskManager) scheduleTasks() {
+	for !tm.shuttingDown && tm.running < tm.maxConcurrent && len(tm.queue) > 0 {
+		taskID := tm.queue[0]
+		tm.queue = tm.queue[1:]
+
+		task, exists := tm.tasks[taskID]
+		if !exists || task.State != proto.TaskStatusResponse_PENDING {
+			continue
 		}
 
-		// Log completion
-		completionEntry := fmt.Sprintf("[%s] [STATUS] Task completed with exit code %d\n",
-			time.Now().Format(time.RFC3339),
-			func() int32 {
-				if err != nil { return 1 }
-				return 0
-			}())
-		logFile.WriteString(completionEntry)
-		logFile.Sync()
-	}()
+		task.State = proto.TaskStatusResponse_RUNNING
+		task.StartedAt = time.Now()
+		tm.running++
+		tm.persistTask(task)
+
+		tm.logs.taskEvent(taskID, taskEventStarted, proto.LogsResponse_INFO, "Starting Claude task %s (%d/%d running)", taskID, tm.running, tm.maxConcurrent)
+		go task.run()
+	}
+}
+
+// releaseSlot frees the concurrency slot of a finished task and starts the next queued task
+func (tm *TaskManager) releaseSlot() {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	tm.running--
+	tm.scheduleTasks()
+}
 
-	return taskID, nil
+// queuePosition returns the 1-based queue position of a task, or 0 if it is not queued (helper method - assumes mutex is already held)
+func (tm *TaskManager) queuePosition(taskID string) int32 {
+	for i, id := range tm.queue {
+		if id == taskID {
+			return int32(i + 1)
+		}
+	}
+	return 0
 }
 
-// completeTask handles task completion logging
+// MoveTask moves a queued task to a new 1-based position in the queue and
+// returns the position it ended up at
+func (tm *TaskManager) MoveTask(taskID string, position int32) (int32, error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	current := tm.queuePosition(taskID)
+	if current == 0 {
+		if _, exists := tm.tasks[taskID]; !exists {
+			return 0, fmt.Errorf("task not found: %s", taskID)
+		}
+		return 0, fmt.Errorf("task %s is not queued", taskID)
+	}
+
+	if position < 1 {
+		position = 1
+	}
+	if position > int32(len(tm.queue)) {
+		position = int32(len(tm.queue))
+	}
+
+	queue := append(tm.queue[:current-1:current-1], tm.queue[current:]...)
+	queue = append(queue[:position-1], append([]string{taskID}, queue[position-1:]...)...)
+	tm.queue = queue
+
+	log.Printf("Moved task %s from queue position %d to %d", taskID, current, position)
+	return position, nil
+}
+
+// startProcess wires the task's output pipes and starts the Claude process
+func (tm *TaskManager) startProcess(task *Task, cmd *exec.Cmd) error {
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start claude: %w", err)
+	}
+
+	tm.mutex.Lock()
+	task.Process = cmd
+	task.StdoutPipe = stdoutPipe
+	task.StderrPipe = stderrPipe
+	tm.mutex.Unlock()
+
+	log.Printf("Claude process for task %s started with PID %d", task.ID, cmd.Process.Pid)
+	return nil
+}
+
+// failTask marks a task as failed without an exit status from the process
+func (tm *TaskManager) failTask(task *Task, err error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	errorMsg := err.Error()
+	exitCode := int32(1)
+	finishedAt := time.Now()
+
+	task.State = proto.TaskStatusResponse_FAILED
+	if task.cancelRequested {
+		task.State = proto.TaskStatusResponse_CANCELLED
+	}
+	task.ExitCode = &exitCode
+	task.Error = &errorMsg
+	task.FinishedAt = &finishedAt
+}
+
+// completeTask handles task completion logging and releases attached streams
 func (tm *TaskManager) completeTask(taskID string) {
 	tm.mutex.RLock()
 	task, exists := tm.tasks[taskID]
+	var exitCode int32
+	if exists && task.ExitCode != nil {
+		exitCode = *task.ExitCode
+	}
 	tm.mutex.RUnlock()
 
 	if !exists {
@@ -217,44 +610,55 @@ func (tm *TaskManager) completeTask(taskID string) {
 		return
 	}
 
-	// Log completion to file if it's still open
+	tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", fmt.Sprintf("Task completed with exit code %d", exitCode))
+
+	task.outputMutex.Lock()
+	task.finished = true
+	for sub := range task.subscribers {
+		close(sub.ch)
+	}
+	task.subscribers = nil
 	if task.LogFile != nil {
-		logEntry := fmt.Sprintf("[%s] [STATUS] Task %s completed\n", time.Now().Format(time.RFC3339), taskID)
-		task.LogFile.WriteString(logEntry)
 		task.LogFile.Sync()
+		task.LogFile.Close()
 	}
+	task.outputMutex.Unlock()
 
-	log.Printf("Task %s completed", taskID)
-}
-
-// logOutput logs output from Claude process to file (kept for compatibility but not used in simplified version)
-func (tm *TaskManager) logOutput(task *Task, pipe io.ReadCloser, streamType string) {
-	defer pipe.Close()
-
-	scanner := bufio.NewScanner(pipe)
-	for scanner.Scan() {
-		line := scanner.Text()
-		timestamp := time.Now().Format(time.RFC3339)
-
-		// Write to log file
-		logEntry := fmt.Sprintf("[%s] [%s] %s\n", timestamp, streamType, line)
-		task.LogFile.WriteString(logEntry)
-		task.LogFile.Sync() // Ensure data is written to disk
+	tm.mutex.RLock()
+	tm.persistTask(task)
+	state := task.State
+	tm.mutex.RUnlock()
 
-		// Log to daemon logs as well
-		log.Printf("Task %s [%s]: %s", task.ID, streamType, line)
-	}
+	close(task.done)
 
-	if err := scanner.Err(); err != nil {
-		log.Printf("Error reading %s for task %s: %v", streamType, task.ID, err)
+	level := proto.LogsResponse_INFO
+	switch state {
+	case proto.TaskStatusResponse_FAILED, proto.TaskStatusResponse_TIMED_OUT, proto.TaskStatusResponse_VERIFICATION_FAILED:
+		level = proto.LogsResponse_ERROR
+	case proto.TaskStatusResponse_CANCELLED:
+		level = proto.LogsResponse_WARN
 	}
+	tm.logs.taskEvent(taskID, taskEventFinished, level, "Task %s completed with state %s", taskID, state)
 }
 
-// monitorProcess monitors the Claude process completion
+// monitorProcess waits for the Claude process to exit and records its result
 func (tm *TaskManager) monitorProcess(task *Task) {
 	// Wait for process to complete
 	err := task.Process.Wait()
 
+	tm.mutex.RLock()
+	cancelled := task.cancelRequested
+	timeoutReason := task.timeoutReason
+	tm.mutex.RUnlock()
+
+	if cancelled {
+		tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", "Task cancelled")
+	} else if timeoutReason != "" {
+		tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", "Task timed out")
+	} else if err != nil {
+		tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_ERROR, "ERROR", fmt.Sprintf("Claude execution failed: %v", err))
+	}
+
 	tm.mutex.Lock()
 	defer tm.mutex.Unlock()
 
@@ -262,34 +666,44 @@ func (tm *TaskManager) monitorProcess(task *Task) {
 	now := time.Now()
 	task.FinishedAt = &now
 
-	if err != nil {
+	if cancelled || timeoutReason != "" {
+		errorMsg := "task cancelled"
+		task.State = proto.TaskStatusResponse_CANCELLED
+		if !cancelled {
+			errorMsg = timeoutReason
+			task.State = proto.TaskStatusResponse_TIMED_OUT
+		}
+		task.Error = &errorMsg
+
+		exitCode := int32(-1)
+		if exitError, ok := err.(*exec.ExitError); ok {
+			exitCode = int32(exitError.ExitCode())
+		} else if err == nil {
+			exitCode = 0
+		}
+		task.ExitCode = &exitCode
+	} else if err != nil {
+		errorMsg := err.Error()
+		task.Error = &errorMsg
+		task.State = proto.TaskStatusResponse_FAILED
+
 		if exitError, ok := err.(*exec.ExitError); ok {
 			exitCode := int32(exitError.ExitCode())
 			task.ExitCode = &exitCode
-			task.State = proto.TaskStatusResponse_FAILED
 		} else {
 			// Process was likely killed or failed to start
 			exitCode := int32(-1)
 			task.ExitCode = &exitCode
-			task.State = proto.TaskStatusResponse_FAILED
 		}
 	} else {
 		exitCode := int32(0)
 		task.ExitCode = &exitCode
 		task.State = proto.TaskStatusResponse_COMPLETED
-	}
-
-	// Close log file
-	task.LogFile.Close()
-
-	// Write completion message to log file
-	logEntry := fmt.Sprintf("[%s] [STATUS] Task completed with exit code %d\n",
-		time.Now().Format(time.RFC3339), *task.ExitCode)
-
-	// Reopen file in append mode to write completion message
-	if logFile, err := os.OpenFile(task.LogFile.Name(), os.O_APPEND|os.O_WRONLY, 0644); err == nil {
-		logFile.WriteString(logEntry)
-		logFile.Close()
+		if len(task.VerifyCommands) > 0 {
+			// The task keeps running until its verification commands have finished
+			task.State = proto.TaskStatusResponse_RUNNING
+			task.FinishedAt = nil
+		}
 	}
 
 	log.Printf("Task %s completed with exit code %d", task.ID, *task.ExitCode)
@@ -310,37 +724,7 @@ func (tm *TaskManager) GetTaskStatus(taskID string) (*proto.TaskStatusResponse,
 		return nil, fmt.Errorf("task not found: %s", taskID)
 	}
 
-	response := &proto.TaskStatusResponse{
-		State:            task.State,
-		StartedAt:        task.StartedAt.Unix(),
-		Prompt:           task.Prompt,
-		WorkingDirectory: task.WorkingDir,
-	}
-
-	if task.FinishedAt != nil {
-		response.FinishedAt = task.FinishedAt.Unix()
-	}
-
-	if task.ExitCode != nil {
-		response.ExitCode = *task.ExitCode
-	}
-
-	if task.Error != nil {
-		response.Error = *task.Error
-	}
-
-	switch task.State {
-	case proto.TaskStatusResponse_RUNNING:
-		response.Message = "Task is currently running"
-	case proto.TaskStatusResponse_COMPLETED:
-		response.Message = "Task completed successfully"
-	case proto.TaskStatusResponse_FAILED:
-		response.Message = "Task failed"
-	default:
-		response.Message = "Task status unknown"
-	}
-
-	return response, nil
+	return tm.taskStatusResponse(task), nil
 }
 
 // getLatestTaskStatus returns the status of the most recent task (helper method - assumes mutex is already held)
@@ -364,42 +748,92 @@ func (tm *TaskManager) getLatestTaskStatus() (*proto.TaskStatusResponse, error)
 		}, nil
 	}
 
-	// Build response for the latest task
+	return tm.taskStatusResponse(latestTask), nil
+}
+
+// taskStatusResponse describes a task for GetTaskStatus and ListTasks (helper method - assumes mutex is already held)
+func (tm *TaskManager) taskStatusResponse(task *Task) *proto.TaskStatusResponse {
 	response := &proto.TaskStatusResponse{
-		State:            latestTask.State,
-		StartedAt:        latestTask.StartedAt.Unix(),
-		Prompt:           latestTask.Prompt,
-		WorkingDirectory: latestTask.WorkingDir,
+		State:            task.State,
+		StartedAt:        task.StartedAt.Unix(),
+		Prompt:           task.Prompt,
+		WorkingDirectory: task.WorkingDir,
+		QueuePosition:    tm.queuePosition(task.ID),
+		SessionId:        task.SessionID,
+		ResumeTaskId:     task.ResumeTaskID,
+		Usage:            task.Usage,
+		Result:           task.Result,
+		Model:            task.Model,
+		Verification:     task.Verification,
+		ParentTaskId:     task.ParentTaskID,
+		Attempt:          int32(task.Attempt),
+		FixTaskIds:       task.FixTaskIDs,
 	}
 
-	if latestTask.FinishedAt != nil {
-		response.FinishedAt = latestTask.FinishedAt.Unix()
+	if task.FinishedAt != nil {
+		response.FinishedAt = task.FinishedAt.Unix()
 	}
 
-	if latestTask.ExitCode != nil {
-		response.ExitCode = *latestTask.ExitCode
+	if task.ExitCode != nil {
+		response.ExitCode = *task.ExitCode
 	}
 
-	if latestTask.Error != nil {
-		response.Error = *latestTask.Error
+	if task.Error != nil {
+		response.Error = *task.Error
 	}
 
-	switch latestTask.State {
+	switch task.State {
+	case proto.TaskStatusResponse_PENDING:
+		response.Message = fmt.Sprintf("Task is queued at position %d", response.QueuePosition)
 	case proto.TaskStatusResponse_RUNNING:
 		response.Message = "Task is currently running"
 	case proto.TaskStatusResponse_COMPLETED:
 		response.Message = "Task completed successfully"
 	case proto.TaskStatusResponse_FAILED:
 		response.Message = "Task failed"
+	case proto.TaskStatusResponse_CANCELLED:
+		response.Message = "Task was cancelled"
+	case proto.TaskStatusResponse_INTERRUPTED:
+		response.Message = "Task was interrupted by a daemon restart"
+	case proto.TaskStatusResponse_TIMED_OUT:
+		response.Message = "Task timed out and was stopped"
+	case proto.TaskStatusResponse_VERIFICATION_FAILED:
+		response.Message = "Task finished but its verification failed"
 	default:
 		response.Message = "Task status unknown"
 	}
 
-	return response, nil
+	return response
 }
 
-// StreamTaskOutput streams the output of a Claude task (simplified synchronous version)
-func (tm *TaskManager) StreamTaskOutput(taskID string, stream proto.AgentService_ExecuteClaudeServer) error {
+// taskInfo describes a task for ListTasks with the same fields as its status (helper method - assumes mutex is already held)
+func (tm *TaskManager) taskInfo(task *Task) *proto.TaskInfo {
+	status := tm.taskStatusResponse(task)
+	return &proto.TaskInfo{
+		TaskId:           task.ID,
+		Prompt:           status.Prompt,
+		State:            status.State,
+		StartedAt:        status.StartedAt,
+		FinishedAt:       status.FinishedAt,
+		ExitCode:         status.ExitCode,
+		Error:            status.Error,
+		WorkingDirectory: status.WorkingDirectory,
+		QueuePosition:    status.QueuePosition,
+		SessionId:        status.SessionId,
+		ResumeTaskId:     status.ResumeTaskId,
+		Usage:            status.Usage,
+		Model:            status.Model,
+		Verification:     status.Verification,
+		ParentTaskId:     status.ParentTaskId,
+		Attempt:          status.Attempt,
+		FixTaskIds:       status.FixTaskIds,
+		Result:           status.Result,
+	}
+}
+
+// StreamTaskOutput replays the output a task has produced from the given log
+// offset and then follows new output until the task finishes
+func (tm *TaskManager) StreamTaskOutput(taskID string, fromOffset int64, stream taskOutputStream) error {
 	tm.mutex.RLock()
 	task, exists := tm.tasks[taskID]
 	tm.mutex.RUnlock()
@@ -408,122 +842,536 @@ func (tm *TaskManager) StreamTaskOutput(taskID string, stream proto.AgentService
 		return fmt.Errorf("task not found: %s", taskID)
 	}
 
-	// Wait for task to complete
+	sub, backlog, err := task.subscribe(fromOffset)
+	if err != nil {
+		return err
+	}
+	defer task.unsubscribe(sub)
+
+	for _, resp := range backlog {
+		if err := stream.Send(resp); err != nil {
+			log.Printf("Error sending stream data: %v", err)
+			return err
+		}
+	}
+
 	for {
 		select {
 		case <-stream.Context().Done():
 			return stream.Context().Err()
-		case <-task.ctx.Done():
-			// Task completed, send all output at once and final status
-			tm.mutex.RLock()
-			currentTask, exists := tm.tasks[taskID]
-			tm.mutex.RUnlock()
+		case resp, ok := <-sub.ch:
+			if !ok {
+				task.outputMutex.Lock()
+				dropped := sub.dropped
+				task.outputMutex.Unlock()
+				if dropped {
+					return status.Errorf(codes.Unavailable, "stream fell behind the output of task %s, reattach from the last offset received", task.ID)
+				}
 
-			if !exists {
-				return fmt.Errorf("task not found during streaming: %s", taskID)
+				// Task finished, send final completion status
+				tm.mutex.RLock()
+				var exitCode int32
+				if task.ExitCode != nil {
+					exitCode = *task.ExitCode
+				}
+				tm.mutex.RUnlock()
+
+				task.outputMutex.Lock()
+				offset := task.logOffset
+				task.outputMutex.Unlock()
+
+				return stream.Send(&proto.ExecuteClaudeResponse{
+					Type:       proto.ExecuteClaudeResponse_STATUS,
+					Content:    "Task completed",
+					Timestamp:  time.Now().Unix(),
+					ExitCode:   exitCode,
+					IsFinished: true,
+					Offset:     offset,
+					TaskId:     task.ID,
+				})
+			}
+
+			if err := stream.Send(resp); err != nil {
+				log.Printf("Error sending stream data: %v", err)
+				return err
+			}
+		}
+	}
+}
+
+// ResolveTaskID returns the given task ID if the task exists, or the ID of the
+// most recent task when taskID is empty
+func (tm *TaskManager) ResolveTaskID(taskID string) (string, error) {
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+
+	if taskID != "" {
+		if _, exists := tm.tasks[taskID]; !exists {
+			return "", fmt.Errorf("task not found: %s", taskID)
+		}
+		return taskID, nil
+	}
+
+	var latestTask *Task
+	for _, task := range tm.tasks {
+		if latestTask == nil || task.StartedAt.After(latestTask.StartedAt) {
+			latestTask = task
+		}
+	}
+
+	if latestTask == nil {
+		return "", fmt.Errorf("no tasks found")
+	}
+
+	return latestTask.ID, nil
+}
+
+// streamAndLogPipe reads output from a pipe line by line, logging each line to
+// the task log file and forwarding it to every attached stream. Stream-json
+// events on stdout are converted into typed entries.
+func (tm *TaskManager) streamAndLogPipe(task *Task, pipe io.ReadCloser, responseType proto.ExecuteClaudeResponse_ResponseType, streamType string) {
+	defer pipe.Close()
+
+	// stream-json puts whole tool results on one line, so lines are not length limited
+	reader := bufio.NewReader(pipe)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			line = strings.TrimRight(line, "\r\n")
+
+			var events []*proto.ExecuteClaudeResponse
+			var ok bool
+			if responseType == proto.ExecuteClaudeResponse_STDOUT {
+				events, ok = parseStreamJSONLine(line, task.WorkingDir)
 			}
 
-			// Read and send the log file content (if available)
-			if currentTask.LogFile != nil {
-				logFileName := currentTask.LogFile.Name()
-				if content, err := os.ReadFile(logFileName); err == nil {
-					lines := strings.Split(string(content), "\n")
-					for _, line := range lines {
-						if strings.TrimSpace(line) == "" {
-							continue
-						}
-
-						// Parse log entry and determine type
-						responseType := proto.ExecuteClaudeResponse_STDOUT
-						content := line
-						if strings.Contains(line, "[ERROR]") {
-							responseType = proto.ExecuteClaudeResponse_ERROR
-						} else if strings.Contains(line, "[STATUS]") {
-							responseType = proto.ExecuteClaudeResponse_STATUS
-						}
-
-						if err := stream.Send(&proto.ExecuteClaudeResponse{
-							Type:      responseType,
-							Content:   content,
-							Timestamp: time.Now().Unix(),
-						}); err != nil {
-							log.Printf("Error sending stream data: %v", err)
-							return err
-						}
+			if ok {
+				for _, event := range events {
+					if event.Event != nil && event.Event.SessionId != "" {
+						tm.setSessionID(task, event.Event.SessionId)
 					}
+					if event.Event != nil && event.Event.Usage != nil {
+						tm.setUsage(task, event.Event.Usage)
+					}
+					if event.Type == proto.ExecuteClaudeResponse_RESULT && event.Content != "" {
+						tm.setResult(task, event.Content)
+					}
+					tm.writeTaskEntry(task, event)
 				}
+			} else {
+				tm.writeTaskOutput(task, responseType, streamType, line)
 			}
+		}
 
-			// Send final completion status
-			var exitCode int32
-			if currentTask.ExitCode != nil {
-				exitCode = *currentTask.ExitCode
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("Error reading %s for task %s: %v", streamType, task.ID, err)
 			}
+			return
+		}
+	}
+}
+
+// writeTaskOutput appends a line to the task log file and forwards it to attached streams
+func (tm *TaskManager) writeTaskOutput(task *Task, responseType proto.ExecuteClaudeResponse_ResponseType, streamType, line string) {
+	tm.writeTaskEntry(task, &proto.ExecuteClaudeResponse{
+		Type:    responseType,
+		Content: line,
+	})
+}
+
+// writeTaskEntry appends a response to the task log file and forwards it to
+// attached streams. Typed events are logged as a one-line summary followed by
+// a tab and the event encoded as JSON, so the log stays readable while the
+// event can be restored from it.
+func (tm *TaskManager) writeTaskEntry(task *Task, resp *proto.ExecuteClaudeResponse) {
+	timestamp := time.Now()
+
+	task.outputMutex.Lock()
+	defer task.outputMutex.Unlock()
+
+	if task.finished {
+		return
+	}
+
+	// Secrets are masked once, so neither the log file, the daemon logs nor
+	// the attached streams ever see them
+	resp.Content = tm.logs.Redact(resp.Content)
+	tm.redactEvent(resp.Event)
+
+	streamType := resp.Type.String()
+	line := resp.Content
+	if resp.Event != nil {
+		line = flattenLine(resp.Content)
+		payload, err := protojson.Marshal(&proto.ExecuteClaudeResponse{Content: resp.Content, Event: resp.Event})
+		if err != nil {
+			log.Printf("Failed to encode %s event for task %s: %v", streamType, task.ID, err)
+		} else {
+			line += "\t" + string(payload)
+		}
+	}
+
+	task.lastOutput = timestamp
+
+	// Write to log file
+	logEntry := fmt.Sprintf("[%s] [%s] %s\n", timestamp.Format(time.RFC3339), streamType, line)
+	if task.LogFile != nil {
+		n, _ := task.LogFile.WriteString(logEntry)
+		task.logOffset += int64(n)
+	}
+
+	// Log to daemon logs as well
+	level := proto.LogsResponse_INFO
+	if resp.Type == proto.ExecuteClaudeResponse_ERROR {
+		level = proto.LogsResponse_ERROR
+	}
+	if resp.Event != nil {
+		tm.logs.taskEvent(task.ID, taskEventOutput, level, "Task %s [%s]: %s", task.ID, streamType, flattenLine(resp.Content))
+	} else {
+		tm.logs.taskEvent(task.ID, taskEventOutput, level, "Task %s [%s]: %s", task.ID, streamType, resp.Content)
+	}
 
-			return stream.Send(&proto.ExecuteClaudeResponse{
-				Type:       proto.ExecuteClaudeResponse_STATUS,
-				Content:    "Task completed",
-				Timestamp:  time.Now().Unix(),
-				ExitCode:   exitCode,
-				IsFinished: true,
-			})
+	resp.Timestamp = timestamp.Unix()
+	resp.Offset = task.logOffset
+	resp.TaskId = task.ID
+	for sub := range task.subscribers {
+		select {
+		case sub.ch <- resp:
 		default:
-			time.Sleep(100 * time.Millisecond)
+			// A stream that stopped reading must not hold up Claude's output, so it is
+			// dropped and its client reattaches from the last offset it received
+			log.Printf("Dropping a stream of task %s that fell behind its output", task.ID)
+			sub.dropped = true
+			close(sub.ch)
+			delete(task.subscribers, sub)
 		}
 	}
 }
 
-// streamAndLogPipe streams output from a pipe to gRPC stream and logs to file
-func (tm *TaskManager) streamAndLogPipe(task *Task, pipe io.ReadCloser, responseType proto.ExecuteClaudeResponse_ResponseType, streamType string, stream proto.AgentService_ExecuteClaudeServer) {
-	defer pipe.Close()
+// redactEvent masks the secrets in the text an event carries
+func (tm *TaskManager) redactEvent(event *proto.ClaudeEvent) {
+	if event == nil {
+		return
+	}
+	event.Text = tm.logs.Redact(event.Text)
+	if event.ToolUse != nil {
+		event.ToolUse.InputJson = tm.logs.Redact(event.ToolUse.InputJson)
+	}
+	if event.ToolResult != nil {
+		event.ToolResult.Content = tm.logs.Redact(event.ToolResult.Content)
+	}
+}
 
-	scanner := bufio.NewScanner(pipe)
-	for scanner.Scan() {
-		line := scanner.Text()
-		timestamp := time.Now()
-
-		// Write to log file
-		logEntry := fmt.Sprintf("[%s] [%s] %s\n", timestamp.Format(time.RFC3339), streamType, line)
-		task.LogFile.WriteString(logEntry)
-		task.LogFile.Sync() // Ensure data is written to disk
-
-		// Log to daemon logs as well
-		log.Printf("Task %s [%s]: %s", task.ID, streamType, line)
-
-		// Send to gRPC stream
-		if err := stream.Send(&proto.ExecuteClaudeResponse{
-			Type:      responseType,
-			Content:   line,
-			Timestamp: timestamp.Unix(),
-		}); err != nil {
-			log.Printf("Error sending stream data: %v", err)
-			return
+// subscribe attaches a new subscriber to the task output. It returns the
+// output already written to the log file after fromOffset so the caller can
+// replay it before following live output; the subscriber channel is closed
+// once the task finishes.
+func (t *Task) subscribe(fromOffset int64) (*taskSubscriber, []*proto.ExecuteClaudeResponse, error) {
+	t.outputMutex.Lock()
+	defer t.outputMutex.Unlock()
+
+	var backlog []*proto.ExecuteClaudeResponse
+	if t.LogPath != "" {
+		content, err := os.ReadFile(t.LogPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read task log: %w", err)
+		}
+
+		if fromOffset < 0 || fromOffset > int64(len(content)) {
+			return nil, nil, fmt.Errorf("offset %d is outside of the task log (size %d)", fromOffset, len(content))
 		}
+
+		offset := fromOffset
+		for _, line := range strings.SplitAfter(string(content[fromOffset:]), "\n") {
+			offset += int64(len(line))
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			resp := parseLogEntry(strings.TrimSuffix(line, "\n"))
+			resp.Offset = offset
+			resp.TaskId = t.ID
+			backlog = append(backlog, resp)
+		}
+	}
+
+	sub := &taskSubscriber{
+		ch: make(chan *proto.ExecuteClaudeResponse, subscriberBufferSize),
+	}
+
+	if t.finished {
+		close(sub.ch)
+		return sub, backlog, nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Printf("Error reading %s for task %s: %v", streamType, task.ID, err)
+	if t.subscribers == nil {
+		t.subscribers = make(map[*taskSubscriber]struct{})
 	}
+	t.subscribers[sub] = struct{}{}
+
+	return sub, backlog, nil
 }
 
-// StopTask stops a running task
-func (tm *TaskManager) StopTask(taskID string) error {
-	tm.mutex.Lock()
-	defer tm.mutex.Unlock()
+// waitDone waits up to timeout for the task to finish and reports whether it did
+func (t *Task) waitDone(timeout time.Duration) bool {
+	select {
+	case <-t.done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// unsubscribe detaches a subscriber from the task output
+func (t *Task) unsubscribe(sub *taskSubscriber) {
+	t.outputMutex.Lock()
+	delete(t.subscribers, sub)
+	t.outputMutex.Unlock()
+}
+
+// parseLogEntry converts a "[timestamp] [TYPE] content" log line back into a stream response
+func parseLogEntry(line string) *proto.ExecuteClaudeResponse {
+	resp := &proto.ExecuteClaudeResponse{
+		Type:      proto.ExecuteClaudeResponse_STDOUT,
+		Content:   line,
+		Timestamp: time.Now().Unix(),
+	}
+
+	if !strings.HasPrefix(line, "[") {
+		return resp
+	}
+
+	tsEnd := strings.Index(line, "] [")
+	if tsEnd < 0 {
+		return resp
+	}
+	rest := line[tsEnd+3:]
+	typeEnd := strings.Index(rest, "] ")
+	if typeEnd < 0 {
+		return resp
+	}
+
+	if ts, err := time.Parse(time.RFC3339, line[1:tsEnd]); err == nil {
+		resp.Timestamp = ts.Unix()
+	}
+
+	responseType, ok := proto.ExecuteClaudeResponse_ResponseType_value[rest[:typeEnd]]
+	if !ok {
+		return resp
+	}
+	resp.Type = proto.ExecuteClaudeResponse_ResponseType(responseType)
+	resp.Content = rest[typeEnd+2:]
+
+	// Typed events carry their JSON encoding after the summary
+	if isTypedEvent(resp.Type) {
+		if tab := strings.IndexByte(resp.Content, '\t'); tab >= 0 {
+			var event proto.ExecuteClaudeResponse
+			if err := protojson.Unmarshal([]byte(resp.Content[tab+1:]), &event); err == nil {
+				resp.Content = event.Content
+				resp.Event = event.Event
+			} else {
+				resp.Content = resp.Content[:tab]
+			}
+		}
+	}
+
+	return resp
+}
+
+// isTypedEvent reports whether a response type carries a structured Claude event
+func isTypedEvent(responseType proto.ExecuteClaudeResponse_ResponseType) bool {
+	switch responseType {
+	case proto.ExecuteClaudeResponse_STDOUT, proto.ExecuteClaudeResponse_STDERR,
+		proto.ExecuteClaudeResponse_STATUS, proto.ExecuteClaudeResponse_ERROR:
+		return false
+	default:
+		return true
+	}
+}
 
+// flattenLine turns multi-line content into a single line summary for the task log
+func flattenLine(content string) string {
+	return truncate(strings.Join(strings.Fields(content), " "), maxSummaryLength)
+}
+
+// CancelTask stops a running task together with every process it spawned.
+// The task's process group receives SIGINT, then SIGTERM and finally SIGKILL,
+// with gracePeriod between signals for the task to exit. Queued tasks are
+// removed from the queue without being started. It returns the state the task
+// ended in.
+func (tm *TaskManager) CancelTask(taskID string, gracePeriod time.Duration) (proto.TaskStatusResponse_TaskState, error) {
+	tm.mutex.Lock()
 	task, exists := tm.tasks[taskID]
 	if !exists {
-		return fmt.Errorf("task not found: %s", taskID)
+		tm.mutex.Unlock()
+		return proto.TaskStatusResponse_FAILED, fmt.Errorf("task not found: %s", taskID)
 	}
+	if task.State == proto.TaskStatusResponse_PENDING {
+		tm.dequeueTask(task)
+		tm.mutex.Unlock()
 
-	if task.State == proto.TaskStatusResponse_RUNNING {
-		task.cancel() // Cancel context to stop the process
-		task.State = proto.TaskStatusResponse_FAILED
+		task.cancel()
+		tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", "Task removed from the queue")
+		tm.completeTask(taskID)
 
-		log.Printf("Stopped task %s", taskID)
+		log.Printf("Removed task %s from the queue", taskID)
+		return proto.TaskStatusResponse_CANCELLED, nil
+	}
+	if task.State != proto.TaskStatusResponse_RUNNING {
+		state := task.State
+		tm.mutex.Unlock()
+		return state, fmt.Errorf("task %s is not running", taskID)
 	}
+	task.cancelRequested = true
+	process := task.Process
+	tm.mutex.Unlock()
 
-	return nil
+	if gracePeriod <= 0 {
+		gracePeriod = defaultCancelGracePeriod
+	}
+
+	if !tm.stopTask(task, process, gracePeriod) {
+		return proto.TaskStatusResponse_RUNNING, fmt.Errorf("task %s did not exit after cancellation", taskID)
+	}
+
+	tm.mutex.RLock()
+	state := task.State
+	tm.mutex.RUnlock()
+
+	log.Printf("Cancelled task %s", taskID)
+	return state, nil
+}
+
+// stopTask signals the task's process group with SIGINT, then SIGTERM and
+// finally SIGKILL, waiting gracePeriod after each signal for the task to
+// finish. It reports whether the task finished.
+func (tm *TaskManager) stopTask(task *Task, process *exec.Cmd, gracePeriod time.Duration) bool {
+	if process == nil {
+		// The process has not started yet, cancelling the context prevents it from starting
+		log.Printf("Stopping task %s before its process started", task.ID)
+		task.cancel()
+	} else {
+		for _, sig := range []syscall.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL} {
+			log.Printf("Sending %s to task %s (process group %d)", sig, task.ID, process.Process.Pid)
+			if err := signalProcessGroup(process, sig); err != nil {
+				log.Printf("Failed to send %s to task %s: %v", sig, task.ID, err)
+			}
+
+			if task.waitDone(gracePeriod) {
+				break
+			}
+		}
+	}
+
+	return task.waitDone(gracePeriod)
+}
+
+// Shutdown stops the running tasks before the daemon exits, so that their
+// process groups do not outlive it, giving each gracePeriod after every
+// signal. Queued tasks are left in the queue and are marked interrupted when
+// the daemon starts again.
+func (tm *TaskManager) Shutdown(gracePeriod time.Duration) {
+	tm.mutex.Lock()
+	tm.shuttingDown = true
+	stopping := make(map[*Task]*exec.Cmd)
+	for _, task := range tm.tasks {
+		if task.State == proto.TaskStatusResponse_RUNNING && !task.cancelRequested {
+			task.cancelRequested = true
+			stopping[task] = task.Process
+		}
+	}
+	tm.mutex.Unlock()
+
+	var wg sync.WaitGroup
+	for task, process := range stopping {
+		wg.Add(1)
+		go func(task *Task, process *exec.Cmd) {
+			defer wg.Done()
+			tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", "Stopping task: the daemon is shutting down")
+			if !tm.stopTask(task, process, gracePeriod) {
+				log.Printf("Task %s did not exit before the daemon stopped", task.ID)
+			}
+		}(task, process)
+	}
+	wg.Wait()
+}
+
+// watchTimeouts stops a running task once it exceeds its timeout or goes
+// without output for longer than its idle timeout
+func (tm *TaskManager) watchTimeouts(task *Task) {
+	if task.Timeout <= 0 && task.IdleTimeout <= 0 {
+		return
+	}
+
+	// Idle time is measured from the start of the process, not from the queued status line
+	task.outputMutex.Lock()
+	task.lastOutput = time.Now()
+	task.outputMutex.Unlock()
+
+	ticker := time.NewTicker(timeoutCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-task.done:
+			return
+		case now := <-ticker.C:
+			tm.mutex.RLock()
+			startedAt := task.StartedAt
+			tm.mutex.RUnlock()
+
+			task.outputMutex.Lock()
+			lastOutput := task.lastOutput
+			task.outputMutex.Unlock()
+
+			var reason string
+			if task.Timeout > 0 && now.Sub(startedAt) >= task.Timeout {
+				reason = fmt.Sprintf("task exceeded its timeout of %s", task.Timeout)
+			} else if task.IdleTimeout > 0 && now.Sub(lastOutput) >= task.IdleTimeout {
+				reason = fmt.Sprintf("task produced no output for %s", task.IdleTimeout)
+			}
+
+			if reason != "" {
+				tm.timeoutTask(task, reason)
+				return
+			}
+		}
+	}
+}
+
+// timeoutTask stops a running task that exceeded one of its timeouts
+func (tm *TaskManager) timeoutTask(task *Task, reason string) {
+	tm.mutex.Lock()
+	if task.State != proto.TaskStatusResponse_RUNNING || task.cancelRequested {
+		tm.mutex.Unlock()
+		return
+	}
+	task.timeoutReason = reason
+	process := task.Process
+	tm.mutex.Unlock()
+
+	log.Printf("Task %s timed out: %s", task.ID, reason)
+	tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", "Stopping task: "+reason)
+
+	if !tm.stopTask(task, process, defaultCancelGracePeriod) {
+		log.Printf("Task %s did not exit after timing out", task.ID)
+	}
+}
+
+// dequeueTask removes a pending task from the queue and marks it as cancelled (helper method - assumes mutex is already held)
+func (tm *TaskManager) dequeueTask(task *Task) {
+	if position := tm.queuePosition(task.ID); position > 0 {
+		tm.queue = append(tm.queue[:position-1], tm.queue[position:]...)
+	}
+
+	errorMsg := "task cancelled before it started"
+	exitCode := int32(-1)
+	finishedAt := time.Now()
+
+	task.cancelRequested = true
+	task.State = proto.TaskStatusResponse_CANCELLED
+	task.Error = &errorMsg
+	task.ExitCode = &exitCode
+	task.FinishedAt = &finishedAt
 }
 
 // CleanupTask removes a completed task from memory
@@ -537,17 +1385,34 @@ func (tm *TaskManager) CleanupTask(taskID string) error {
 	}
 
 	// Only cleanup completed or failed tasks
-	if task.State == proto.TaskStatusResponse_RUNNING {
+	if task.State == proto.TaskStatusResponse_RUNNING || task.State == proto.TaskStatusResponse_PENDING {
 		return fmt.Errorf("cannot cleanup running task")
 	}
 
 	delete(tm.tasks, taskID)
+	if tm.store != nil {
+		if err := tm.store.remove(taskID); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
 	log.Printf("Cleaned up task %s", taskID)
 	return nil
 }
 
-// ensureClaudeConfigured configures Claude with necessary settings and tools on first run
+// ensureClaudeConfigured configures Claude with necessary settings and tools on the first run in a
+// working directory, and again on later runs until every config command succeeded there (helper
+// method - must be called without mutex held)
 func (tm *TaskManager) ensureClaudeConfigured(workingDir string) error {
+	tm.configureMutex.Lock()
+	defer tm.configureMutex.Unlock()
+
+	tm.mutex.RLock()
+	done := tm.configuredDirs[workingDir]
+	tm.mutex.RUnlock()
+	if done {
+		return nil
+	}
+
 	// List of configuration commands to run
 	configCommands := [][]string{
 		{"claude", "config", "set", "hasCompletedProjectOnboarding", "true"},
@@ -561,6 +1426,7 @@ func (tm *TaskManager) ensureClaudeConfigured(workingDir string) error {
 
 	log.Printf("Configuring Claude with necessary settings and tools...")
 
+	configured := true
 	for _, cmdArgs := range configCommands {
 		cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
 		cmd.Dir = workingDir
@@ -570,15 +1436,35 @@ func (tm *TaskManager) ensureClaudeConfigured(workingDir string) error {
 		if err != nil {
 			// Log warning but don't fail - some configs might already be set
 			log.Printf("Warning: Claude config command failed (%v): %s", cmdArgs, string(output))
+			configured = false
 		} else {
 			log.Printf("Claude config applied: %v", cmdArgs[2:])
 		}
 	}
 
+	tm.mutex.Lock()
+	tm.claudeConfigured = configured
+	if configured {
+		if tm.configuredDirs == nil {
+			tm.configuredDirs = make(map[string]bool)
+		}
+		tm.configuredDirs[workingDir] = true
+	}
+	tm.mutex.Unlock()
+
 	log.Printf("Claude configuration completed")
 	return nil
 }
 
+// Load returns the number of running and queued tasks, the maximum number of
+// tasks run at once and whether Claude has been configured
+func (tm *TaskManager) Load() (running, queued, maxConcurrent int, claudeConfigured bool) {
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+
+	return tm.running, len(tm.queue), tm.maxConcurrent, tm.claudeConfigured
+}
+
 // ListTasks returns a list of all tasks, optionally filtered by state
 func (tm *TaskManager) ListTasks(stateFilter *proto.TaskStatusResponse_TaskState) ([]*proto.TaskInfo, error) {
 	tm.mutex.RLock()
@@ -592,28 +1478,16 @@ func (tm *TaskManager) ListTasks(stateFilter *proto.TaskStatusResponse_TaskState
 			continue
 		}
 
-		taskInfo := &proto.TaskInfo{
-			TaskId:           task.ID,
-			Prompt:           task.Prompt,
-			State:            task.State,
-			StartedAt:        task.StartedAt.Unix(),
-			WorkingDirectory: task.WorkingDir,
-		}
-
-		if task.FinishedAt != nil {
-			taskInfo.FinishedAt = task.FinishedAt.Unix()
-		}
-
-		if task.ExitCode != nil {
-			taskInfo.ExitCode = *task.ExitCode
-		}
+		tasks = append(tasks, tm.taskInfo(task))
+	}
 
-		if task.Error != nil {
-			taskInfo.Error = *task.Error
+	// Oldest first, so listings are stable across calls and restarts
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].StartedAt != tasks[j].StartedAt {
+			return tasks[i].StartedAt < tasks[j].StartedAt
 		}
-
-		tasks = append(tasks, taskInfo)
-	}
+		return tasks[i].TaskId < tasks[j].TaskId
+	})
 
 	return tasks, nil
-}
\ No newline at end of file
+}
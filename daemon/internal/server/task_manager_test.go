@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"daemon/proto"
+)
+
+// newTestTaskManager creates a task manager whose history is kept in a temporary directory
+func newTestTaskManager(t *testing.T) *TaskManager {
+	dir := t.TempDir()
+	return NewTaskManager(dir+"/logs", dir+"/tasks", 1, NewLogBus(io.Discard))
+}
+
+func TestGetTaskStatusReportsResult(t *testing.T) {
+	tm := newTestTaskManager(t)
+	now := time.Now()
+	finished := now.Add(time.Minute)
+	exitCode := int32(0)
+
+	tm.tasks["claude_1"] = &Task{ID: "claude_1", State: proto.TaskStatusResponse_COMPLETED, StartedAt: now.Add(-time.Hour), Result: "Older summary"}
+	tm.tasks["claude_2"] = &Task{ID: "claude_2", State: proto.TaskStatusResponse_COMPLETED, StartedAt: now, FinishedAt: &finished, ExitCode: &exitCode, Result: "Fixed the login timeout."}
+
+	// An empty task ID asks for the most recent task, which is how dispense pr gets the summary
+	for _, taskID := range []string{"", "claude_2"} {
+		status, err := tm.GetTaskStatus(taskID)
+		if err != nil {
+			t.Fatalf("GetTaskStatus(%q) failed: %v", taskID, err)
+		}
+		if status.Result != "Fixed the login timeout." {
+			t.Errorf("GetTaskStatus(%q).Result = %q, want the latest task's result", taskID, status.Result)
+		}
+		if status.Message != "Task completed successfully" || status.FinishedAt != finished.Unix() {
+			t.Errorf("GetTaskStatus(%q) = %+v", taskID, status)
+		}
+	}
+
+	tasks, err := tm.ListTasks(nil)
+	if err != nil {
+		t.Fatalf("ListTasks() failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].TaskId != "claude_1" || tasks[1].Result != "Fixed the login timeout." {
+		t.Errorf("ListTasks() = %+v", tasks)
+	}
+}
+
+func TestWriteTaskEntryDropsStalledSubscriber(t *testing.T) {
+	tm := newTestTaskManager(t)
+	task := &Task{ID: "claude_1", State: proto.TaskStatusResponse_RUNNING}
+
+	stalled, _, err := task.subscribe(0)
+	if err != nil {
+		t.Fatalf("subscribe() failed: %v", err)
+	}
+	reading, _, err := task.subscribe(0)
+	if err != nil {
+		t.Fatalf("subscribe() failed: %v", err)
+	}
+
+	// Writing more lines than a subscriber buffers must not block on the one that stopped reading
+	written := make(chan struct{})
+	received := 0
+	go func() {
+		for i := 0; i <= subscriberBufferSize; i++ {
+			tm.writeTaskEntry(task, &proto.ExecuteClaudeResponse{Type: proto.ExecuteClaudeResponse_STDOUT, Content: "line"})
+		}
+		close(written)
+	}()
+	for received <= subscriberBufferSize {
+		select {
+		case <-reading.ch:
+			received++
+		case <-time.After(5 * time.Second):
+			t.Fatalf("writeTaskEntry() blocked after %d lines", received)
+		}
+	}
+	<-written
+
+	task.outputMutex.Lock()
+	dropped, attached := stalled.dropped, len(task.subscribers)
+	task.outputMutex.Unlock()
+	if !dropped || reading.dropped || attached != 1 {
+		t.Errorf("stalled dropped = %v, reading dropped = %v, %d subscribers attached, want only the stalled one dropped", dropped, reading.dropped, attached)
+	}
+
+	lines := 0
+	for range stalled.ch {
+		lines++
+	}
+	if lines != subscriberBufferSize {
+		t.Errorf("stalled subscriber got %d lines before its channel was closed, want %d", lines, subscriberBufferSize)
+	}
+}
+
+func TestWriteTaskEntryRedactsSubscriberOutput(t *testing.T) {
+	tm := newTestTaskManager(t)
+	task := &Task{ID: "claude_1", State: proto.TaskStatusResponse_RUNNING}
+	sub, _, err := task.subscribe(0)
+	if err != nil {
+		t.Fatalf("subscribe() failed: %v", err)
+	}
+
+	tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STDOUT, "STDOUT", "export ANTHROPIC_API_KEY=sk-ant-REDACTED")
+	tm.writeTaskEntry(task, &proto.ExecuteClaudeResponse{
+		Type:    proto.ExecuteClaudeResponse_TOOL_USE,
+		Content: "Running curl ...",
+		Event: &proto.ClaudeEvent{
+			ToolUse:    &proto.ClaudeToolUse{Name: "Bash", InputJson: `{"command":"curl -H 'x-api-key: sk-ant-REDACTED'"}`},
+			ToolResult: &proto.ClaudeToolResult{Content: "key sk-ant-REDACTED"},
+		},
+	})
+
+	output := <-sub.ch
+	if strings.Contains(output.Content, "abcdefghijklmnop") {
+		t.Errorf("subscriber received %q", output.Content)
+	}
+	event := (<-sub.ch).Event
+	if strings.Contains(event.ToolUse.InputJson, "abcdefghijklmnop") || event.ToolResult.Content != "key sk-ant-[REDACTED]" {
+		t.Errorf("subscriber received event %+v", event)
+	}
+}
+
+// newCancellableTask adds a task in the given state to tm, queued between two other tasks
+func newCancellableTask(tm *TaskManager, state proto.TaskStatusResponse_TaskState) *Task {
+	ctx, cancel := context.WithCancel(context.Background())
+	task := &Task{ID: "claude_1", State: state, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+	tm.tasks[task.ID] = task
+	tm.queue = []string{"claude_0", task.ID, "claude_2"}
+	return task
+}
+
+func TestCancelPendingTaskLeavesQueue(t *testing.T) {
+	tm := newTestTaskManager(t)
+	task := newCancellableTask(tm, proto.TaskStatusResponse_PENDING)
+
+	state, err := tm.CancelTask(task.ID, time.Second)
+	if err != nil || state != proto.TaskStatusResponse_CANCELLED {
+		t.Fatalf("CancelTask() = %s, %v, want CANCELLED", state, err)
+	}
+	if len(tm.queue) != 2 || tm.queuePosition(task.ID) != 0 {
+		t.Errorf("queue %v still holds the cancelled task", tm.queue)
+	}
+	if !task.waitDone(time.Second) || task.ctx.Err() == nil || task.ExitCode == nil || *task.ExitCode != -1 {
+		t.Errorf("cancelled task is not finished: context %v, exit code %v", task.ctx.Err(), task.ExitCode)
+	}
+}
+
+func TestCancelRunningTaskWaitsForExit(t *testing.T) {
+	tm := newTestTaskManager(t)
+	task := newCancellableTask(tm, proto.TaskStatusResponse_RUNNING)
+
+	// Stands in for the Claude process, which exits once the task context is cancelled
+	go func() {
+		<-task.ctx.Done()
+		tm.mutex.Lock()
+		task.State = proto.TaskStatusResponse_CANCELLED
+		tm.mutex.Unlock()
+		tm.completeTask(task.ID)
+	}()
+
+	state, err := tm.CancelTask(task.ID, time.Second)
+	if err != nil || state != proto.TaskStatusResponse_CANCELLED {
+		t.Fatalf("CancelTask() = %s, %v, want CANCELLED", state, err)
+	}
+	if !task.cancelRequested {
+		t.Error("cancellation was not recorded on the task")
+	}
+}
+
+func TestShutdownStopsRunningTasks(t *testing.T) {
+	tm := newTestTaskManager(t)
+	task := newCancellableTask(tm, proto.TaskStatusResponse_RUNNING)
+	tm.tasks["claude_2"] = &Task{ID: "claude_2", State: proto.TaskStatusResponse_PENDING}
+	tm.running = 1
+
+	// Stands in for Claude and the tools it started in its process group
+	cmd := exec.CommandContext(task.ctx, "sh", "-c", "sleep 60 & wait")
+	configureProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	task.Process = cmd
+	go func() {
+		cmd.Wait()
+		tm.completeTask(task.ID)
+		tm.releaseSlot()
+	}()
+
+	tm.Shutdown(time.Second)
+
+	select {
+	case <-task.done:
+	default:
+		t.Error("Shutdown() returned before the running task finished")
+	}
+	// The slot freed by the stopped task does not start the next one
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+	if state := tm.tasks["claude_2"].State; state != proto.TaskStatusResponse_PENDING {
+		t.Errorf("queued task is %s after Shutdown(), want PENDING", state)
+	}
+}
+
+func TestCancelFinishedTaskFails(t *testing.T) {
+	for _, state := range []proto.TaskStatusResponse_TaskState{
+		proto.TaskStatusResponse_COMPLETED,
+		proto.TaskStatusResponse_FAILED,
+		proto.TaskStatusResponse_CANCELLED,
+	} {
+		tm := newTestTaskManager(t)
+		task := newCancellableTask(tm, state)
+
+		got, err := tm.CancelTask(task.ID, time.Second)
+		if err == nil || got != state {
+			t.Errorf("CancelTask() of a %s task = %s, %v, want its state and an error", state, got, err)
+		}
+		if task.ctx.Err() != nil {
+			t.Errorf("CancelTask() of a %s task cancelled its context", state)
+		}
+	}
+
+	if _, err := newTestTaskManager(t).CancelTask("claude_missing", time.Second); err == nil {
+		t.Error("CancelTask() of an unknown task succeeded")
+	}
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"daemon/proto"
+)
+
+// taskRecord is the on-disk representation of a task
+type taskRecord struct {
+	ID           string     `json:"id"`
+	Prompt       string     `json:"prompt"`
+	Model        string     `json:"model,omitempty"`
+	WorkingDir   string     `json:"working_dir"`
+	SessionID    string     `json:"session_id,omitempty"`
+	ResumeTaskID string     `json:"resume_task_id,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	State        string     `json:"state"`
+	ExitCode     *int32     `json:"exit_code,omitempty"`
+	Error        *string    `json:"error,omitempty"`
+	LogPath      string     `json:"log_path"`
+	Usage        *taskUsage `json:"usage,omitempty"`
+	Result       string     `json:"result,omitempty"`
+
+	VerifyCommands []string           `json:"verify_commands,omitempty"`
+	Verification   []taskVerification `json:"verification,omitempty"`
+	MaxFixAttempts int                `json:"max_fix_attempts,omitempty"`
+	ParentTaskID   string             `json:"parent_task_id,omitempty"`
+	Attempt        int                `json:"attempt,omitempty"`
+	FixTaskIDs     []string           `json:"fix_task_ids,omitempty"`
+}
+
+// taskUsage is the on-disk representation of a task's token usage and cost
+type taskUsage struct {
+	InputTokens              int64   `json:"input_tokens"`
+	OutputTokens             int64   `json:"output_tokens"`
+	CacheCreationInputTokens int64   `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64   `json:"cache_read_input_tokens"`
+	TotalCostUSD             float64 `json:"total_cost_usd"`
+}
+
+// taskVerification is the on-disk representation of a verification command result
+type taskVerification struct {
+	Command    string `json:"command"`
+	ExitCode   int32  `json:"exit_code"`
+	OutputTail string `json:"output_tail,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// taskStore persists task records as one JSON file per task so that task
+// history survives daemon restarts
+type taskStore struct {
+	dir string
+}
+
+// newTaskStore creates a task store in dir, creating the directory if needed
+func newTaskStore(dir string) (*taskStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create task directory %s: %w", dir, err)
+	}
+	return &taskStore{dir: dir}, nil
+}
+
+// save writes a task record, replacing any previous version of it
+func (s *taskStore) save(record *taskRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode task %s: %w", record.ID, err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated record
+	path := s.path(record.ID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write task %s: %w", record.ID, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write task %s: %w", record.ID, err)
+	}
+
+	return nil
+}
+
+// load reads every task record in the store
+func (s *taskStore) load() ([]*taskRecord, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task directory %s: %w", s.dir, err)
+	}
+
+	var records []*taskRecord
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		// A record that cannot be read must not hide the rest of the history
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			log.Printf("Warning: Skipping task record %s: %v", entry.Name(), err)
+			continue
+		}
+
+		var record taskRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			log.Printf("Warning: Skipping task record %s that failed to decode: %v", entry.Name(), err)
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// remove deletes a task record
+func (s *taskStore) remove(taskID string) error {
+	if err := os.Remove(s.path(taskID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// path returns the file a task record is stored in
+func (s *taskStore) path(taskID string) string {
+	return filepath.Join(s.dir, taskID+".json")
+}
+
+// newTaskRecord captures the persistent fields of a task (assumes the task manager mutex is held)
+func newTaskRecord(task *Task) *taskRecord {
+	record := &taskRecord{
+		ID:           task.ID,
+		Prompt:       task.Prompt,
+		Model:        task.Model,
+		WorkingDir:   task.WorkingDir,
+		SessionID:    task.SessionID,
+		ResumeTaskID: task.ResumeTaskID,
+		StartedAt:    task.StartedAt,
+		FinishedAt:   task.FinishedAt,
+		State:        task.State.String(),
+		ExitCode:     task.ExitCode,
+		Error:        task.Error,
+		LogPath:      task.LogPath,
+
+		VerifyCommands: task.VerifyCommands,
+		MaxFixAttempts: task.MaxFixAttempts,
+		ParentTaskID:   task.ParentTaskID,
+		Attempt:        task.Attempt,
+		FixTaskIDs:     task.FixTaskIDs,
+		Result:         task.Result,
+	}
+
+	for _, result := range task.Verification {
+		record.Verification = append(record.Verification, taskVerification{
+			Command:    result.Command,
+			ExitCode:   result.ExitCode,
+			OutputTail: result.OutputTail,
+			DurationMs: result.DurationMs,
+		})
+	}
+
+	if task.Usage != nil {
+		record.Usage = &taskUsage{
+			InputTokens:              task.Usage.InputTokens,
+			OutputTokens:             task.Usage.OutputTokens,
+			CacheCreationInputTokens: task.Usage.CacheCreationInputTokens,
+			CacheReadInputTokens:     task.Usage.CacheReadInputTokens,
+			TotalCostUSD:             task.Usage.TotalCostUsd,
+		}
+	}
+
+	return record
+}
+
+// taskFromRecord rebuilds a finished task from its stored record
+func taskFromRecord(record *taskRecord) *Task {
+	state, ok := proto.TaskStatusResponse_TaskState_value[record.State]
+	if !ok {
+		state = int32(proto.TaskStatusResponse_FAILED)
+	}
+
+	task := &Task{
+		ID:           record.ID,
+		Prompt:       record.Prompt,
+		Model:        record.Model,
+		WorkingDir:   record.WorkingDir,
+		SessionID:    record.SessionID,
+		ResumeTaskID: record.ResumeTaskID,
+		StartedAt:    record.StartedAt,
+		FinishedAt:   record.FinishedAt,
+		State:        proto.TaskStatusResponse_TaskState(state),
+		ExitCode:     record.ExitCode,
+		Error:        record.Error,
+		LogPath:      record.LogPath,
+		done:         make(chan struct{}),
+		finished:     true,
+
+		VerifyCommands: record.VerifyCommands,
+		MaxFixAttempts: record.MaxFixAttempts,
+		ParentTaskID:   record.ParentTaskID,
+		Attempt:        record.Attempt,
+		FixTaskIDs:     record.FixTaskIDs,
+		Result:         record.Result,
+	}
+	close(task.done)
+
+	for _, result := range record.Verification {
+		task.Verification = append(task.Verification, &proto.VerificationResult{
+			Command:    result.Command,
+			ExitCode:   result.ExitCode,
+			OutputTail: result.OutputTail,
+			DurationMs: result.DurationMs,
+		})
+	}
+
+	if record.Usage != nil {
+		task.Usage = &proto.ClaudeUsage{
+			InputTokens:              record.Usage.InputTokens,
+			OutputTokens:             record.Usage.OutputTokens,
+			CacheCreationInputTokens: record.Usage.CacheCreationInputTokens,
+			CacheReadInputTokens:     record.Usage.CacheReadInputTokens,
+			TotalCostUsd:             record.Usage.TotalCostUSD,
+		}
+	}
+
+	if info, err := os.Stat(record.LogPath); err == nil {
+		task.logOffset = info.Size()
+	}
+
+	return task
+}
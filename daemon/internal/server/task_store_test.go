@@ -0,0 +1,177 @@
+package server
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"daemon/proto"
+)
+
+func TestTaskStoreSaveLoad(t *testing.T) {
+	store, err := newTaskStore(filepath.Join(t.TempDir(), "tasks"))
+	if err != nil {
+		t.Fatalf("newTaskStore() failed: %v", err)
+	}
+
+	startedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(time.Minute)
+	exitCode := int32(1)
+	task := &Task{
+		ID:           "claude_1",
+		Prompt:       "Fix the login timeout",
+		Model:        "sonnet",
+		WorkingDir:   "/workspace",
+		SessionID:    "session-1",
+		ResumeTaskID: "claude_0",
+		StartedAt:    startedAt,
+		FinishedAt:   &finishedAt,
+		State:        proto.TaskStatusResponse_VERIFICATION_FAILED,
+		ExitCode:     &exitCode,
+		Result:       "Fixed the login timeout.",
+		Usage:        &proto.ClaudeUsage{InputTokens: 10, OutputTokens: 20, TotalCostUsd: 0.5},
+
+		VerifyCommands: []string{"go test ./..."},
+		Verification:   []*proto.VerificationResult{{Command: "go test ./...", ExitCode: 1, OutputTail: "FAIL", DurationMs: 1200}},
+		MaxFixAttempts: 2,
+		FixTaskIDs:     []string{"claude_2"},
+	}
+
+	if err := store.save(newTaskRecord(task)); err != nil {
+		t.Fatalf("save() failed: %v", err)
+	}
+	// Saving again replaces the record instead of adding one
+	if err := store.save(newTaskRecord(task)); err != nil {
+		t.Fatalf("save() failed: %v", err)
+	}
+
+	records, err := store.load()
+	if err != nil {
+		t.Fatalf("load() failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("load() returned %d records, want 1", len(records))
+	}
+
+	loaded := taskFromRecord(records[0])
+	if loaded.ID != task.ID || loaded.Prompt != task.Prompt || loaded.Model != task.Model || loaded.WorkingDir != task.WorkingDir ||
+		loaded.SessionID != task.SessionID || loaded.ResumeTaskID != task.ResumeTaskID || loaded.Result != task.Result {
+		t.Errorf("loaded task %+v, want %+v", loaded, task)
+	}
+	if loaded.State != task.State || *loaded.ExitCode != exitCode || !loaded.StartedAt.Equal(startedAt) || !loaded.FinishedAt.Equal(finishedAt) {
+		t.Errorf("loaded state %s, exit code %d, started %v, finished %v", loaded.State, *loaded.ExitCode, loaded.StartedAt, loaded.FinishedAt)
+	}
+	if loaded.Usage.OutputTokens != 20 || loaded.Usage.TotalCostUsd != 0.5 {
+		t.Errorf("loaded usage %+v", loaded.Usage)
+	}
+	if len(loaded.Verification) != 1 || loaded.Verification[0].OutputTail != "FAIL" || loaded.MaxFixAttempts != 2 || len(loaded.FixTaskIDs) != 1 {
+		t.Errorf("loaded verification %+v, fix attempts %d %v", loaded.Verification, loaded.MaxFixAttempts, loaded.FixTaskIDs)
+	}
+	if !loaded.waitDone(time.Second) {
+		t.Error("loaded task is not done")
+	}
+
+	if err := store.remove(task.ID); err != nil {
+		t.Fatalf("remove() failed: %v", err)
+	}
+	if err := store.remove(task.ID); err != nil {
+		t.Errorf("remove() of a removed task failed: %v", err)
+	}
+	if records, _ := store.load(); len(records) != 0 {
+		t.Errorf("load() after remove() returned %d records", len(records))
+	}
+}
+
+func TestLoadTasksInterruptsRunningTasks(t *testing.T) {
+	tests := []struct {
+		state string
+		want  proto.TaskStatusResponse_TaskState
+	}{
+		{"RUNNING", proto.TaskStatusResponse_INTERRUPTED},
+		{"PENDING", proto.TaskStatusResponse_INTERRUPTED},
+		{"COMPLETED", proto.TaskStatusResponse_COMPLETED},
+		{"CANCELLED", proto.TaskStatusResponse_CANCELLED},
+		{"NO_SUCH_STATE", proto.TaskStatusResponse_FAILED},
+	}
+
+	dir := t.TempDir()
+	store, err := newTaskStore(filepath.Join(dir, "tasks"))
+	if err != nil {
+		t.Fatalf("newTaskStore() failed: %v", err)
+	}
+	for _, tt := range tests {
+		logPath := filepath.Join(dir, tt.state+".log")
+		if err := os.WriteFile(logPath, []byte("[2024-05-01T10:00:00Z] [STDOUT] working\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.save(&taskRecord{ID: tt.state, State: tt.state, LogPath: logPath}); err != nil {
+			t.Fatalf("save() failed: %v", err)
+		}
+	}
+
+	tm := NewTaskManager(filepath.Join(dir, "logs"), store.dir, 1, NewLogBus(io.Discard))
+
+	for _, tt := range tests {
+		task, exists := tm.tasks[tt.state]
+		if !exists {
+			t.Errorf("task %s was not loaded", tt.state)
+			continue
+		}
+		if task.State != tt.want {
+			t.Errorf("task %s loaded as %s, want %s", tt.state, task.State, tt.want)
+		}
+
+		logData, err := os.ReadFile(task.LogPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interrupted := strings.Contains(string(logData), "Task interrupted by daemon restart")
+		if interrupted != (tt.want == proto.TaskStatusResponse_INTERRUPTED) {
+			t.Errorf("task %s log %q", tt.state, logData)
+		}
+		if interrupted && (task.ExitCode == nil || *task.ExitCode != -1 || task.Error == nil || task.FinishedAt == nil) {
+			t.Errorf("interrupted task %s has exit code %v, error %v, finished at %v", tt.state, task.ExitCode, task.Error, task.FinishedAt)
+		}
+		if task.logOffset != int64(len(logData)) {
+			t.Errorf("task %s log offset %d, want %d", tt.state, task.logOffset, len(logData))
+		}
+	}
+
+	// The interruption is persisted, so a second restart keeps it
+	records, err := store.load()
+	if err != nil {
+		t.Fatalf("load() failed: %v", err)
+	}
+	for _, record := range records {
+		if (record.ID == "RUNNING" || record.ID == "PENDING") && record.State != "INTERRUPTED" {
+			t.Errorf("stored task %s has state %s, want INTERRUPTED", record.ID, record.State)
+		}
+	}
+}
+
+func TestTaskStoreLoadSkipsCorruptRecords(t *testing.T) {
+	store, err := newTaskStore(filepath.Join(t.TempDir(), "tasks"))
+	if err != nil {
+		t.Fatalf("newTaskStore() failed: %v", err)
+	}
+	for _, id := range []string{"claude_1", "claude_3"} {
+		if err := store.save(&taskRecord{ID: id, State: "COMPLETED"}); err != nil {
+			t.Fatalf("save() failed: %v", err)
+		}
+	}
+	// A record cut short by a crash while it was written
+	if err := os.WriteFile(store.path("claude_2"), []byte(`{"id": "claude_2", "sta`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := store.load()
+	if err != nil {
+		t.Fatalf("load() failed: %v", err)
+	}
+	if len(records) != 2 || records[0].ID != "claude_1" || records[1].ID != "claude_3" {
+		t.Errorf("load() = %+v, want the two valid records", records)
+	}
+}
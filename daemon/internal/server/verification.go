@@ -0,0 +1,236 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"daemon/proto"
+)
+
+const (
+	// verificationTailLines is the number of output lines kept per verification command
+	verificationTailLines = 50
+	// verificationMaxLineLength is the length output lines are cut to in the tail
+	verificationMaxLineLength = 500
+)
+
+// verifyTask runs the task's verification commands one after the other in its
+// working directory and records their results. Every command runs even if an
+// earlier one failed, so the task shows the complete picture. The task keeps
+// running until the last command has finished and can be cancelled or time
+// out meanwhile. If verification fails, the fix attempt is queued with the
+// same API key and environment variables.
+func (tm *TaskManager) verifyTask(task *Task, apiKey string, envVars map[string]string) {
+	tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", fmt.Sprintf("Running %d verification command(s)", len(task.VerifyCommands)))
+
+	for _, command := range task.VerifyCommands {
+		result, ok := tm.runVerification(task, command)
+		if !ok {
+			break
+		}
+
+		tm.mutex.Lock()
+		task.Verification = append(task.Verification, result)
+		tm.persistTask(task)
+		tm.mutex.Unlock()
+
+		if result.ExitCode == 0 {
+			tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", fmt.Sprintf("Verification passed: %s", command))
+		} else {
+			tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", fmt.Sprintf("Verification failed with exit code %d: %s", result.ExitCode, command))
+		}
+	}
+
+	tm.finishVerification(task, apiKey, envVars)
+}
+
+// runVerification runs a verification command, streaming its output to the
+// task. It reports false without running the command if the task is being
+// cancelled or timed out.
+func (tm *TaskManager) runVerification(task *Task, command string) (*proto.VerificationResult, bool) {
+	cmd := exec.CommandContext(task.ctx, "sh", "-c", command)
+	cmd.Dir = task.WorkingDir
+	cmd.Env = append(os.Environ(), "CI=true")
+	configureProcessGroup(cmd)
+
+	result := &proto.VerificationResult{Command: command}
+	started := time.Now()
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		result.ExitCode = -1
+		result.OutputTail = fmt.Sprintf("failed to run command: %v", err)
+		return result, true
+	}
+	cmd.Stderr = cmd.Stdout
+
+	// Starting under the mutex makes the command the process a cancellation
+	// or timeout stops, or keeps it from starting once one is underway
+	tm.mutex.Lock()
+	if task.cancelRequested || task.timeoutReason != "" {
+		tm.mutex.Unlock()
+		return nil, false
+	}
+	err = cmd.Start()
+	if err == nil {
+		task.Process = cmd
+	}
+	tm.mutex.Unlock()
+
+	if err != nil {
+		result.ExitCode = -1
+		result.OutputTail = fmt.Sprintf("failed to run command: %v", err)
+		return result, true
+	}
+
+	log.Printf("Running verification command for task %s: %s", task.ID, command)
+	tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", "Verifying: "+command)
+
+	var tail []string
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_VERIFICATION, "VERIFICATION", line)
+
+		if len(line) > verificationMaxLineLength {
+			line = line[:verificationMaxLineLength] + "..."
+		}
+		tail = append(tail, line)
+		if len(tail) > verificationTailLines {
+			tail = tail[1:]
+		}
+	}
+
+	err = cmd.Wait()
+	result.DurationMs = time.Since(started).Milliseconds()
+	result.OutputTail = strings.Join(tail, "\n")
+	if exitError, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = int32(exitError.ExitCode())
+	} else if err != nil {
+		result.ExitCode = -1
+	}
+
+	return result, true
+}
+
+// finishVerification sets the final state of a task once its verification
+// commands have run. A fix attempt for a failed verification is queued while
+// the state is set, so no caller sees the task fail before its fix starts.
+func (tm *TaskManager) finishVerification(task *Task, apiKey string, envVars map[string]string) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	now := time.Now()
+	task.FinishedAt = &now
+
+	var failed *proto.VerificationResult
+	for _, result := range task.Verification {
+		if result.ExitCode != 0 {
+			failed = result
+			break
+		}
+	}
+
+	switch {
+	case task.cancelRequested:
+		errorMsg := "task cancelled during verification"
+		task.State = proto.TaskStatusResponse_CANCELLED
+		task.Error = &errorMsg
+	case task.timeoutReason != "":
+		errorMsg := task.timeoutReason
+		task.State = proto.TaskStatusResponse_TIMED_OUT
+		task.Error = &errorMsg
+	case failed != nil:
+		errorMsg := fmt.Sprintf("verification failed: %s exited with code %d", failed.Command, failed.ExitCode)
+		exitCode := failed.ExitCode
+		task.State = proto.TaskStatusResponse_VERIFICATION_FAILED
+		task.Error = &errorMsg
+		task.ExitCode = &exitCode
+	default:
+		task.State = proto.TaskStatusResponse_COMPLETED
+	}
+
+	log.Printf("Task %s verification finished with state %s", task.ID, task.State)
+
+	if task.State == proto.TaskStatusResponse_VERIFICATION_FAILED {
+		tm.queueFixAttempt(task, apiKey, envVars)
+	}
+}
+
+// queueFixAttempt queues a follow-up Claude run after task failed its
+// verification, as long as the task it belongs to has fix attempts left. The
+// fix attempt continues the Claude session of the failed run, is told which
+// commands failed with their output and runs the same verification commands
+// (helper method - assumes mutex is already held).
+func (tm *TaskManager) queueFixAttempt(task *Task, apiKey string, envVars map[string]string) {
+	parent := task
+	if task.ParentTaskID != "" {
+		var exists bool
+		if parent, exists = tm.tasks[task.ParentTaskID]; !exists {
+			log.Printf("Parent task %s of fix attempt %s not found", task.ParentTaskID, task.ID)
+			return
+		}
+	}
+
+	attempt := task.Attempt + 1
+	if attempt > parent.MaxFixAttempts {
+		if parent.MaxFixAttempts > 0 {
+			log.Printf("Task %s failed verification after %d fix attempts", parent.ID, parent.MaxFixAttempts)
+			tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", fmt.Sprintf("Verification still fails after %d fix attempts", parent.MaxFixAttempts))
+		}
+		return
+	}
+
+	fix := &Task{
+		Prompt:         fixPrompt(task.Verification),
+		Model:          task.Model,
+		WorkingDir:     task.WorkingDir,
+		Timeout:        task.Timeout,
+		IdleTimeout:    task.IdleTimeout,
+		VerifyCommands: task.VerifyCommands,
+		ParentTaskID:   parent.ID,
+		Attempt:        attempt,
+	}
+	// Continuing the session keeps what Claude learned about the change
+	if task.SessionID != "" {
+		fix.ResumeTaskID = task.ID
+	}
+
+	if err := tm.queueTask(fix, apiKey, envVars); err != nil {
+		log.Printf("Failed to start fix attempt %d for task %s: %v", attempt, parent.ID, err)
+		tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_ERROR, "ERROR", fmt.Sprintf("Failed to start fix attempt: %v", err))
+		return
+	}
+
+	parent.FixTaskIDs = append(parent.FixTaskIDs, fix.ID)
+	tm.persistTask(parent)
+
+	log.Printf("Started fix attempt %d/%d for task %s as task %s", attempt, parent.MaxFixAttempts, parent.ID, fix.ID)
+	tm.writeTaskOutput(task, proto.ExecuteClaudeResponse_STATUS, "STATUS", fmt.Sprintf("Starting fix attempt %d/%d as task %s", attempt, parent.MaxFixAttempts, fix.ID))
+}
+
+// fixPrompt builds the prompt of a fix attempt from the failed verification results
+func fixPrompt(results []*proto.VerificationResult) string {
+	var prompt strings.Builder
+	prompt.WriteString("The verification commands failed after your changes. Fix the code so that they pass, addressing the cause of the failures rather than weakening the checks.\n")
+
+	for _, result := range results {
+		if result.ExitCode == 0 {
+			continue
+		}
+		fmt.Fprintf(&prompt, "\n`%s` exited with code %d", result.Command, result.ExitCode)
+		if result.OutputTail != "" {
+			fmt.Fprintf(&prompt, ", the end of its output was:\n```\n%s\n```\n", result.OutputTail)
+		} else {
+			prompt.WriteString(" without output.\n")
+		}
+	}
+
+	return prompt.String()
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"testing"
+
+	"daemon/proto"
+)
+
+func TestFinishVerificationQueuesFixAttempt(t *testing.T) {
+	tm := newTestTaskManager(t)
+	// Keep the fix attempt in the queue instead of running Claude
+	tm.running = tm.maxConcurrent
+
+	task := &Task{
+		ID:             "claude_1",
+		State:          proto.TaskStatusResponse_RUNNING,
+		WorkingDir:     t.TempDir(),
+		VerifyCommands: []string{"go test ./..."},
+		Verification:   []*proto.VerificationResult{{Command: "go test ./...", ExitCode: 1, OutputTail: "FAIL"}},
+		MaxFixAttempts: 1,
+	}
+	tm.tasks[task.ID] = task
+
+	tm.finishVerification(task, "", nil)
+
+	// The failed state and the fix attempt are visible together
+	if task.State != proto.TaskStatusResponse_VERIFICATION_FAILED || len(task.FixTaskIDs) != 1 {
+		t.Fatalf("task state %s with fix attempts %v, want VERIFICATION_FAILED with one", task.State, task.FixTaskIDs)
+	}
+	fix := tm.tasks[task.FixTaskIDs[0]]
+	if fix == nil || fix.State != proto.TaskStatusResponse_PENDING || fix.ParentTaskID != task.ID || fix.Attempt != 1 {
+		t.Errorf("fix attempt %+v", fix)
+	}
+
+	// Without attempts left only the state is set
+	fix.State = proto.TaskStatusResponse_RUNNING
+	fix.Verification = task.Verification
+	tm.finishVerification(fix, "", nil)
+	if fix.State != proto.TaskStatusResponse_VERIFICATION_FAILED || len(task.FixTaskIDs) != 1 {
+		t.Errorf("last fix attempt state %s, %d fix attempts, want VERIFICATION_FAILED without another", fix.State, len(task.FixTaskIDs))
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+
+	"daemon/proto"
+
+	"workspacediff"
+)
+
+// fileDiffStatus maps how a file changed to its status in the protocol
+var fileDiffStatus = map[workspacediff.Status]proto.FileDiff_Status{
+	workspacediff.Modified:    proto.FileDiff_MODIFIED,
+	workspacediff.Added:       proto.FileDiff_ADDED,
+	workspacediff.Deleted:     proto.FileDiff_DELETED,
+	workspacediff.Renamed:     proto.FileDiff_RENAMED,
+	workspacediff.Untracked:   proto.FileDiff_UNTRACKED,
+	workspacediff.TypeChanged: proto.FileDiff_TYPE_CHANGED,
+}
+
+// workspaceDiff returns the changes in the git repository at dir since
+// baseRef, or since the commit the workspace started from if baseRef is
+// empty. Committed, staged, unstaged and untracked changes are all included;
+// ignored files are not. The repository's index is left untouched.
+func workspaceDiff(ctx context.Context, dir, baseRef string, includePatches bool) (*proto.WorkspaceDiffResponse, error) {
+	diff, err := workspacediff.Compute(ctx, dir, baseRef, includePatches)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.WorkspaceDiffResponse{
+		BaseCommit: diff.BaseCommit,
+		HeadCommit: diff.HeadCommit,
+		Branch:     diff.Branch,
+	}
+	for _, file := range diff.Files {
+		resp.Files = append(resp.Files, &proto.FileDiff{
+			Path:           file.Path,
+			OldPath:        file.OldPath,
+			Status:         fileDiffStatus[file.Status],
+			Additions:      int32(file.Additions),
+			Deletions:      int32(file.Deletions),
+			Binary:         file.Binary,
+			Patch:          file.Patch,
+			PatchTruncated: file.PatchTruncated,
+		})
+	}
+	return resp, nil
+}
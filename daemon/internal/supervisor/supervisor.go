@@ -0,0 +1,130 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// LogMaxSize is the size at which the daemon's log file is rotated
+	LogMaxSize = 10 * 1024 * 1024
+	// LogBackups is the number of rotated log files kept
+	LogBackups = 5
+
+	// minRestartDelay is the delay before the first restart of a crashed daemon
+	minRestartDelay = time.Second
+	// maxRestartDelay caps the delay, which doubles with every restart
+	maxRestartDelay = time.Minute
+	// stableRunTime is how long the daemon has to run for the delay to be reset
+	stableRunTime = time.Minute
+)
+
+// Run starts the daemon binary at path with args and restarts it whenever it
+// exits, waiting longer after each restart while it keeps crashing. SIGINT and
+// SIGTERM are forwarded to the daemon and Run returns once it has stopped.
+// The daemon's output is written to out.
+func Run(path string, args []string, out io.Writer) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	delay := minRestartDelay
+	for {
+		cmd := exec.Command(path, args...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+
+		started := time.Now()
+		if err := cmd.Start(); err != nil {
+			log.Printf("Supervisor: failed to start daemon: %v", err)
+		} else {
+			log.Printf("Supervisor: started daemon with PID %d", cmd.Process.Pid)
+
+			exited := make(chan error, 1)
+			go func() {
+				exited <- cmd.Wait()
+			}()
+
+			select {
+			case err := <-exited:
+				log.Printf("Supervisor: daemon %s", exitDescription(err))
+			case sig := <-signals:
+				log.Printf("Supervisor: received %s, stopping daemon", sig)
+				cmd.Process.Signal(sig)
+				log.Printf("Supervisor: daemon %s", exitDescription(<-exited))
+				return nil
+			}
+
+			if time.Since(started) >= stableRunTime {
+				delay = minRestartDelay
+			}
+		}
+
+		log.Printf("Supervisor: restarting daemon in %s", delay)
+		select {
+		case <-time.After(delay):
+		case sig := <-signals:
+			log.Printf("Supervisor: received %s, not restarting daemon", sig)
+			return nil
+		}
+
+		delay *= 2
+		if delay > maxRestartDelay {
+			delay = maxRestartDelay
+		}
+	}
+}
+
+// WaitReady waits until the daemon at target reports through the gRPC health
+// service that it is serving, or fails once timeout has passed. The daemon is
+// dialed with opts, or without transport security if there are none.
+func WaitReady(target string, timeout time.Duration, opts ...grpc.DialOption) error {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon at %s: %w", target, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client := healthpb.NewHealthClient(conn)
+	for {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		if err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("daemon not ready after %s: %w", timeout, err)
+			}
+			return fmt.Errorf("daemon not ready after %s: %s", timeout, resp.Status)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// exitDescription describes how the daemon process exited
+func exitDescription(err error) string {
+	if err == nil {
+		return "exited"
+	}
+	return fmt.Sprintf("exited: %v", err)
+}
@@ -28,7 +28,7 @@ func main() {
 	}
 
 	// Build daemon binary first
-	if err := buildDaemonBinary(); err != nil {
+	if err := buildDaemonBinary(buildInfo); err != nil {
 		fmt.Printf("Error building daemon binary: %v\n", err)
 		os.Exit(1)
 	}
@@ -142,47 +142,53 @@ func getGitCommit() string {
 }
 
 func buildLdflags(info BuildInfo) string {
+	// The CLI also records the version of the daemon it embeds, which is
+	// built with the same values
 	return fmt.Sprintf(
-		"-X main.Version=%s -X main.GitCommit=%s -X main.BuildTime=%s",
+		"-X main.Version=%[1]s -X main.GitCommit=%[2]s -X main.BuildTime=%[3]s "+
+			"-X cli/pkg/daemon.Version=%[1]s -X cli/pkg/daemon.GitCommit=%[2]s -X cli/pkg/daemon.BuildTime=%[3]s",
 		info.Version, info.GitCommit, info.BuildTime,
 	)
 }
 
-func buildDaemonBinary() error {
-	fmt.Println("Building daemon binary (Linux AMD64)...")
-
+func buildDaemonBinary(info BuildInfo) error {
 	// Ensure daemon embed directory exists
 	embedDir := "pkg/daemon"
 	if err := os.MkdirAll(embedDir, 0755); err != nil {
 		return fmt.Errorf("failed to create embed directory: %w", err)
 	}
 
-	daemonBinaryPath := filepath.Join(embedDir, "daemon-linux-amd64")
+	// Sandboxes run on either architecture, the CLI embeds both
+	for _, arch := range []string{"amd64", "arm64"} {
+		fmt.Printf("Building daemon binary (linux/%s)...\n", arch)
 
-	// Build daemon for Linux AMD64
-	cmd := exec.Command("go", "build",
-		"-ldflags", "-s -w", // Strip debug info for smaller binary
-		"-o", daemonBinaryPath,
-		"./cmd/main.go")
+		daemonBinaryPath := filepath.Join(embedDir, "daemon-linux-"+arch)
 
-	// Set environment for Linux AMD64 cross-compilation
-	env := os.Environ()
-	env = append(env, "GOOS=linux")
-	env = append(env, "GOARCH=amd64")
-	env = append(env, "CGO_ENABLED=0") // Static binary
-	cmd.Env = env
-	cmd.Dir = "../daemon" // Build from daemon directory
+		// Build daemon for Linux
+		cmd := exec.Command("go", "build",
+			"-ldflags", "-s -w "+buildLdflags(info), // Strip debug info for smaller binary
+			"-o", daemonBinaryPath,
+			"./cmd/main.go")
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to build daemon: %w\nOutput: %s", err, string(output))
-	}
+		// Set environment for Linux cross-compilation
+		env := os.Environ()
+		env = append(env, "GOOS=linux")
+		env = append(env, "GOARCH="+arch)
+		env = append(env, "CGO_ENABLED=0") // Static binary
+		cmd.Env = env
+		cmd.Dir = "../daemon" // Build from daemon directory
 
-	// Verify the binary was created
-	if stat, err := os.Stat(daemonBinaryPath); err != nil {
-		return fmt.Errorf("daemon binary not found after build: %w", err)
-	} else {
-		fmt.Printf("✓ Built daemon binary (%d bytes)\n", stat.Size())
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to build daemon for linux/%s: %w\nOutput: %s", arch, err, string(output))
+		}
+
+		// Verify the binary was created
+		if stat, err := os.Stat(daemonBinaryPath); err != nil {
+			return fmt.Errorf("daemon binary not found after build: %w", err)
+		} else {
+			fmt.Printf("✓ Built daemon binary for linux/%s (%d bytes)\n", arch, stat.Size())
+		}
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,8 +15,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"cli/pkg/daemon"
+	"cli/pkg/database"
 	"cli/pkg/sandbox"
 	"cli/pkg/sandbox/local"
 	"cli/pkg/sandbox/remote"
@@ -72,13 +76,34 @@ func findSandboxByName(sandboxName string) (*sandbox.SandboxInfo, error) {
 }
 
 // getDaemonConnection gets connection details for daemon (works with both local and remote)
-func getDaemonConnection(sandboxName string) (string, func(), error) {
+func getDaemonConnection(sandboxName string) (string, []grpc.DialOption, func(), error) {
 	// Find the sandbox to determine its type
 	sandboxInfo, err := findSandboxByName(sandboxName)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to find sandbox: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to find sandbox: %w", err)
 	}
 
+	return getSandboxDaemonConnection(sandboxInfo)
+}
+
+// getSandboxDaemonConnection gets connection details for the daemon of a sandbox that was already looked up,
+// including the dial options that authenticate the CLI to it
+func getSandboxDaemonConnection(sandboxInfo *sandbox.SandboxInfo) (string, []grpc.DialOption, func(), error) {
+	dialOpts, err := daemonDialOptions(sandboxInfo)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	daemonAddr, cleanup, err := getSandboxDaemonAddress(sandboxInfo)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return daemonAddr, dialOpts, cleanup, nil
+}
+
+// getSandboxDaemonAddress returns the address the daemon of a sandbox can be reached at,
+// setting up an SSH port-forward for remote sandboxes
+func getSandboxDaemonAddress(sandboxInfo *sandbox.SandboxInfo) (string, func(), error) {
 	// Handle connection based on sandbox type
 	if sandboxInfo.Type == sandbox.TypeRemote {
 		// Remote sandbox - use SSH port forwarding
@@ -90,7 +115,7 @@ func getDaemonConnection(sandboxName string) (string, func(), error) {
 		return remoteProvider.GetDaemonConnection(sandboxInfo)
 	} else {
 		// Local sandbox - use direct IP connection
-		ip, err := getSandboxIP(sandboxName)
+		ip, err := getSandboxIP(sandboxInfo.Name)
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to get sandbox IP: %w", err)
 		}
@@ -100,6 +125,26 @@ func getDaemonConnection(sandboxName string) (string, func(), error) {
 	}
 }
 
+// daemonDialOptions returns the gRPC dial options for the daemon of a sandbox, using the credentials
+// generated when it was created. Sandboxes created before credentials existed are dialed without them.
+func daemonDialOptions(sandboxInfo *sandbox.SandboxInfo) ([]grpc.DialOption, error) {
+	db, err := database.NewSandboxDB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sandbox database: %w", err)
+	}
+
+	creds, err := db.GetDaemonCredentials(sandboxInfo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load daemon credentials: %w", err)
+	}
+	if creds == nil {
+		utils.DebugPrintf("No daemon credentials stored for sandbox %s, connecting without authentication\n", sandboxInfo.Name)
+		return daemon.InsecureDialOptions(), nil
+	}
+
+	return creds.DialOptions()
+}
+
 // executeSandboxCommand runs a command in a sandbox (both local Docker and remote Daytona)
 func executeSandboxCommand(sandboxName string, command []string) ([]byte, error) {
 	// Find the sandbox to determine its type
@@ -233,7 +278,10 @@ All claude commands require a sandbox name as the first argument.
 
 Usage:
   cli claude <sandbox-name> status
-  cli claude <sandbox-name> run "prompt"
+  cli claude <sandbox-name> run [--continue | --resume task-id] [--timeout 30m] [--idle-timeout 10m] [--verify "cmd"]... [--fix-attempts N] "prompt"
+  cli claude <sandbox-name> attach [task-id]
+  cli claude <sandbox-name> cancel [task-id]
+  cli claude <sandbox-name> move <task-id> <position>
   cli claude <sandbox-name> tasks [task-id]
   cli claude <sandbox-name> logs [task-id]`,
 	Args: cobra.MinimumNArgs(1),
@@ -265,10 +313,52 @@ Usage:
 				os.Exit(1)
 			}
 			modelFlag := cmd.Root().Flag("model").Value.String()
-			if err := runClaudeWithPrompt(prompt, workDir, sandboxName, modelFlag); err != nil {
+			continueSession, _ := cmd.Flags().GetBool("continue")
+			resumeTaskID, _ := cmd.Flags().GetString("resume")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			idleTimeout, _ := cmd.Flags().GetDuration("idle-timeout")
+			verifyCommands, _ := cmd.Flags().GetStringArray("verify")
+			fixAttempts, _ := cmd.Flags().GetInt("fix-attempts")
+			if fixAttempts > 0 && len(verifyCommands) == 0 {
+				fmt.Fprintf(os.Stderr, "❌ --fix-attempts requires at least one --verify command\n")
+				os.Exit(1)
+			}
+			if err := runClaudeWithPrompt(prompt, workDir, sandboxName, modelFlag, resumeTaskID, continueSession, timeout, idleTimeout, verifyCommands, fixAttempts); err != nil {
 				fmt.Fprintf(os.Stderr, "❌ Claude execution failed: %s\n", err)
 				os.Exit(1)
 			}
+		case "attach":
+			var taskID string
+			if len(args) > 2 {
+				taskID = args[2]
+			}
+			if err := attachToClaudeTask(sandboxName, taskID); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to attach to Claude task: %s\n", err)
+				os.Exit(1)
+			}
+		case "cancel":
+			var taskID string
+			if len(args) > 2 {
+				taskID = args[2]
+			}
+			if err := cancelClaudeTask(sandboxName, taskID); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to cancel Claude task: %s\n", err)
+				os.Exit(1)
+			}
+		case "move":
+			if len(args) < 4 {
+				fmt.Fprintf(os.Stderr, "❌ Task ID and queue position are required for 'move' command\n")
+				os.Exit(1)
+			}
+			position, err := strconv.Atoi(args[3])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Invalid queue position: %s\n", args[3])
+				os.Exit(1)
+			}
+			if err := moveClaudeTask(sandboxName, args[2], position); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to move Claude task: %s\n", err)
+				os.Exit(1)
+			}
 		case "tasks":
 			var taskID string
 			if len(args) > 2 {
@@ -294,7 +384,7 @@ Usage:
 			}
 		default:
 			fmt.Fprintf(os.Stderr, "❌ Unknown subcommand: %s\n", subcommand)
-			fmt.Fprintf(os.Stderr, "Available subcommands: status, run, tasks, logs\n")
+			fmt.Fprintf(os.Stderr, "Available subcommands: status, run, attach, cancel, move, tasks, logs\n")
 			os.Exit(1)
 		}
 	},
@@ -311,7 +401,7 @@ func checkClaudeDaemonStatus(sandboxName string) error {
 	utils.DebugPrintf("Checking Claude daemon status for sandbox: %s\n", sandboxName)
 
 	// Get daemon connection (works with both local and remote)
-	daemonAddr, cleanup, err := getDaemonConnection(sandboxName)
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxName)
 	if err != nil {
 		return fmt.Errorf("failed to get daemon connection: %w", err)
 	}
@@ -320,23 +410,33 @@ func checkClaudeDaemonStatus(sandboxName string) error {
 	utils.DebugPrintf("Connecting to daemon at: %s\n", daemonAddr)
 
 	// Try to connect to the daemon
-	conn, err := grpc.NewClient(daemonAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
 	if err != nil {
 		return fmt.Errorf("daemon not reachable at %s: %w", daemonAddr, err)
 	}
 	defer conn.Close()
 
-	// Test connection with a simple health check using the AgentService
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Try to create an AgentService client and test connectivity
+	if err := checkDaemonHealth(ctx, conn); err != nil {
+		return fmt.Errorf("daemon at %s is not ready: %w", daemonAddr, err)
+	}
+
 	client := pb.NewAgentServiceClient(conn)
 
+	info, err := client.DaemonInfo(ctx, &pb.DaemonInfoRequest{})
+	if err != nil {
+		utils.DebugPrintf("DaemonInfo error: %v\n", err)
+	} else {
+		printDaemonInfo(info)
+		printDaemonUpgradeHint(info, sandboxName)
+	}
+
 	// Get status of the most recent task (empty task ID returns latest task status)
 	status, err := client.GetTaskStatus(ctx, &pb.TaskStatusRequest{TaskId: ""})
 	if err != nil {
-		// If there's an error (e.g., no tasks found), daemon is ready but no tasks
+		// The daemon is healthy, so an error means it has no tasks yet
 		utils.DebugPrintf("GetTaskStatus error: %v\n", err)
 		fmt.Printf("🟢 Claude is ready in sandbox '%s'\n", sandboxName)
 		return nil
@@ -353,8 +453,26 @@ func checkClaudeDaemonStatus(sandboxName string) error {
 			if status.Error != "" {
 				fmt.Printf(": %s", status.Error)
 			}
+		case pb.TaskStatusResponse_CANCELLED:
+			fmt.Printf("⚪ Cancelled in sandbox '%s'", sandboxName)
+		case pb.TaskStatusResponse_INTERRUPTED:
+			fmt.Printf("🟠 Interrupted in sandbox '%s'", sandboxName)
+		case pb.TaskStatusResponse_TIMED_OUT:
+			fmt.Printf("⏰ Timed out in sandbox '%s'", sandboxName)
+			if status.Error != "" {
+				fmt.Printf(": %s", status.Error)
+			}
+		case pb.TaskStatusResponse_VERIFICATION_FAILED:
+			fmt.Printf("🧪 Verification failed in sandbox '%s'", sandboxName)
+			if status.Error != "" {
+				fmt.Printf(": %s", status.Error)
+			}
 		case pb.TaskStatusResponse_PENDING:
-			fmt.Printf("🟢 Claude is ready in sandbox '%s'", sandboxName)
+			if status.QueuePosition > 0 {
+				fmt.Printf("⏳ Queued in sandbox '%s' (position %d)", sandboxName, status.QueuePosition)
+			} else {
+				fmt.Printf("🟢 Claude is ready in sandbox '%s'", sandboxName)
+			}
 		default:
 			fmt.Printf("🟢 Claude is ready in sandbox '%s'", sandboxName)
 		}
@@ -365,8 +483,11 @@ func checkClaudeDaemonStatus(sandboxName string) error {
 	return nil
 }
 
-// runClaudeWithPrompt executes Claude with the given prompt
-func runClaudeWithPrompt(prompt, workDir, sandboxName, model string) error {
+// runClaudeWithPrompt executes Claude with the given prompt. Zero timeouts use
+// the defaults of the sandbox. The verify commands run in the working directory
+// once Claude has finished and decide whether the task succeeded. If they fail,
+// the daemon starts up to maxFixAttempts follow-up runs, which are followed too.
+func runClaudeWithPrompt(prompt, workDir, sandboxName, model, resumeTaskID string, continueSession bool, timeout, idleTimeout time.Duration, verifyCommands []string, maxFixAttempts int) error {
 	if sandboxName == "" {
 		return fmt.Errorf("sandbox name is required. Use --sandbox flag to specify which sandbox to use")
 	}
@@ -374,7 +495,7 @@ func runClaudeWithPrompt(prompt, workDir, sandboxName, model string) error {
 	utils.DebugPrintf("Running Claude with prompt: %s in sandbox: %s\n", prompt, sandboxName)
 
 	// Get daemon connection (works with both local and remote)
-	daemonAddr, cleanup, err := getDaemonConnection(sandboxName)
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxName)
 	if err != nil {
 		return fmt.Errorf("failed to get daemon connection: %w", err)
 	}
@@ -383,7 +504,7 @@ func runClaudeWithPrompt(prompt, workDir, sandboxName, model string) error {
 	utils.DebugPrintf("Connecting to daemon at: %s\n", daemonAddr)
 
 	// Connect to daemon
-	conn, err := grpc.NewClient(daemonAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to connect to daemon at %s: %w", daemonAddr, err)
 	}
@@ -392,19 +513,38 @@ func runClaudeWithPrompt(prompt, workDir, sandboxName, model string) error {
 	// Create AgentService client
 	client := pb.NewAgentServiceClient(conn)
 
-	// Get Anthropic API key
-	apiKey, err := utils.GetAnthropicAPIKey()
-	if err != nil {
-		return fmt.Errorf("failed to get Anthropic API key: %w", err)
-	}
+	info := checkDaemonVersion(client, sandboxName)
 
 	// Prepare the request
 	req := &pb.ExecuteClaudeRequest{
-		Prompt:           prompt,
-		WorkingDirectory: workDir,
-		EnvironmentVars:  make(map[string]string), // Add any needed env vars
-		AnthropicApiKey:  apiKey,
-		Model:           model,
+		Prompt:             prompt,
+		WorkingDirectory:   workDir,
+		EnvironmentVars:    make(map[string]string), // Add any needed env vars
+		Model:              model,
+		ResumeTaskId:       resumeTaskID,
+		ContinueSession:    continueSession,
+		TimeoutSeconds:     int32(timeout.Seconds()),
+		IdleTimeoutSeconds: int32(idleTimeout.Seconds()),
+		VerifyCommands:     verifyCommands,
+		MaxFixAttempts:     int32(maxFixAttempts),
+	}
+
+	// The task refers to the key stored in the daemon when the sandbox was created rather than carrying it
+	err = setTaskCredentials(req, info, func() (string, error) {
+		apiKey, err := utils.GetAnthropicAPIKey()
+		if err != nil {
+			return "", fmt.Errorf("failed to get Anthropic API key: %w", err)
+		}
+		return apiKey, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if resumeTaskID != "" {
+		fmt.Printf("↪️  Continuing the conversation of task %s\n", resumeTaskID)
+	} else if continueSession {
+		fmt.Printf("↪️  Continuing the conversation of the latest task\n")
 	}
 
 	fmt.Printf("🟡 Claude is working...\n")
@@ -416,38 +556,311 @@ func runClaudeWithPrompt(prompt, workDir, sandboxName, model string) error {
 		return fmt.Errorf("failed to start Claude execution: %w", err)
 	}
 
+	var taskID string
 	for {
 		resp, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
+			if taskID != "" {
+				return fmt.Errorf("error receiving stream: %w (the task keeps running, reattach with 'dispense claude %s attach %s')", err, sandboxName, taskID)
+			}
 			return fmt.Errorf("error receiving stream: %w", err)
 		}
 
-		// Handle different response types
-		switch resp.Type {
-		case pb.ExecuteClaudeResponse_STDOUT:
-			fmt.Print(resp.Content)
-		case pb.ExecuteClaudeResponse_STDERR:
-			fmt.Fprintf(os.Stderr, "%s", resp.Content)
-		case pb.ExecuteClaudeResponse_STATUS:
-			if resp.IsFinished {
-				if resp.ExitCode == 0 {
-					fmt.Printf("🟢 Done\n")
-				} else {
-					fmt.Printf("🔴 Error\n")
-				}
-			}
-		case pb.ExecuteClaudeResponse_ERROR:
-			fmt.Fprintf(os.Stderr, "❌ Error: %s\n", resp.Content)
+		if resp.TaskId != "" {
+			taskID = resp.TaskId
 		}
 
+		printClaudeResponse(resp)
+
 		if resp.IsFinished {
 			break
 		}
 	}
 
+	if taskID != "" && maxFixAttempts > 0 {
+		return followFixAttempts(client, sandboxName, taskID)
+	}
+
+	return nil
+}
+
+// followFixAttempts attaches to the fix attempts the daemon starts after the
+// verification of a task failed, one after the other, until none is left
+func followFixAttempts(client pb.AgentServiceClient, sandboxName, taskID string) error {
+	followed := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		status, err := client.GetTaskStatus(ctx, &pb.TaskStatusRequest{TaskId: taskID})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to get the fix attempts of task %s: %w", taskID, err)
+		}
+
+		if followed >= len(status.FixTaskIds) {
+			return nil
+		}
+
+		fixTaskID := status.FixTaskIds[followed]
+		followed++
+
+		fmt.Printf("🔁 Fix attempt %d: task %s\n", followed, fixTaskID)
+		if err := attachToClaudeTask(sandboxName, fixTaskID); err != nil {
+			return err
+		}
+	}
+}
+
+// printClaudeResponse prints a single streamed Claude output entry
+func printClaudeResponse(resp *pb.ExecuteClaudeResponse) {
+	switch resp.Type {
+	case pb.ExecuteClaudeResponse_STDOUT:
+		fmt.Println(resp.Content)
+	case pb.ExecuteClaudeResponse_STDERR:
+		fmt.Fprintln(os.Stderr, resp.Content)
+	case pb.ExecuteClaudeResponse_STATUS:
+		if resp.IsFinished {
+			if resp.ExitCode == 0 {
+				fmt.Printf("🟢 Done\n")
+			} else {
+				fmt.Printf("🔴 Error\n")
+			}
+		}
+	case pb.ExecuteClaudeResponse_ERROR:
+		fmt.Fprintf(os.Stderr, "❌ Error: %s\n", resp.Content)
+	case pb.ExecuteClaudeResponse_ASSISTANT:
+		fmt.Println(resp.Content)
+	case pb.ExecuteClaudeResponse_TOOL_USE:
+		fmt.Printf("🔧 %s\n", resp.Content)
+	case pb.ExecuteClaudeResponse_TOOL_RESULT:
+		if resp.Event != nil && resp.Event.ToolResult != nil && resp.Event.ToolResult.IsError {
+			fmt.Printf("   ⚠️  %s\n", resp.Content)
+		}
+	case pb.ExecuteClaudeResponse_USAGE:
+		fmt.Printf("📊 %s\n", resp.Content)
+	case pb.ExecuteClaudeResponse_VERIFICATION:
+		fmt.Printf("   %s\n", resp.Content)
+	case pb.ExecuteClaudeResponse_RESULT:
+		if resp.Event != nil && resp.Event.Result != nil && resp.Event.Result.IsError {
+			fmt.Fprintf(os.Stderr, "❌ Claude stopped (%s): %s\n", resp.Event.Result.Subtype, resp.Content)
+		}
+	case pb.ExecuteClaudeResponse_SYSTEM:
+		utils.DebugPrintf("%s\n", resp.Content)
+	}
+}
+
+const (
+	// attachMaxRetries is the number of consecutive failed reconnects before attach gives up
+	attachMaxRetries = 10
+	// attachRetryDelay is the pause between reconnect attempts
+	attachRetryDelay = 3 * time.Second
+)
+
+// attachToClaudeTask follows the output of an existing task (the most recent one
+// if taskID is empty). When the connection to the daemon drops, for example
+// because the SSH port-forward went away, it reconnects and resumes from the
+// last received log offset. Errors reported by the daemon, such as an unknown
+// task or rejected credentials, are returned right away.
+func attachToClaudeTask(sandboxName, taskID string) error {
+	if sandboxName == "" {
+		return fmt.Errorf("sandbox name is required")
+	}
+
+	// The sandbox and its credentials are looked up once; failing to find them
+	// is not something reconnecting can fix
+	sandboxInfo, err := findSandboxByName(sandboxName)
+	if err != nil {
+		return fmt.Errorf("failed to find sandbox: %w", err)
+	}
+	dialOpts, err := daemonDialOptions(sandboxInfo)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	failures := 0
+
+	for {
+		received, finished, err := streamAttachedTask(sandboxInfo, dialOpts, &taskID, &offset)
+		if finished {
+			return nil
+		}
+		if !retryableAttachError(err) {
+			return err
+		}
+
+		if received {
+			failures = 0
+		}
+		failures++
+		if failures > attachMaxRetries {
+			return fmt.Errorf("giving up after %d reconnect attempts: %w", attachMaxRetries, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "⚠️  Connection to daemon lost (%v), reconnecting in %s...\n", err, attachRetryDelay)
+		time.Sleep(attachRetryDelay)
+	}
+}
+
+// connectionError marks an attach failure caused by the connection to the
+// daemon, such as a port-forward that could not be set up or a stream that
+// ended before the task finished
+type connectionError struct {
+	err error
+}
+
+func (e *connectionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *connectionError) Unwrap() error {
+	return e.err
+}
+
+// retryableAttachError reports whether attaching failed because the connection
+// to the daemon was lost, rather than because the daemon rejected the request
+// or the sandbox could not be looked up
+func retryableAttachError(err error) bool {
+	var connErr *connectionError
+	if errors.As(err, &connErr) {
+		return true
+	}
+	s, ok := status.FromError(err)
+	return ok && s.Code() == codes.Unavailable
+}
+
+// streamAttachedTask opens a single AttachTask stream and prints its output,
+// advancing taskID and offset as responses arrive
+func streamAttachedTask(sandboxInfo *sandbox.SandboxInfo, dialOpts []grpc.DialOption, taskID *string, offset *int64) (received bool, finished bool, err error) {
+	sandboxName := sandboxInfo.Name
+	daemonAddr, cleanup, err := getSandboxDaemonAddress(sandboxInfo)
+	if err != nil {
+		return false, false, &connectionError{fmt.Errorf("failed to get daemon connection: %w", err)}
+	}
+	defer cleanup()
+
+	utils.DebugPrintf("Attaching to task '%s' at offset %d via %s\n", *taskID, *offset, daemonAddr)
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
+	if err != nil {
+		return false, false, &connectionError{fmt.Errorf("failed to connect to daemon at %s: %w", daemonAddr, err)}
+	}
+	defer conn.Close()
+
+	client := pb.NewAgentServiceClient(conn)
+
+	stream, err := client.AttachTask(context.Background(), &pb.AttachTaskRequest{
+		TaskId:     *taskID,
+		FromOffset: *offset,
+	})
+	if err != nil {
+		return false, false, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return received, false, &connectionError{fmt.Errorf("stream closed before the task finished")}
+		}
+		if err != nil {
+			return received, false, err
+		}
+
+		if !received && *offset == 0 {
+			fmt.Printf("📎 Attached to task %s in sandbox '%s'\n", resp.TaskId, sandboxName)
+		}
+		received = true
+
+		if resp.TaskId != "" {
+			*taskID = resp.TaskId
+		}
+		if resp.Offset > 0 {
+			*offset = resp.Offset
+		}
+
+		printClaudeResponse(resp)
+
+		if resp.IsFinished {
+			return true, true, nil
+		}
+	}
+}
+
+// cancelClaudeTask stops a running task (the most recent one if taskID is empty)
+func cancelClaudeTask(sandboxName, taskID string) error {
+	if sandboxName == "" {
+		return fmt.Errorf("sandbox name is required")
+	}
+
+	utils.DebugPrintf("Cancelling task '%s' in sandbox: %s\n", taskID, sandboxName)
+
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxName)
+	if err != nil {
+		return fmt.Errorf("failed to get daemon connection: %w", err)
+	}
+	defer cleanup()
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon at %s: %w", daemonAddr, err)
+	}
+	defer conn.Close()
+
+	client := pb.NewAgentServiceClient(conn)
+
+	// The daemon escalates SIGINT, SIGTERM and SIGKILL, so allow for all three grace periods
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	fmt.Printf("🛑 Cancelling task in sandbox '%s'...\n", sandboxName)
+
+	resp, err := client.CancelTask(ctx, &pb.CancelTaskRequest{TaskId: taskID})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("%s", resp.Message)
+	}
+
+	fmt.Printf("%s Task %s: %s\n", getTaskStateEmoji(resp.State), resp.TaskId, getTaskStateText(resp.State))
+	return nil
+}
+
+// moveClaudeTask moves a queued task to a new position in the sandbox task queue
+func moveClaudeTask(sandboxName, taskID string, position int) error {
+	if sandboxName == "" {
+		return fmt.Errorf("sandbox name is required")
+	}
+
+	utils.DebugPrintf("Moving task '%s' to queue position %d in sandbox: %s\n", taskID, position, sandboxName)
+
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxName)
+	if err != nil {
+		return fmt.Errorf("failed to get daemon connection: %w", err)
+	}
+	defer cleanup()
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon at %s: %w", daemonAddr, err)
+	}
+	defer conn.Close()
+
+	client := pb.NewAgentServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.MoveTask(ctx, &pb.MoveTaskRequest{TaskId: taskID, Position: int32(position)})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("%s", resp.Message)
+	}
+
+	fmt.Printf("⏳ Task %s is now at queue position %d\n", taskID, resp.QueuePosition)
 	return nil
 }
 
@@ -460,13 +873,13 @@ func listClaudeTasks(sandboxName, taskID string) error {
 	utils.DebugPrintf("Listing Claude tasks from sandbox: %s\n", sandboxName)
 
 	// Try to get tasks from daemon first
-	daemonAddr, cleanup, err := getDaemonConnection(sandboxName)
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxName)
 	if err != nil {
 		return fmt.Errorf("failed to get daemon connection: %w", err)
 	}
 	defer cleanup()
 
-	conn, err := grpc.NewClient(daemonAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
 	if err != nil {
 		utils.DebugPrintf("Failed to connect to daemon, will check log files in sandbox: %v\n", err)
 	} else {
@@ -509,12 +922,35 @@ func listClaudeTasks(sandboxName, taskID string) error {
 					fmt.Printf("     📅 Started: %s\n", startTime)
 					fmt.Printf("     📊 State: %s\n", stateText)
 
+					if task.QueuePosition > 0 {
+						fmt.Printf("     ⏳ Queue position: %d\n", task.QueuePosition)
+					}
+
+					if task.ResumeTaskId != "" {
+						fmt.Printf("     ↪️  Continues: %s\n", task.ResumeTaskId)
+					}
+
+					if task.ParentTaskId != "" {
+						fmt.Printf("     🔁 Fix attempt %d of %s\n", task.Attempt, task.ParentTaskId)
+					}
+					if len(task.FixTaskIds) > 0 {
+						fmt.Printf("     🔁 Fix attempts: %s\n", strings.Join(task.FixTaskIds, ", "))
+					}
+
 					if task.FinishedAt > 0 {
 						endTime := time.Unix(task.FinishedAt, 0).Format("2006-01-02 15:04:05")
 						duration := time.Unix(task.FinishedAt, 0).Sub(time.Unix(task.StartedAt, 0))
 						fmt.Printf("     ⏱️  Duration: %s (finished: %s)\n", duration.String(), endTime)
 					}
 
+					if task.Usage != nil {
+						fmt.Printf("     💰 %s\n", formatTaskUsage(task.Usage))
+					}
+
+					for _, result := range task.Verification {
+						fmt.Printf("     %s\n", formatVerificationResult(result))
+					}
+
 					if task.Error != "" {
 						fmt.Printf("     ❌ Error: %s\n", task.Error)
 					}
@@ -622,7 +1058,8 @@ func showLogFileFromSandbox(sandboxName, logDir, filename string) error {
 	return nil
 }
 
-// runClaudeOnGitHubIssue starts Claude working on the GitHub issue from the sandbox's task data
+// runClaudeOnGitHubIssue starts Claude working on the GitHub issue, or the review comments of the GitHub
+// pull request, from the sandbox's task data
 func runClaudeOnGitHubIssue(sandboxName string) error {
 	utils.DebugPrintf("Starting Claude on GitHub issue for sandbox %s\n", sandboxName)
 
@@ -632,8 +1069,16 @@ func runClaudeOnGitHubIssue(sandboxName string) error {
 		return fmt.Errorf("failed to read GitHub issue data: %w", err)
 	}
 
+	if taskData.GitHubPR != nil {
+		workDir, err := getWorkDirFromProvider(sandboxName)
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		return runClaudeWithPrompt(createGitHubPRPrompt(taskData), workDir, sandboxName, "", "", false, 0, 0, nil, 0)
+	}
+
 	if taskData.GitHubIssue == nil {
-		return fmt.Errorf("no GitHub issue found in task data")
+		return fmt.Errorf("no GitHub issue or pull request found in task data")
 	}
 
 	// Construct the comprehensive prompt for the GitHub issue
@@ -659,7 +1104,7 @@ func runClaudeOnGitHubIssue(sandboxName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
-	return runClaudeWithPrompt(taskPrompt.String(), workDir, sandboxName, "")
+	return runClaudeWithPrompt(taskPrompt.String(), workDir, sandboxName, "", "", false, 0, 0, nil, 0)
 }
 
 // readTaskDataFromSandbox reads the GitHub issue task data from the sandbox
@@ -697,6 +1142,14 @@ func getTaskStateEmoji(state pb.TaskStatusResponse_TaskState) string {
 		return "🟢"
 	case pb.TaskStatusResponse_FAILED:
 		return "🔴"
+	case pb.TaskStatusResponse_CANCELLED:
+		return "⚪"
+	case pb.TaskStatusResponse_INTERRUPTED:
+		return "🟠"
+	case pb.TaskStatusResponse_TIMED_OUT:
+		return "⏰"
+	case pb.TaskStatusResponse_VERIFICATION_FAILED:
+		return "🧪"
 	default:
 		return "❓"
 	}
@@ -713,6 +1166,14 @@ func getTaskStateText(state pb.TaskStatusResponse_TaskState) string {
 		return "Completed"
 	case pb.TaskStatusResponse_FAILED:
 		return "Failed"
+	case pb.TaskStatusResponse_CANCELLED:
+		return "Cancelled"
+	case pb.TaskStatusResponse_INTERRUPTED:
+		return "Interrupted"
+	case pb.TaskStatusResponse_TIMED_OUT:
+		return "Timed out"
+	case pb.TaskStatusResponse_VERIFICATION_FAILED:
+		return "Verification failed"
 	default:
 		return "Unknown"
 	}
@@ -735,6 +1196,10 @@ func showTaskDetails(sandboxName, taskID string, taskStatus *pb.TaskStatusRespon
 	fmt.Printf("🆔 Task ID: %s\n", taskID)
 	fmt.Printf("%s  State: %s\n", stateEmoji, stateText)
 
+	if taskStatus.QueuePosition > 0 {
+		fmt.Printf("⏳ Queue position: %d\n", taskStatus.QueuePosition)
+	}
+
 	if taskStatus.Prompt != "" {
 		fmt.Printf("📝 Prompt: %s\n", taskStatus.Prompt)
 	}
@@ -743,6 +1208,22 @@ func showTaskDetails(sandboxName, taskID string, taskStatus *pb.TaskStatusRespon
 		fmt.Printf("📂 Working Directory: %s\n", taskStatus.WorkingDirectory)
 	}
 
+	if taskStatus.SessionId != "" {
+		fmt.Printf("💬 Claude Session: %s\n", taskStatus.SessionId)
+	}
+
+	if taskStatus.ResumeTaskId != "" {
+		fmt.Printf("↪️  Continues: %s\n", taskStatus.ResumeTaskId)
+	}
+
+	if taskStatus.ParentTaskId != "" {
+		fmt.Printf("🔁 Fix attempt %d of: %s\n", taskStatus.Attempt, taskStatus.ParentTaskId)
+	}
+
+	if len(taskStatus.FixTaskIds) > 0 {
+		fmt.Printf("🔁 Fix attempts: %s\n", strings.Join(taskStatus.FixTaskIds, ", "))
+	}
+
 	fmt.Printf("📅 Started: %s\n", time.Unix(taskStatus.StartedAt, 0).Format("2006-01-02 15:04:05"))
 
 	if taskStatus.FinishedAt > 0 {
@@ -753,6 +1234,26 @@ func showTaskDetails(sandboxName, taskID string, taskStatus *pb.TaskStatusRespon
 		fmt.Printf("⏱️  Duration: %s\n", duration.String())
 	}
 
+	if taskStatus.Model != "" {
+		fmt.Printf("🤖 Model: %s\n", taskStatus.Model)
+	}
+
+	if taskStatus.Usage != nil {
+		fmt.Printf("💰 Usage: %s\n", formatTaskUsage(taskStatus.Usage))
+	}
+
+	if len(taskStatus.Verification) > 0 {
+		fmt.Printf("🧪 Verification:\n")
+		for _, result := range taskStatus.Verification {
+			fmt.Printf("   %s\n", formatVerificationResult(result))
+			if result.ExitCode != 0 && result.OutputTail != "" {
+				for _, line := range strings.Split(result.OutputTail, "\n") {
+					fmt.Printf("      %s\n", line)
+				}
+			}
+		}
+	}
+
 	if taskStatus.ExitCode != 0 {
 		fmt.Printf("🔢 Exit Code: %d\n", taskStatus.ExitCode)
 	}
@@ -769,6 +1270,21 @@ func showTaskDetails(sandboxName, taskID string, taskStatus *pb.TaskStatusRespon
 	return nil
 }
 
+// formatTaskUsage formats the token usage and cost of a task for display
+func formatTaskUsage(usage *pb.ClaudeUsage) string {
+	return fmt.Sprintf("$%.4f (%d input, %d output, %d cache write, %d cache read tokens)",
+		usage.TotalCostUsd, usage.InputTokens, usage.OutputTokens, usage.CacheCreationInputTokens, usage.CacheReadInputTokens)
+}
+
+// formatVerificationResult formats the outcome of a verification command for display
+func formatVerificationResult(result *pb.VerificationResult) string {
+	duration := (time.Duration(result.DurationMs) * time.Millisecond).Round(100 * time.Millisecond)
+	if result.ExitCode == 0 {
+		return fmt.Sprintf("✅ %s (%s)", result.Command, duration)
+	}
+	return fmt.Sprintf("❌ %s (exit code %d, %s)", result.Command, result.ExitCode, duration)
+}
+
 // showTaskDetailsFromLogFile displays detailed information about a specific task from log files
 func showTaskDetailsFromLogFile(sandboxName, taskID string) error {
 	sandboxLogDir := "/home/daytona/.dispense/logs"
@@ -913,4 +1429,10 @@ func init() {
 	// Claude command now handles all subcommands inline
 	// No subcommand registration needed - all handled in the main Run function
 	// Optionally add workdir flag for run command if needed in future
+	claudeCmd.Flags().Bool("continue", false, "Continue the Claude conversation of the latest task (run only)")
+	claudeCmd.Flags().String("resume", "", "Continue the Claude conversation of the given task (run only)")
+	claudeCmd.Flags().Duration("timeout", 0, "Stop the task after this long, e.g. 30m (run only, default: sandbox default)")
+	claudeCmd.Flags().Duration("idle-timeout", 0, "Stop the task after this long without output, e.g. 10m (run only, default: sandbox default)")
+	claudeCmd.Flags().StringArray("verify", nil, "Command that must succeed once Claude has finished, repeatable (run only)")
+	claudeCmd.Flags().Int("fix-attempts", 0, "Follow-up runs that fix failed --verify commands (run only)")
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryableAttachError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{status.Error(codes.Unavailable, "connection refused"), true},
+		{status.Error(codes.Unavailable, "stream fell behind the output of task claude_1"), true},
+		{&connectionError{fmt.Errorf("failed to get daemon connection: %w", errors.New("ssh: handshake failed"))}, true},
+		{&connectionError{errors.New("stream closed before the task finished")}, true},
+		{fmt.Errorf("giving up: %w", &connectionError{errors.New("connection refused")}), true},
+		// Failing to look up the sandbox or its credentials is not fixed by reconnecting
+		{fmt.Errorf("failed to find sandbox: %w", errors.New("sandbox 'x' not found")), false},
+		{fmt.Errorf("failed to load daemon credentials: %w", errors.New("database is locked")), false},
+		{status.Error(codes.NotFound, "task not found: claude_1"), false},
+		{status.Error(codes.InvalidArgument, "no task to attach to"), false},
+		{status.Error(codes.Unauthenticated, "invalid client certificate"), false},
+		{status.Error(codes.PermissionDenied, "permission denied"), false},
+		{status.Error(codes.Unknown, "daemon error"), false},
+	}
+
+	for _, tt := range tests {
+		if got := retryableAttachError(tt.err); got != tt.want {
+			t.Errorf("retryableAttachError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
 	"cli/pkg/daemon"
+	"cli/pkg/sandbox"
+	"cli/pkg/utils"
+	pb "cli/proto"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 var daemonCmd = &cobra.Command{
@@ -24,7 +33,11 @@ var startDaemonCmd = &cobra.Command{
 	Short: "Start the embedded daemon",
 	Long:  `Extract and start the embedded daemon binary.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		embeddedDaemon := daemon.NewEmbeddedDaemon()
+		embeddedDaemon, err := daemon.NewEmbeddedDaemonForArch(runtime.GOARCH)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
 
 		// Check if daemon is supported on current platform
 		if !embeddedDaemon.IsSupported() {
@@ -110,7 +123,12 @@ var infoDaemonCmd = &cobra.Command{
 
 		fmt.Println("Embedded Daemon Information:")
 		fmt.Printf("  Version: %s\n", embeddedDaemon.GetVersion())
-		fmt.Printf("  Binary size: %d bytes\n", embeddedDaemon.Size())
+		fmt.Printf("  Git commit: %s\n", daemon.GitCommit)
+		fmt.Printf("  Build time: %s\n", daemon.BuildTime)
+		for _, arch := range []string{"amd64", "arm64"} {
+			archDaemon, _ := daemon.NewEmbeddedDaemonForArch(arch)
+			fmt.Printf("  Binary size (linux/%s): %d bytes\n", arch, archDaemon.Size())
+		}
 		fmt.Printf("  Supported on current platform: %t\n", embeddedDaemon.IsSupported())
 		fmt.Printf("  Current platform: %s\n", getPlatformInfo())
 
@@ -122,10 +140,42 @@ var infoDaemonCmd = &cobra.Command{
 	},
 }
 
+var upgradeDaemonCmd = &cobra.Command{
+	Use:   "upgrade <sandbox-name>",
+	Short: "Upgrade the daemon of a sandbox to the embedded version",
+	Long: `Replace the daemon running in a sandbox with the daemon embedded in this
+version of dispense and restart it. The daemon finishes its requests before it
+stops and keeps its task history.
+
+The upgrade is refused while the daemon has running or queued Claude tasks,
+which a restart would interrupt, unless --force is given. --force also
+reinstalls a daemon that is up to date or not reachable.
+
+Examples:
+  dispense daemon upgrade my-project
+  dispense daemon upgrade my-project --force`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		preferLocal, _ := cmd.Flags().GetBool("local")
+		preferRemote, _ := cmd.Flags().GetBool("remote")
+
+		if err := upgradeSandboxDaemon(args[0], preferLocal, preferRemote, force); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Daemon upgrade failed: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
 func init() {
 	// Add daemon subcommands
 	daemonCmd.AddCommand(startDaemonCmd)
 	daemonCmd.AddCommand(infoDaemonCmd)
+	daemonCmd.AddCommand(upgradeDaemonCmd)
+
+	upgradeDaemonCmd.Flags().Bool("force", false, "Upgrade even if the daemon is up to date, unreachable or running tasks")
+	upgradeDaemonCmd.Flags().Bool("local", false, "Prefer local Docker sandboxes")
+	upgradeDaemonCmd.Flags().Bool("remote", false, "Prefer remote Daytona sandboxes")
 
 	// Add daemon command to root
 	rootCmd.AddCommand(daemonCmd)
@@ -133,4 +183,250 @@ func init() {
 
 func getPlatformInfo() string {
 	return fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
-}
\ No newline at end of file
+}
+
+// checkDaemonHealth asks the standard gRPC health service of a daemon whether
+// it is ready to run tasks, waiting for the connection until ctx expires.
+// Daemons older than the health service count as ready once they answer.
+func checkDaemonHealth(ctx context.Context, conn *grpc.ClientConn) error {
+	client := healthpb.NewHealthClient(conn)
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: pb.AgentService_ServiceDesc.ServiceName}, grpc.WaitForReady(true))
+	if status.Code(err) == codes.Unimplemented {
+		utils.DebugPrintf("Daemon has no health service, assuming it is ready\n")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("daemon is not serving (%s)", resp.Status)
+	}
+	return nil
+}
+
+// dialSandboxDaemon connects to the daemon of a sandbox. The returned function
+// closes the connection and any port forwarding set up for it.
+func dialSandboxDaemon(sandboxInfo *sandbox.SandboxInfo) (*grpc.ClientConn, func(), error) {
+	daemonAddr, dialOpts, cleanup, err := getSandboxDaemonConnection(sandboxInfo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get daemon connection: %w", err)
+	}
+
+	utils.DebugPrintf("Connecting to daemon at: %s\n", daemonAddr)
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to connect to daemon at %s: %w", daemonAddr, err)
+	}
+
+	return conn, func() {
+		conn.Close()
+		cleanup()
+	}, nil
+}
+
+// checkSandboxDaemonHealth checks whether the daemon of a sandbox is ready to run tasks
+func checkSandboxDaemonHealth(sandboxInfo *sandbox.SandboxInfo, timeout time.Duration) error {
+	conn, cleanup, err := dialSandboxDaemon(sandboxInfo)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return checkDaemonHealth(ctx, conn)
+}
+
+// getSandboxDaemonInfo checks the health of a sandbox's daemon and returns its build and runtime information
+func getSandboxDaemonInfo(sandboxInfo *sandbox.SandboxInfo, timeout time.Duration) (*pb.DaemonInfoResponse, error) {
+	conn, cleanup, err := dialSandboxDaemon(sandboxInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := checkDaemonHealth(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	return pb.NewAgentServiceClient(conn).DaemonInfo(ctx, &pb.DaemonInfoRequest{})
+}
+
+// formatDaemonSummary formats the version, uptime and load of a daemon as a short line
+func formatDaemonSummary(info *pb.DaemonInfoResponse) string {
+	uptime := (time.Duration(info.UptimeSeconds) * time.Second).String()
+	return fmt.Sprintf("%s, up %s, %d running, %d queued", info.Version, uptime, info.RunningTasks, info.QueuedTasks)
+}
+
+// printDaemonInfo prints the build and runtime information of a daemon
+func printDaemonInfo(info *pb.DaemonInfoResponse) {
+	fmt.Printf("🩺 Daemon %s (commit %s, built %s)\n", info.Version, info.GitCommit, info.BuildTime)
+	fmt.Printf("   • Up since: %s (%s)\n", time.Unix(info.StartedAt, 0).Format("2006-01-02 15:04:05"), time.Duration(info.UptimeSeconds)*time.Second)
+
+	claude := info.ClaudeVersion
+	if claude == "" {
+		claude = "not found"
+	}
+	if info.ClaudeConfigured {
+		claude += " (configured)"
+	} else {
+		claude += " (not configured yet)"
+	}
+	fmt.Printf("   • Claude CLI: %s\n", claude)
+	fmt.Printf("   • Workspace: %s\n", info.Workspace)
+	fmt.Printf("   • Tasks: %d running, %d queued (at most %d at once)\n", info.RunningTasks, info.QueuedTasks, info.MaxConcurrentTasks)
+}
+
+// isSandboxRunning reports whether a sandbox is in a state its daemon can be reached in
+func isSandboxRunning(sandboxInfo *sandbox.SandboxInfo) bool {
+	state := strings.ToLower(sandboxInfo.State)
+	return state == "running" || state == "started"
+}
+
+// printDaemonUpgradeHint offers to upgrade the daemon of a sandbox when the
+// daemon embedded in the CLI is newer than the running one
+func printDaemonUpgradeHint(info *pb.DaemonInfoResponse, sandboxName string) {
+	if !daemon.NewEmbeddedDaemon().IsNewerThan(info.Version, info.BuildTime) {
+		return
+	}
+	fmt.Printf("⬆️  Daemon %s is available (running: %s), upgrade with 'dispense daemon upgrade %s'\n", daemon.Version, info.Version, sandboxName)
+}
+
+// checkDaemonVersion compares the daemon of a sandbox with the embedded one
+// before a command uses it and returns what the daemon reported about itself.
+// Daemons that cannot report their version are left alone, and nil is returned.
+func checkDaemonVersion(client pb.AgentServiceClient, sandboxName string) *pb.DaemonInfoResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := client.DaemonInfo(ctx, &pb.DaemonInfoRequest{})
+	if err != nil {
+		utils.DebugPrintf("Could not check the daemon version: %v\n", err)
+		return nil
+	}
+	printDaemonUpgradeHint(info, sandboxName)
+	return info
+}
+
+// daemonCredentialsName is the name the Anthropic API key is stored under in the daemon of a sandbox
+const daemonCredentialsName = "default"
+
+// storeDaemonCredentials sends the Anthropic API key to the daemon of a sandbox once, which keeps it as
+// its default credentials for tasks to refer to. The daemon only keeps the key in memory unless persist
+// is set. It returns false for daemons older than SetCredentials, which only accept the key with each task.
+func storeDaemonCredentials(client pb.AgentServiceClient, apiKey string, persist bool) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := client.SetCredentials(ctx, &pb.SetCredentialsRequest{
+		Name:            daemonCredentialsName,
+		AnthropicApiKey: apiKey,
+		Persist:         persist,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		utils.DebugPrintf("Daemon does not support SetCredentials, sending the API key with each task\n")
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to send credentials to daemon: %w", err)
+	}
+	if !resp.Success {
+		return false, fmt.Errorf("daemon rejected credentials: %s", resp.Message)
+	}
+	return true, nil
+}
+
+// storeSandboxCredentials sends the Anthropic API key to the daemon of a new sandbox, see storeDaemonCredentials
+func storeSandboxCredentials(sandboxInfo *sandbox.SandboxInfo, apiKey string, persist bool) error {
+	conn, cleanup, err := dialSandboxDaemon(sandboxInfo)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	_, err = storeDaemonCredentials(pb.NewAgentServiceClient(conn), apiKey, persist)
+	return err
+}
+
+// setTaskCredentials makes a task refer to the credentials stored in the daemon by name. The API key from
+// loadAPIKey is only sent with the task if info does not list the credentials, which is the case for
+// daemons older than SetCredentials and for daemons that kept the key in memory and restarted since.
+func setTaskCredentials(req *pb.ExecuteClaudeRequest, info *pb.DaemonInfoResponse, loadAPIKey func() (string, error)) error {
+	for _, name := range info.GetCredentials() {
+		if name == daemonCredentialsName {
+			req.CredentialsName = daemonCredentialsName
+			return nil
+		}
+	}
+
+	utils.DebugPrintf("Daemon does not hold the %q credentials, sending the API key with the task\n", daemonCredentialsName)
+	apiKey, err := loadAPIKey()
+	if err != nil {
+		return err
+	}
+	req.AnthropicApiKey = apiKey
+	return nil
+}
+
+// upgradeSandboxDaemon installs the embedded daemon in a sandbox and restarts
+// it if the embedded daemon is newer than the running one, or always with force
+func upgradeSandboxDaemon(identifier string, preferLocal, preferRemote, force bool) error {
+	sandboxInfo, provider, err := findSandbox(identifier, preferLocal, preferRemote)
+	if err != nil {
+		return fmt.Errorf("failed to find sandbox: %w", err)
+	}
+	if !isSandboxRunning(sandboxInfo) {
+		return fmt.Errorf("sandbox '%s' is not running (state: %s)", sandboxInfo.Name, sandboxInfo.State)
+	}
+
+	embeddedDaemon := daemon.NewEmbeddedDaemon()
+	if embeddedDaemon.Size() == 0 {
+		return fmt.Errorf("no embedded daemon binary found")
+	}
+
+	current := "unknown version"
+	info, err := getSandboxDaemonInfo(sandboxInfo, 10*time.Second)
+	switch {
+	case err != nil && !force:
+		return fmt.Errorf("daemon is not reachable, use --force to reinstall it: %w", err)
+	case err != nil:
+		fmt.Printf("⚠️  Daemon is not reachable, reinstalling it: %s\n", err)
+	case !force && !embeddedDaemon.IsNewerThan(info.Version, info.BuildTime):
+		fmt.Printf("✅ Daemon %s in sandbox '%s' is up to date (embedded: %s)\n", info.Version, sandboxInfo.Name, daemon.Version)
+		return nil
+	case info.RunningTasks > 0 || info.QueuedTasks > 0:
+		if !force {
+			return fmt.Errorf("daemon has %d running and %d queued tasks, wait for them to finish or use --force to interrupt them", info.RunningTasks, info.QueuedTasks)
+		}
+		fmt.Printf("⚠️  Interrupting %d running and %d queued tasks\n", info.RunningTasks, info.QueuedTasks)
+		current = info.Version
+	default:
+		current = info.Version
+	}
+
+	fmt.Printf("⬆️  Upgrading daemon in sandbox '%s' from %s to %s...\n", sandboxInfo.Name, current, daemon.Version)
+	if err := provider.UpgradeDaemon(sandboxInfo); err != nil {
+		return fmt.Errorf("failed to install daemon: %w", err)
+	}
+
+	// The new daemon loads the task history before it reports as serving
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		upgraded, err := getSandboxDaemonInfo(sandboxInfo, 5*time.Second)
+		if err == nil {
+			fmt.Printf("✅ Daemon %s is running in sandbox '%s'\n", upgraded.Version, sandboxInfo.Name)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("upgraded daemon did not become ready: %w", err)
+		}
+		utils.DebugPrintf("Waiting for upgraded daemon: %v\n", err)
+		time.Sleep(2 * time.Second)
+	}
+}
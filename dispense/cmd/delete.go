@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"cli/pkg/database"
 	"cli/pkg/sandbox"
 	"cli/pkg/sandbox/local"
 	"cli/pkg/sandbox/remote"
@@ -89,11 +90,23 @@ var deleteCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "Error: Failed to delete sandbox: %s\n", err)
 			os.Exit(1)
 		}
+		forgetDaemonCredentials(sandboxInfo.ID)
 
 		fmt.Printf("✅ Successfully deleted sandbox: %s\n", sandboxInfo.Name)
 	},
 }
 
+// forgetDaemonCredentials removes the stored daemon credentials of a deleted sandbox
+func forgetDaemonCredentials(sandboxID string) {
+	db, err := database.NewSandboxDB()
+	if err == nil {
+		err = db.DeleteDaemonCredentials(sandboxID)
+	}
+	if err != nil {
+		utils.DebugPrintf("Failed to delete daemon credentials of sandbox %s: %v\n", sandboxID, err)
+	}
+}
+
 // findLocalSandbox tries to find a sandbox in the local provider by name or ID
 func findLocalSandbox(provider *local.Provider, identifier string) (*sandbox.SandboxInfo, error) {
 	sandboxes, err := provider.List()
@@ -209,6 +222,7 @@ func deleteAllSandboxes(force bool) {
 			fmt.Printf(" ❌ FAILED: %s\n", err)
 			failureCount++
 		} else {
+			forgetDaemonCredentials(sb.ID)
 			fmt.Printf(" ✅ SUCCESS\n")
 			successCount++
 		}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"cli/pkg/project"
+	"cli/pkg/sandbox"
+	"cli/pkg/sandbox/remote"
+	"cli/pkg/utils"
+	pb "cli/proto"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <sandbox-name>",
+	Short: "Show what changed in the workspace of a sandbox",
+	Long: `Show the changes made in the workspace of a sandbox since the commit it
+started from: commits made in the sandbox as well as staged, unstaged and
+untracked files. Files ignored by git are left out.
+
+Without flags the changes are printed as a unified diff, which can be applied
+elsewhere with git apply. Local sandboxes backed by a git worktree are diffed
+on this machine, other sandboxes by their daemon.
+
+Examples:
+  dispense diff my-project                  # Unified diff of all changes
+  dispense diff my-project --stat           # Changed files with line counts
+  dispense diff my-project --name-only      # Only the paths of changed files
+  dispense diff my-project --base main      # Changes since the main branch
+  dispense diff my-project | git apply      # Apply the changes to this checkout`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		stat, _ := cmd.Flags().GetBool("stat")
+		nameOnly, _ := cmd.Flags().GetBool("name-only")
+		baseRef, _ := cmd.Flags().GetString("base")
+
+		if stat && nameOnly {
+			fmt.Fprintf(os.Stderr, "❌ --stat and --name-only cannot be used together\n")
+			os.Exit(1)
+		}
+		if strings.HasPrefix(baseRef, "-") {
+			fmt.Fprintf(os.Stderr, "❌ Invalid --base %q\n", baseRef)
+			os.Exit(1)
+		}
+
+		sandboxInfo, err := findSandboxByName(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+			os.Exit(1)
+		}
+
+		diff, err := getWorkspaceDiff(sandboxInfo, baseRef, !stat && !nameOnly)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to get workspace diff: %s\n", err)
+			os.Exit(1)
+		}
+
+		switch {
+		case nameOnly:
+			for _, file := range diff.Files {
+				fmt.Println(file.Path)
+			}
+		case stat:
+			printDiffStat(sandboxInfo.Name, diff)
+		default:
+			printDiffPatches(diff)
+		}
+	},
+}
+
+func init() {
+	diffCmd.Flags().Bool("stat", false, "Show the changed files with the number of added and deleted lines")
+	diffCmd.Flags().Bool("name-only", false, "Show only the paths of the changed files")
+	diffCmd.Flags().String("base", "", "Commit, branch or tag to compare with (default: the commit the workspace started from)")
+}
+
+// getWorkspaceDiff gets the changes in the workspace of a sandbox since baseRef, or since the commit it
+// started from if baseRef is empty
+func getWorkspaceDiff(sandboxInfo *sandbox.SandboxInfo, baseRef string, includePatches bool) (*pb.WorkspaceDiffResponse, error) {
+	// The .git file of a local sandbox's worktree points to the repository on this
+	// machine, so git cannot open it inside the container
+	if sandboxInfo.Type == sandbox.TypeLocal {
+		if projectPath, ok := sandboxInfo.Metadata["project_path"].(string); ok && project.IsGitWorktree(projectPath) {
+			if baseRef == "" {
+				baseRef, _ = sandboxInfo.Metadata["base_commit"].(string)
+			}
+			utils.DebugPrintf("Diffing worktree %s on the host against %q\n", projectPath, baseRef)
+			return project.WorkspaceDiff(context.Background(), projectPath, baseRef, includePatches)
+		}
+	}
+
+	// Local sandboxes leave the directory to the daemon, which knows where the project was set up
+	var workDir string
+	if sandboxInfo.Type == sandbox.TypeRemote {
+		remoteProvider, err := remote.NewProvider()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote provider: %w", err)
+		}
+		workDir, err = remoteProvider.GetWorkDir(sandboxInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get working directory: %w", err)
+		}
+	}
+
+	daemonAddr, dialOpts, cleanup, err := getSandboxDaemonConnection(sandboxInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daemon connection: %w", err)
+	}
+	defer cleanup()
+
+	utils.DebugPrintf("Connecting to daemon at: %s\n", daemonAddr)
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon at %s: %w", daemonAddr, err)
+	}
+	defer conn.Close()
+
+	client := pb.NewAgentServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	return client.GetWorkspaceDiff(ctx, &pb.WorkspaceDiffRequest{
+		WorkingDirectory: workDir,
+		BaseRef:          baseRef,
+		IncludePatches:   includePatches,
+	})
+}
+
+// printDiffPatches prints the unified diff of every changed file
+func printDiffPatches(diff *pb.WorkspaceDiffResponse) {
+	for _, file := range diff.Files {
+		fmt.Print(file.Patch)
+		if file.PatchTruncated {
+			fmt.Fprintf(os.Stderr, "⚠️  Diff of %s was truncated, use --stat for its line counts\n", file.Path)
+		}
+	}
+}
+
+// printDiffStat prints the changed files with their status and line counts
+func printDiffStat(sandboxName string, diff *pb.WorkspaceDiffResponse) {
+	base := shortCommit(diff.BaseCommit)
+	if diff.Branch != "" {
+		fmt.Printf("📝 Changes in %s since %s (branch %s)\n", sandboxName, base, diff.Branch)
+	} else {
+		fmt.Printf("📝 Changes in %s since %s\n", sandboxName, base)
+	}
+
+	if len(diff.Files) == 0 {
+		fmt.Printf("✅ No changes\n")
+		return
+	}
+
+	width := 0
+	for _, file := range diff.Files {
+		if len(diffStatPath(file)) > width {
+			width = len(diffStatPath(file))
+		}
+	}
+
+	var additions, deletions int32
+	for _, file := range diff.Files {
+		counts := fmt.Sprintf("+%d -%d", file.Additions, file.Deletions)
+		if file.Binary {
+			counts = "binary"
+		}
+		fmt.Printf("  %s %-*s  %s\n", diffStatusLetter(file.Status), width, diffStatPath(file), counts)
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+
+	fmt.Printf("\n%d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)\n", len(diff.Files), additions, deletions)
+}
+
+// diffStatPath returns the path of a changed file as shown by --stat
+func diffStatPath(file *pb.FileDiff) string {
+	if file.OldPath != "" {
+		return fmt.Sprintf("%s -> %s", file.OldPath, file.Path)
+	}
+	return file.Path
+}
+
+// diffStatusLetter returns the letter git status uses for a change
+func diffStatusLetter(status pb.FileDiff_Status) string {
+	switch status {
+	case pb.FileDiff_ADDED:
+		return "A"
+	case pb.FileDiff_DELETED:
+		return "D"
+	case pb.FileDiff_RENAMED:
+		return "R"
+	case pb.FileDiff_UNTRACKED:
+		return "?"
+	case pb.FileDiff_TYPE_CHANGED:
+		return "T"
+	default:
+		return "M"
+	}
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
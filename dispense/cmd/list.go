@@ -5,7 +5,9 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"cli/pkg/daemon"
 	"cli/pkg/sandbox"
 	"cli/pkg/sandbox/local"
 	"cli/pkg/sandbox/remote"
@@ -127,7 +129,7 @@ var listCmd = &cobra.Command{
 
 		// Print header
 		if verbose {
-			fmt.Fprintln(w, "ID\tName\tType\tState\tShell Command\tMetadata")
+			fmt.Fprintln(w, "ID\tName\tType\tState\tShell Command\tDaemon\tMetadata")
 		} else {
 			fmt.Fprintln(w, "ID\tName\tType\tState\tShell Command")
 		}
@@ -136,12 +138,13 @@ var listCmd = &cobra.Command{
 		for _, sb := range allSandboxes {
 			if verbose {
 				metadataStr := formatMetadata(sb.Metadata)
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 					truncateString(sb.ID, 36),
 					sb.Name,
 					sb.Type,
 					sb.State,
 					sb.ShellCommand,
+					listDaemonStatus(sb),
 					metadataStr,
 				)
 			} else {
@@ -185,6 +188,25 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// listDaemonStatus describes the daemon of a sandbox for the verbose listing
+func listDaemonStatus(sb *sandbox.SandboxInfo) string {
+	if !isSandboxRunning(sb) {
+		return "-"
+	}
+
+	info, err := getSandboxDaemonInfo(sb, 5*time.Second)
+	if err != nil {
+		utils.DebugPrintf("Failed to get daemon info of %s: %v\n", sb.Name, err)
+		return "unreachable"
+	}
+
+	summary := formatDaemonSummary(info)
+	if daemon.NewEmbeddedDaemon().IsNewerThan(info.Version, info.BuildTime) {
+		summary += " (upgrade available)"
+	}
+	return summary
+}
+
 func formatMetadata(metadata map[string]interface{}) string {
 	if len(metadata) == 0 {
 		return "-"
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"cli/pkg/utils"
+	pb "cli/proto"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <sandbox-name>",
+	Short: "Show the daemon log of a sandbox",
+	Long: `Show the log of the daemon running in a sandbox: its own log lines and the
+lifecycle events of Claude tasks (created, started, output, finished).
+
+The daemon keeps its most recent log entries in memory, older entries are not
+available.
+
+Examples:
+  dispense logs my-project                   # Recent daemon log
+  dispense logs my-project --follow          # Keep streaming new entries
+  dispense logs my-project --task <task-id>  # Events of a single task
+  dispense logs my-project --level warn      # Only warnings and errors
+  dispense logs my-project --since 30m       # Entries of the last 30 minutes`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		follow, _ := cmd.Flags().GetBool("follow")
+		taskID, _ := cmd.Flags().GetString("task")
+		levelFlag, _ := cmd.Flags().GetString("level")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+
+		level, ok := pb.LogsResponse_Level_value[strings.ToUpper(levelFlag)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "❌ Invalid --level %q: use info, warn or error\n", levelFlag)
+			os.Exit(1)
+		}
+
+		req := &pb.LogsRequest{
+			TaskId:   taskID,
+			MinLevel: pb.LogsResponse_Level(level),
+			Follow:   follow,
+		}
+
+		if sinceFlag != "" {
+			since, err := parseSince(sinceFlag, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+				os.Exit(1)
+			}
+			req.Since = since.Unix()
+		}
+
+		if err := streamDaemonLogs(args[0], req); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to stream daemon logs: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep streaming new log entries")
+	logsCmd.Flags().String("task", "", "Only show the events of this task")
+	logsCmd.Flags().String("level", "info", "Minimum level to show: info, warn or error")
+	logsCmd.Flags().String("since", "", "Only show entries since a duration ago (e.g. 30m, 1d) or a date (YYYY-MM-DD)")
+}
+
+// streamDaemonLogs prints the daemon log entries of a sandbox matching req
+func streamDaemonLogs(sandboxName string, req *pb.LogsRequest) error {
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxName)
+	if err != nil {
+		return fmt.Errorf("failed to get daemon connection: %w", err)
+	}
+	defer cleanup()
+
+	utils.DebugPrintf("Connecting to daemon at: %s\n", daemonAddr)
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon at %s: %w", daemonAddr, err)
+	}
+	defer conn.Close()
+
+	client := pb.NewProjectServiceClient(conn)
+
+	// Stop following on Ctrl+C
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stream, err := client.Logs(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to start log stream: %w", err)
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("log stream failed: %w", err)
+		}
+
+		printLogEntry(entry)
+	}
+}
+
+// printLogEntry prints a daemon log entry as a single line
+func printLogEntry(entry *pb.LogsResponse) {
+	timestamp := time.Unix(entry.Timestamp, 0).Format("2006-01-02 15:04:05")
+
+	var emoji string
+	switch entry.Level {
+	case pb.LogsResponse_WARN:
+		emoji = "⚠️ "
+	case pb.LogsResponse_ERROR:
+		emoji = "❌"
+	default:
+		emoji = "  "
+	}
+
+	if entry.Event != "" && entry.Event != "output" {
+		fmt.Printf("%s %s [%s] %s\n", timestamp, emoji, entry.Event, entry.LogEntry)
+	} else {
+		fmt.Printf("%s %s %s\n", timestamp, emoji, entry.LogEntry)
+	}
+}
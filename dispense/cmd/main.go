@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
 
 	"cli/pkg/utils"
@@ -68,6 +69,12 @@ func init() {
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(claudeCmd)
 	rootCmd.AddCommand(waitCmd)
+	rootCmd.AddCommand(usageCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(portsCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(prCmd)
 	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(execCmd)
@@ -85,8 +92,14 @@ func init() {
 	rootCmd.Flags().Int32P("auto-stop", "a", 60, "Auto-stop interval in minutes (0 = disabled, remote only)")
 	rootCmd.Flags().Bool("skip-copy", false, "Skip copying files to sandbox")
 	rootCmd.Flags().Bool("skip-daemon", false, "Skip installing daemon to sandbox")
+	rootCmd.Flags().Bool("skip-init", false, "Skip the project setup commands, toolchain detection and dependency install")
 	rootCmd.Flags().String("model", "", "Anthropic model to use (e.g., claude-3-opus-20240229)")
 	rootCmd.Flags().String("task", "", "Task description (skips task prompt)")
+	rootCmd.Flags().Duration("task-timeout", 0, "Default time limit for Claude tasks in the sandbox, e.g. 2h (0 = no limit)")
+	rootCmd.Flags().Duration("idle-timeout", 0, "Default time a Claude task in the sandbox may go without output, e.g. 15m (0 = no limit)")
+	rootCmd.Flags().Bool("tls", false, "Connect to the sandbox's daemon over mutual TLS with a certificate authority generated for the sandbox")
+	rootCmd.Flags().Bool("persist-credentials", false, "Let the sandbox's daemon keep the Anthropic API key in a file only it can read, so it survives daemon restarts (default: memory only)")
+	rootCmd.Flags().Bool("auto-pr", false, "Open a GitHub pull request, or push to the pull request's branch, once Claude has finished working on the GitHub issue or pull request")
 }
 
 func Execute() {
@@ -97,6 +110,9 @@ func Execute() {
 }
 
 func main() {
+	// Mask secrets in everything written through the standard logger
+	log.SetOutput(utils.NewRedactingWriter(os.Stderr))
+
 	utils.DebugPrintf("main() function called\n")
 	Execute()
 }
\ No newline at end of file
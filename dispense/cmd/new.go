@@ -5,14 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"cli/pkg/config"
+	"cli/pkg/daemon"
+	"cli/pkg/database"
+	"cli/pkg/github"
+	"cli/pkg/project"
 	"cli/pkg/sandbox"
 	"cli/pkg/sandbox/local"
 	"cli/pkg/sandbox/remote"
@@ -20,8 +27,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials/insecure"
 
 	pb "cli/proto"
 )
@@ -47,9 +52,15 @@ var newCmd = &cobra.Command{
 		name, _ := cmd.Flags().GetString("name")
 		skipCopy, _ := cmd.Flags().GetBool("skip-copy")
 		skipDaemon, _ := cmd.Flags().GetBool("skip-daemon")
+		skipInit, _ := cmd.Flags().GetBool("skip-init")
 		group, _ := cmd.Flags().GetString("group")
 		model, _ := cmd.Flags().GetString("model")
 		task, _ := cmd.Flags().GetString("task")
+		taskTimeout, _ := cmd.Flags().GetDuration("task-timeout")
+		idleTimeout, _ := cmd.Flags().GetDuration("idle-timeout")
+		useTLS, _ := cmd.Flags().GetBool("tls")
+		autoPR, _ := cmd.Flags().GetBool("auto-pr")
+		persistCredentials, _ := cmd.Flags().GetBool("persist-credentials")
 
 		// Get branch name - either from flag or prompt
 		var branchName string
@@ -85,14 +96,27 @@ var newCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		// A pull request is opened or updated for the work Claude does on a GitHub issue or pull request,
+		// which needs a token to push with
+		if autoPR {
+			if !taskData.isGitHubTask() || skipDaemon {
+				fmt.Fprintf(os.Stderr, "❌ --auto-pr needs a GitHub issue or pull request task for Claude to work on\n")
+				os.Exit(1)
+			}
+			if _, err := config.LoadGitHubToken(); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ --auto-pr needs a GitHub token: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
 		// Auto-skip file copy only for remote sandboxes when GitHub issue is provided
-		if !skipCopy && taskData != nil && taskData.GitHubIssue != nil && isRemote {
+		if !skipCopy && taskData.isGitHubTask() && isRemote {
 			skipCopy = true
-			fmt.Printf("🔗 GitHub issue detected for remote sandbox - automatically skipping file copy\n")
-			fmt.Printf("   Working on: %s\n", taskData.GitHubIssue.URL)
-		} else if taskData != nil && taskData.GitHubIssue != nil && !isRemote {
-			fmt.Printf("🔗 GitHub issue detected for local sandbox - creating empty workspace\n")
-			fmt.Printf("   Working on: %s\n", taskData.GitHubIssue.URL)
+			fmt.Printf("🔗 %s detected for remote sandbox - automatically skipping file copy\n", taskData.gitHubKind())
+			fmt.Printf("   Working on: %s\n", taskData.gitHubURL())
+		} else if taskData.isGitHubTask() && !isRemote {
+			fmt.Printf("🔗 %s detected for local sandbox - creating empty workspace\n", taskData.gitHubKind())
+			fmt.Printf("   Working on: %s\n", taskData.gitHubURL())
 		}
 
 		// The source directory is the directory from which the files will be copied
@@ -109,8 +133,8 @@ var newCmd = &cobra.Command{
 
 			// Validate git repository (unless force flag is used or GitHub issue provided)
 			if !force && !isGitRepository(sourceDirectory) {
-				if taskData != nil && taskData.GitHubIssue != nil {
-					fmt.Printf("ℹ️  Note: Working on GitHub issue - local git repository not required\n")
+				if taskData.isGitHubTask() {
+					fmt.Printf("ℹ️  Note: Working on %s - local git repository not required\n", taskData.gitHubKind())
 				} else {
 					fmt.Printf("⚠️  Warning: Current directory does not appear to be a git repository\n")
 					if !confirmContinue() {
@@ -168,9 +192,32 @@ var newCmd = &cobra.Command{
 			BranchName:  branchName,
 			SourceDir:   sourceDirectory,
 			TaskData:    taskDataJSON,
-			GitHubIssue: taskData != nil && taskData.GitHubIssue != nil,
+			GitHubIssue: taskData.isGitHubTask(),
 			Group:       group,
 			Model:       model,
+			TaskTimeout: taskTimeout,
+			IdleTimeout: idleTimeout,
+		}
+
+		// Settings checked in with the project fill in what the flags left unset. The workspace of a
+		// GitHub issue or pull request is a clone, which uses the settings of the current directory
+		// only if that is a checkout of the same repository.
+		configDirectory := sourceDirectory
+		if taskData.isGitHubTask() {
+			owner, repo := taskData.gitHubRepository()
+			configDirectory = repositoryCheckout(owner, repo)
+		}
+		var projectConfig *project.Config
+		if configDirectory != "" {
+			projectConfig, err = project.LoadConfig(configDirectory)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+				os.Exit(1)
+			}
+			if projectConfig != nil {
+				fmt.Printf("📄 Using project settings from %s\n", filepath.Base(projectConfig.Path))
+				projectConfig.Apply(opts)
+			}
 		}
 
 		// Create sandbox
@@ -188,10 +235,26 @@ var newCmd = &cobra.Command{
 		fmt.Printf("State: %s\n", sandboxInfo.State)
 
 		// Handle file copying or GitHub repo cloning
-		if taskData != nil && taskData.GitHubIssue != nil {
-			// Clone GitHub repository instead of copying files
-			fmt.Printf("📥 Cloning GitHub repository %s/%s...\n", taskData.GitHubIssue.Owner, taskData.GitHubIssue.Repo)
-			err = provider.CloneGitHubRepo(sandboxInfo, taskData.GitHubIssue.Owner, taskData.GitHubIssue.Repo, branchName)
+		if taskData.isGitHubTask() {
+			// Clone GitHub repository instead of copying files, checking out the pull request's head for pull requests
+			owner, repo := taskData.gitHubRepository()
+			pullRequest := 0
+			if taskData.GitHubPR != nil {
+				pullRequest = taskData.GitHubPR.Number
+			}
+
+			// Git in the sandbox gets the GitHub token from a credential helper that expires, so private
+			// repositories can be cloned and pushed to without the token ending up in the workspace
+			if token, err := config.LoadGitHubToken(); err == nil {
+				if err := provider.SetGitHubCredentials(sandboxInfo, token); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Could not give the sandbox GitHub credentials: %s\n", err)
+				}
+			} else {
+				utils.DebugPrintf("Cloning without a GitHub token: %s\n", err)
+			}
+
+			fmt.Printf("📥 Cloning GitHub repository %s/%s...\n", owner, repo)
+			err = provider.CloneGitHubRepo(sandboxInfo, owner, repo, branchName, pullRequest)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "❌ Failed to clone repository: %s\n", err)
 				os.Exit(1)
@@ -222,8 +285,26 @@ var newCmd = &cobra.Command{
 			fmt.Println("⏭️  Skipping file copy (--skip-copy flag used)")
 		}
 
+		// Without settings from a local checkout, the project commands are read from the workspace
+		// before the project is initialized
+		if projectConfig == nil && !skipDaemon && !skipInit {
+			workspaceConfig, err := loadWorkspaceConfig(sandboxInfo)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Could not use the project settings of the workspace: %s\n", err)
+			} else if workspaceConfig != nil {
+				fmt.Printf("📄 Using project commands from %s in the workspace\n", filepath.Base(workspaceConfig.Path))
+				workspaceConfig.ApplyCommands(opts)
+			}
+		}
+
 		// Install daemon unless skipped
 		if !skipDaemon {
+			fmt.Println("🔑 Generating daemon credentials...")
+			err = setupDaemonCredentials(provider, sandboxInfo, useTLS)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Could not set up daemon credentials, the daemon will accept unauthenticated connections: %s\n", err)
+			}
+
 			fmt.Println("🔧 Installing embedded daemon...")
 			err = provider.InstallDaemon(sandboxInfo)
 			if err != nil {
@@ -239,7 +320,7 @@ var newCmd = &cobra.Command{
 		// Get Claude API key for background tasks - check saved config first
 		var claudeApiKey string
 		if savedApiKey, err := loadSandboxClaudeConfig(); err == nil && savedApiKey != "" {
-			fmt.Printf("Using saved Anthropic API key (length: %d, starts with: %.10s...).\n", len(savedApiKey), savedApiKey)
+			fmt.Printf("Using saved Anthropic API key (%s).\n", utils.MaskSecret(savedApiKey))
 			claudeApiKey = savedApiKey
 		} else {
 			fmt.Printf("Enter your Anthropic API key for Claude: ")
@@ -262,6 +343,7 @@ var newCmd = &cobra.Command{
 		}
 
 		// Verify Claude daemon and show issue-specific instructions
+		claudeStarted := false
 		if !skipDaemon {
 			fmt.Println("🧪 Verifying Claude daemon connectivity...")
 
@@ -276,36 +358,56 @@ var newCmd = &cobra.Command{
 				fmt.Fprintf(os.Stderr, "Warning: Claude daemon not ready: %s\n", err)
 
 				// Even if daemon is not immediately ready, start the retry process for GitHub issues
-				if taskData != nil && taskData.GitHubIssue != nil {
-					fmt.Printf("🤖 Waiting for daemon to be ready and starting Claude on GitHub issue #%d...\n", taskData.GitHubIssue.Number)
+				if taskData.isGitHubTask() {
+					fmt.Printf("🤖 Waiting for daemon to be ready and starting Claude on %s...\n", taskData.gitHubTarget())
 
-					// Create the GitHub issue prompt
-					prompt := createGitHubIssuePrompt(taskData)
+					// Create the GitHub issue or pull request prompt
+					prompt := createGitHubTaskPrompt(taskData)
 
 					// Wait for daemon to be ready and then start Claude in background
-					if err := waitForDaemonAndStartClaude(sandboxInfo, prompt, claudeApiKey); err != nil {
-						fmt.Fprintf(os.Stderr, "❌ Failed to start Claude on GitHub issue: %s\n", err)
+					var initOpts *sandbox.CreateOptions
+					if !skipInit {
+						initOpts = opts
+					}
+					if err := waitForDaemonAndStartClaude(sandboxInfo, prompt, claudeApiKey, persistCredentials, initOpts); err != nil {
+						fmt.Fprintf(os.Stderr, "❌ Failed to start Claude on %s: %s\n", taskData.gitHubTarget(), err)
 					} else {
-						fmt.Printf("✅ Claude has started working on GitHub issue #%d in background!\n", taskData.GitHubIssue.Number)
+						fmt.Printf("✅ Claude has started working on %s in background!\n", taskData.gitHubTarget())
+						claudeStarted = true
 					}
 
 					fmt.Printf("📋 Claude is working in background. Monitor progress with:\n")
 					fmt.Printf("   claude %s logs\n", sandboxInfo.Name)
 				}
 			} else {
-				if taskData != nil && taskData.GitHubIssue != nil {
-					// Automatically start Claude working on the GitHub issue
-					fmt.Printf("✅ Claude is ready to work on the issue!\n")
-					fmt.Printf("🤖 Starting Claude to work on GitHub issue #%d...\n", taskData.GitHubIssue.Number)
+				// The daemon keeps the API key for every later task, which refers to it by name
+				if claudeApiKey != "" {
+					if err := storeSandboxCredentials(sandboxInfo, claudeApiKey, persistCredentials); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: Could not store the API key in the daemon: %s\n", err)
+					}
+				}
 
-					// Create the GitHub issue prompt
-					prompt := createGitHubIssuePrompt(taskData)
+				// Install dependencies and build before Claude starts working
+				if !skipInit {
+					if err := initializeProject(sandboxInfo, opts); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: Could not initialize project: %s\n", err)
+					}
+				}
+
+				if taskData.isGitHubTask() {
+					// Automatically start Claude working on the GitHub issue or pull request
+					fmt.Printf("✅ Claude is ready to work on %s!\n", taskData.gitHubTarget())
+					fmt.Printf("🤖 Starting Claude to work on %s...\n", taskData.gitHubTarget())
+
+					// Create the GitHub issue or pull request prompt
+					prompt := createGitHubTaskPrompt(taskData)
 
 					// Start Claude in background since daemon is already ready
 					if err := startClaudeCommandInBackground(sandboxInfo, prompt, claudeApiKey); err != nil {
-						fmt.Fprintf(os.Stderr, "❌ Failed to start Claude on GitHub issue: %s\n", err)
+						fmt.Fprintf(os.Stderr, "❌ Failed to start Claude on %s: %s\n", taskData.gitHubTarget(), err)
 					} else {
-						fmt.Printf("✅ Claude has started working on GitHub issue #%d in background!\n", taskData.GitHubIssue.Number)
+						fmt.Printf("✅ Claude has started working on %s in background!\n", taskData.gitHubTarget())
+						claudeStarted = true
 					}
 
 					fmt.Printf("📋 Claude is working in background. Monitor progress with: claude %s logs\n", sandboxInfo.Name)
@@ -324,10 +426,51 @@ var newCmd = &cobra.Command{
 			fmt.Println("\n📋 Local sandbox information:")
 			fmt.Printf("  • Container access: %s\n", sandboxInfo.ShellCommand)
 			fmt.Printf("  • Type: Docker container\n")
+			if len(opts.Ports) > 0 {
+				if containerName, ok := sandboxInfo.Metadata["container_name"].(string); ok {
+					fmt.Printf("  • Published ports: docker port %s\n", containerName)
+				}
+			}
 		} else {
 			fmt.Println("\n📋 Remote sandbox information:")
 			fmt.Printf("  • SSH access: %s\n", sandboxInfo.ShellCommand)
 			fmt.Printf("  • Type: Daytona remote sandbox\n")
+			if portURLs, ok := sandboxInfo.Metadata["port_urls"].(map[int]string); ok {
+				var shown []string
+				for _, port := range opts.Ports {
+					if url, ok := portURLs[port]; ok {
+						fmt.Printf("  • Port %d: %s\n", port, url)
+						shown = append(shown, strconv.Itoa(port))
+					}
+				}
+				if len(shown) > 0 {
+					fmt.Printf("  • Preview token for private sandboxes: dispense ports %s %s\n", sandboxInfo.Name, strings.Join(shown, " "))
+				}
+			}
+		}
+
+		if autoPR && claudeStarted {
+			fmt.Printf("\n⏳ Waiting for Claude to finish before opening a pull request...\n")
+			status, err := waitForSandboxTask(sandboxInfo)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to wait for Claude: %s\n", err)
+				fmt.Printf("   Open the pull request later with: dispense pr %s\n", sandboxInfo.Name)
+				os.Exit(1)
+			}
+			if status.State != pb.TaskStatusResponse_COMPLETED {
+				fmt.Fprintf(os.Stderr, "⚠️  Claude's task ended as %s, not opening a pull request\n", getTaskStateText(status.State))
+				fmt.Printf("   Review the work with: dispense diff %s\n", sandboxInfo.Name)
+				fmt.Printf("   Open the pull request anyway with: dispense pr %s\n", sandboxInfo.Name)
+				os.Exit(1)
+			}
+
+			fmt.Printf("📤 Opening a pull request for sandbox %s...\n", sandboxInfo.Name)
+			result, err := openSandboxPullRequest(sandboxInfo, provider, &prOptions{})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to open pull request: %s\n", err)
+				os.Exit(1)
+			}
+			printPullRequestResult(result)
 		}
 	},
 }
@@ -376,6 +519,50 @@ func isGitRepository(path string) bool {
 	return err == nil
 }
 
+// repositoryCheckout returns the current directory if it is a checkout of the
+// GitHub repository owner/repo, and an empty string otherwise
+func repositoryCheckout(owner, repo string) string {
+	cwd, err := os.Getwd()
+	if err != nil || !isGitRepository(cwd) {
+		return ""
+	}
+
+	output, err := exec.Command("git", "-C", cwd, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	originOwner, originRepo, ok := github.ParseRemoteURL(string(output))
+	if !ok || !strings.EqualFold(originOwner, owner) || !strings.EqualFold(originRepo, repo) {
+		utils.DebugPrintf("Current directory is not a checkout of %s/%s, ignoring its project settings\n", owner, repo)
+		return ""
+	}
+	return cwd
+}
+
+// loadWorkspaceConfig reads the configuration file in the workspace of a sandbox. It returns nil
+// if the workspace has no configuration file.
+func loadWorkspaceConfig(sandboxInfo *sandbox.SandboxInfo) (*project.Config, error) {
+	workDir, err := getWorkDirFromProvider(sandboxInfo.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	for _, name := range project.ConfigFileNames {
+		path := workDir + "/" + name
+		if _, err := executeSandboxCommand(sandboxInfo.Name, []string{"test", "-f", path}); err != nil {
+			continue
+		}
+
+		data, err := executeSandboxCommand(sandboxInfo.Name, []string{"cat", path})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return project.ParseConfig(data, path)
+	}
+
+	return nil, nil
+}
+
 func confirmContinue() bool {
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Printf("Continue anyway? (y/N): ")
@@ -522,22 +709,101 @@ func createGitHubIssuePrompt(taskData *TaskData) string {
 	return taskPrompt.String()
 }
 
-// waitForDaemonAndStartClaude waits for daemon to be ready then starts Claude with the given prompt in background
-func waitForDaemonAndStartClaude(sandboxInfo *sandbox.SandboxInfo, prompt string, apiKey string) error {
+// createGitHubTaskPrompt creates the prompt for the GitHub issue or pull request a task is about
+func createGitHubTaskPrompt(taskData *TaskData) string {
+	if taskData != nil && taskData.GitHubPR != nil {
+		return createGitHubPRPrompt(taskData)
+	}
+	return createGitHubIssuePrompt(taskData)
+}
+
+// createGitHubPRPrompt creates a prompt for addressing the unresolved review comments on a GitHub pull request
+func createGitHubPRPrompt(taskData *TaskData) string {
+	pr := taskData.GitHubPR
+
+	var taskPrompt strings.Builder
+
+	taskPrompt.WriteString("I need help addressing the review comments on this GitHub pull request:\n\n")
+	taskPrompt.WriteString(fmt.Sprintf("**Pull request**: %s\n", pr.Title))
+	taskPrompt.WriteString(fmt.Sprintf("**Repository**: %s/%s\n", pr.Owner, pr.Repo))
+	taskPrompt.WriteString(fmt.Sprintf("**Pull request #%d**: %s\n", pr.Number, pr.URL))
+	taskPrompt.WriteString(fmt.Sprintf("**Branch**: %s, to be merged into %s. Its changes are checked out in the workspace.\n\n", pr.HeadBranch, pr.BaseBranch))
+
+	if pr.Body != "" {
+		taskPrompt.WriteString(fmt.Sprintf("**Description**:\n%s\n\n", pr.Body))
+	}
+
+	if len(pr.Reviews) > 0 {
+		taskPrompt.WriteString("**Reviews**:\n")
+		for _, review := range pr.Reviews {
+			state := "commented"
+			if review.State == "CHANGES_REQUESTED" {
+				state = "requested changes"
+			}
+			taskPrompt.WriteString(fmt.Sprintf("- @%s %s: %s\n", review.Author, state, review.Body))
+		}
+		taskPrompt.WriteString("\n")
+	}
+
+	if len(pr.ReviewThreads) > 0 {
+		taskPrompt.WriteString("**Unresolved review comments**:\n\n")
+		for _, thread := range pr.ReviewThreads {
+			location := thread.Path
+			if thread.Line > 0 {
+				location = fmt.Sprintf("%s:%d", thread.Path, thread.Line)
+			}
+			location = fmt.Sprintf("`%s`", location)
+			if thread.Outdated {
+				location += " (outdated, the code has changed since)"
+			}
+			taskPrompt.WriteString(location + "\n")
+			for _, comment := range thread.Comments {
+				taskPrompt.WriteString(fmt.Sprintf("- @%s: %s\n", comment.Author, comment.Body))
+			}
+			taskPrompt.WriteString("\n")
+		}
+	} else {
+		taskPrompt.WriteString("There are no unresolved review comments on specific lines.\n\n")
+	}
+
+	if taskData.AdditionalText != "" {
+		taskPrompt.WriteString(fmt.Sprintf("**Additional context**:\n%s\n\n", taskData.AdditionalText))
+	}
+
+	taskPrompt.WriteString("Please address each unresolved review comment. Start by reading the code the comments refer to, then make the changes the reviewers asked for. If a comment no longer applies or you disagree with it, leave the code as it is and explain why. When you are done, summarize how you addressed each comment.")
+
+	return taskPrompt.String()
+}
+
+// waitForDaemonAndStartClaude waits for daemon to be ready then starts Claude with the given prompt in background,
+// initializing the project first with the settings of initOpts unless it is nil. The API key is stored in the
+// daemon first, in a file if persistCredentials is set.
+func waitForDaemonAndStartClaude(sandboxInfo *sandbox.SandboxInfo, prompt string, apiKey string, persistCredentials bool, initOpts *sandbox.CreateOptions) error {
 	utils.DebugPrintf("Waiting for daemon and starting Claude with prompt for sandbox: %s\n", sandboxInfo.Name)
 
-	// Wait up to 60 seconds for daemon to be ready
-	maxRetries := 12 // 12 retries * 5 seconds = 60 seconds
+	// Wait up to 60 seconds for daemon to be ready, each health check waits
+	// up to 5 seconds for the daemon to accept connections
+	maxRetries := 10 // 10 retries * (5 seconds + 1 second pause) = 60 seconds
 	for i := 0; i < maxRetries; i++ {
-		time.Sleep(5 * time.Second)
-
 		// Check if daemon is ready
 		if isDaemonReady(sandboxInfo) {
+			if apiKey != "" {
+				if err := storeSandboxCredentials(sandboxInfo, apiKey, persistCredentials); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Could not store the API key in the daemon: %s\n", err)
+				}
+			}
+			if initOpts != nil {
+				if err := initializeProject(sandboxInfo, initOpts); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Could not initialize project: %s\n", err)
+				}
+			}
+
 			utils.DebugPrintf("Daemon ready, starting Claude in background\n")
 			return startClaudeCommandInBackground(sandboxInfo, prompt, apiKey)
 		}
 
 		utils.DebugPrintf("Daemon not ready yet, retry %d/%d\n", i+1, maxRetries)
+		time.Sleep(time.Second)
 	}
 
 	return fmt.Errorf("daemon not ready after 60 seconds")
@@ -547,9 +813,47 @@ func waitForDaemonAndStartClaude(sandboxInfo *sandbox.SandboxInfo, prompt string
 type TaskData struct {
 	OriginalText  string      `json:"original_text"`
 	GitHubIssue   *GitHubIssue `json:"github_issue,omitempty"`
+	GitHubPR      *GitHubPR    `json:"github_pr,omitempty"`
 	AdditionalText string      `json:"additional_text,omitempty"`
 }
 
+// isGitHubTask reports whether the task is about a GitHub issue or pull request
+func (t *TaskData) isGitHubTask() bool {
+	return t != nil && (t.GitHubIssue != nil || t.GitHubPR != nil)
+}
+
+// gitHubRepository returns the repository of the GitHub issue or pull request the task is about
+func (t *TaskData) gitHubRepository() (owner, repo string) {
+	if t.GitHubPR != nil {
+		return t.GitHubPR.Owner, t.GitHubPR.Repo
+	}
+	return t.GitHubIssue.Owner, t.GitHubIssue.Repo
+}
+
+// gitHubKind returns what the task is about, "GitHub issue" or "GitHub pull request"
+func (t *TaskData) gitHubKind() string {
+	if t.GitHubPR != nil {
+		return "GitHub pull request"
+	}
+	return "GitHub issue"
+}
+
+// gitHubTarget describes the GitHub issue or pull request the task is about, such as "GitHub issue #12"
+func (t *TaskData) gitHubTarget() string {
+	if t.GitHubPR != nil {
+		return fmt.Sprintf("GitHub pull request #%d", t.GitHubPR.Number)
+	}
+	return fmt.Sprintf("GitHub issue #%d", t.GitHubIssue.Number)
+}
+
+// gitHubURL returns the URL of the GitHub issue or pull request the task is about
+func (t *TaskData) gitHubURL() string {
+	if t.GitHubPR != nil {
+		return t.GitHubPR.URL
+	}
+	return t.GitHubIssue.URL
+}
+
 // GitHubIssue represents GitHub issue information
 type GitHubIssue struct {
 	URL         string `json:"url"`
@@ -563,11 +867,30 @@ type GitHubIssue struct {
 	CreatedAt   string `json:"created_at"`
 }
 
+// GitHubPR represents GitHub pull request information with the review feedback Claude should address
+type GitHubPR struct {
+	URL           string                 `json:"url"`
+	Owner         string                 `json:"owner"`
+	Repo          string                 `json:"repo"`
+	Number        int                    `json:"number"`
+	Title         string                 `json:"title"`
+	Body          string                 `json:"body"`
+	State         string                 `json:"state"`
+	User          string                 `json:"user"`
+	HeadBranch    string                 `json:"head_branch"`
+	HeadOwner     string                 `json:"head_owner,omitempty"` // empty when the head repository was deleted
+	HeadRepo      string                 `json:"head_repo,omitempty"`
+	BaseBranch    string                 `json:"base_branch"`
+	ReviewThreads []*github.ReviewThread `json:"review_threads,omitempty"` // unresolved review threads
+	Reviews       []*github.Review       `json:"reviews,omitempty"`        // reviews requesting changes or commenting on the pull request as a whole
+}
+
 // promptForTask prompts the user to enter a task description
 func promptForTask() (string, error) {
 	fmt.Println("\n📋 Task Description")
 	fmt.Println("Enter a description of what needs to be done in this sandbox.")
 	fmt.Println("You can start with a GitHub issue URL (e.g., https://github.com/owner/repo/issues/123)")
+	fmt.Println("or a pull request URL to address its review comments (e.g., https://github.com/owner/repo/pull/45)")
 	fmt.Println("followed by additional instructions.")
 	fmt.Print("Task: ")
 
@@ -627,43 +950,115 @@ func parseTaskData(taskDescription string) (*TaskData, error) {
 		}
 	}
 
+	// Check if task starts with GitHub pull request URL, possibly of one of its tabs
+	githubPRRegex := regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/pull/(\d+)(?:/\S*)?`)
+	if matches := githubPRRegex.FindStringSubmatch(taskDescription); len(matches) == 4 {
+		owner := matches[1]
+		repo := matches[2]
+		number, _ := strconv.Atoi(matches[3])
+
+		utils.DebugPrintf("Found GitHub pull request: %s/%s#%d\n", owner, repo, number)
+
+		pr, err := fetchGitHubPR(owner, repo, number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch GitHub pull request: %w", err)
+		}
+		if pr.State != "open" {
+			return nil, fmt.Errorf("GitHub pull request %s/%s#%d is %s", owner, repo, number, pr.State)
+		}
+
+		taskData.GitHubPR = pr
+
+		// Extract additional text after the URL
+		urlEnd := githubPRRegex.FindStringIndex(taskDescription)
+		if additionalText := strings.TrimSpace(taskDescription[urlEnd[1]:]); additionalText != "" {
+			taskData.AdditionalText = additionalText
+		}
+
+		fmt.Printf("✅ Fetched GitHub pull request: %s - %d unresolved review thread(s)\n", pr.Title, len(pr.ReviewThreads))
+		if taskData.AdditionalText != "" {
+			fmt.Printf("📝 Additional instructions: %s\n", taskData.AdditionalText)
+		}
+	}
+
 	return taskData, nil
 }
 
-// fetchGitHubIssue fetches issue details from GitHub API
-func fetchGitHubIssue(owner, repo, issueNumber string) (*GitHubIssue, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%s", owner, repo, issueNumber)
-	utils.DebugPrintf("Fetching GitHub issue from: %s\n", url)
+// fetchGitHubPR fetches a pull request with its unresolved review threads and its reviews from the
+// GitHub API. The configured GitHub token is used if there is one, without it every review thread
+// counts as unresolved.
+func fetchGitHubPR(owner, repo string, number int) (*GitHubPR, error) {
+	token, err := config.LoadGitHubToken()
+	if err != nil {
+		utils.DebugPrintf("Fetching pull request without a GitHub token: %s\n", err)
+	}
+	client := github.NewClient(token)
 
-	resp, err := http.Get(url)
+	pullRequest, err := client.GetPullRequest(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := client.ListReviewThreads(owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
+	}
+	reviews, err := client.ListReviews(owner, repo, number)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch issue: %w", err)
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status %d for issue %s/%s#%s", resp.StatusCode, owner, repo, issueNumber)
+	pr := &GitHubPR{
+		URL:        pullRequest.HTMLURL,
+		Owner:      owner,
+		Repo:       repo,
+		Number:     pullRequest.Number,
+		Title:      pullRequest.Title,
+		Body:       pullRequest.Body,
+		State:      pullRequest.State,
+		User:       pullRequest.User.Login,
+		HeadBranch: pullRequest.Head.Ref,
+		BaseBranch: pullRequest.Base.Ref,
+	}
+	if pullRequest.Head.Repo != nil {
+		pr.HeadOwner = pullRequest.Head.Repo.Owner.Login
+		pr.HeadRepo = pullRequest.Head.Repo.Name
+	}
+
+	for _, thread := range threads {
+		if !thread.Resolved {
+			pr.ReviewThreads = append(pr.ReviewThreads, thread)
+		}
+	}
+	for _, review := range reviews {
+		if review.State == "CHANGES_REQUESTED" || review.State == "COMMENTED" {
+			pr.Reviews = append(pr.Reviews, review)
+		}
+	}
+
+	return pr, nil
+}
+
+// fetchGitHubIssue fetches issue details from the GitHub API, authenticated with the configured
+// GitHub token if there is one so that issues of private repositories can be fetched too
+func fetchGitHubIssue(owner, repo, issueNumber string) (*GitHubIssue, error) {
+	number, err := strconv.Atoi(issueNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue number %q", issueNumber)
 	}
 
-	var issueResponse struct {
-		URL       string `json:"html_url"`
-		Title     string `json:"title"`
-		Body      string `json:"body"`
-		State     string `json:"state"`
-		Number    int    `json:"number"`
-		User      struct {
-			Login string `json:"login"`
-		} `json:"user"`
-		CreatedAt string `json:"created_at"`
+	token, err := config.LoadGitHubToken()
+	if err != nil {
+		utils.DebugPrintf("Fetching issue without a GitHub token: %s\n", err)
 	}
+	utils.DebugPrintf("Fetching GitHub issue %s/%s#%d\n", owner, repo, number)
 
-	err = json.NewDecoder(resp.Body).Decode(&issueResponse)
+	issueResponse, err := github.NewClient(token).GetIssue(owner, repo, number)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode GitHub response: %w", err)
+		return nil, fmt.Errorf("failed to fetch issue %s/%s#%d: %w", owner, repo, number, err)
 	}
 
 	issue := &GitHubIssue{
-		URL:       issueResponse.URL,
+		URL:       issueResponse.HTMLURL,
 		Owner:     owner,
 		Repo:      repo,
 		Number:    issueResponse.Number,
@@ -682,80 +1077,15 @@ func fetchGitHubIssue(owner, repo, issueNumber string) (*GitHubIssue, error) {
 func verifyClaudeDaemonReady(sandboxInfo *sandbox.SandboxInfo) error {
 	utils.DebugPrintf("Verifying Claude daemon readiness in sandbox %s\n", sandboxInfo.Name)
 
-	// Wait a moment for daemon to fully start
-	time.Sleep(2 * time.Second)
-
-	var daemonAddr string
-	var cleanup func()
-
-	// Check if this is a remote sandbox and use appropriate connection method
-	if sandboxInfo.Type == sandbox.TypeRemote {
-		utils.DebugPrintf("Setting up SSH port forwarding for remote sandbox\n")
-
-		// Get remote provider from metadata
-		remoteProvider, err := remote.NewProvider()
-		if err != nil {
-			return fmt.Errorf("failed to create remote provider: %w", err)
-		}
-
-		// Get daemon connection with SSH port forwarding
-		addr, cleanupFunc, err := remoteProvider.GetDaemonConnection(sandboxInfo)
-		if err != nil {
-			return fmt.Errorf("failed to setup daemon connection: %w", err)
-		}
-
-		daemonAddr = addr
-		cleanup = cleanupFunc
-		defer cleanup()
-	} else {
-		// Local sandbox - use existing logic
-		containerIP, err := getSandboxIP(sandboxInfo.Name)
-		if err != nil {
-			return fmt.Errorf("failed to get sandbox IP: %w", err)
-		}
-		daemonAddr = fmt.Sprintf("%s:28080", containerIP)
-	}
-
-	utils.DebugPrintf("Testing connection to daemon at %s\n", daemonAddr)
-
-	// Use context with timeout for connection
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	conn, err := grpc.NewClient(daemonAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// The health check waits for the daemon to accept connections
+	info, err := getSandboxDaemonInfo(sandboxInfo, 10*time.Second)
 	if err != nil {
-		return fmt.Errorf("failed to create grpc client: %w", err)
-	}
-	defer conn.Close()
-
-	// Wait for connection to be ready - keep checking until ready or timeout
-	for {
-		state := conn.GetState()
-		utils.DebugPrintf("Current connection state: %v\n", state)
-
-		if state == connectivity.Ready || state == connectivity.Idle {
-			break
-		}
-		if state == connectivity.TransientFailure || state == connectivity.Shutdown {
-			return fmt.Errorf("daemon connection failed, state: %s", state.String())
-		}
-
-		// Wait for state to change or timeout
-		if !conn.WaitForStateChange(ctx, state) {
-			return fmt.Errorf("connection timeout while waiting for daemon (state: %s)", state.String())
-		}
-	}
-
-	// Test connection state
-	state := conn.GetState()
-	utils.DebugPrintf("Connection state: %v\n", state)
-
-	if state == connectivity.Ready || state == connectivity.Idle {
-		utils.DebugPrintf("Claude daemon is ready\n")
-		return nil
+		return err
 	}
 
-	return fmt.Errorf("daemon connection state: %s", state.String())
+	fmt.Printf("   • Daemon %s is ready (Claude CLI: %s)\n", info.Version, info.ClaudeVersion)
+	utils.DebugPrintf("Claude daemon is ready\n")
+	return nil
 }
 
 // saveTaskDataToSandbox saves the task data to a file in the sandbox
@@ -793,8 +1123,55 @@ func saveTaskDataToSandbox(sandboxInfo *sandbox.SandboxInfo, taskData *TaskData)
 		return nil
 	}
 
-	// For remote sandboxes, would need SSH upload - implement later if needed
-	return fmt.Errorf("saving task data to remote sandboxes not yet implemented")
+	// Remote sandboxes have no directory shared with this machine, so the file is written by a command
+	remoteProvider, err := remote.NewProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create remote provider: %w", err)
+	}
+	workDir, err := remoteProvider.GetWorkDir(sandboxInfo)
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	taskDataPath := path.Join(workDir, ".claude_task.json")
+	if _, err := runInSandbox(remoteProvider, sandboxInfo, fmt.Sprintf("printf '%%s' %s > %s", shellQuote(string(taskDataBytes)), shellQuote(taskDataPath))); err != nil {
+		return fmt.Errorf("failed to write task data to sandbox: %w", err)
+	}
+
+	utils.DebugPrintf("Saved task data to sandbox: %s\n", taskDataPath)
+	return nil
+}
+
+// setupDaemonCredentials generates the credentials the CLI authenticates to the daemon of a new sandbox
+// with, writes the daemon's part of them to ~/.dispense in the sandbox and stores them in the local database
+func setupDaemonCredentials(provider sandbox.Provider, sandboxInfo *sandbox.SandboxInfo, withTLS bool) error {
+	creds, err := daemon.GenerateCredentials(withTLS)
+	if err != nil {
+		return err
+	}
+
+	files := creds.SandboxFiles()
+	for _, file := range files {
+		// The token and private keys are written as files, never as part of a command the sandbox logs
+		utils.DebugPrintf("Writing daemon credentials file ~/.dispense/%s\n", file.Path)
+		if err := provider.WriteHomeFile(sandboxInfo, ".dispense/"+file.Path, file.Content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+
+	db, err := database.NewSandboxDB()
+	if err == nil {
+		err = db.SaveDaemonCredentials(sandboxInfo.ID, sandboxInfo.Name, creds)
+	}
+	if err != nil {
+		// Without the credentials the CLI could not connect, so the daemon must not require them
+		for _, file := range files {
+			provider.ExecuteCommand(sandboxInfo, fmt.Sprintf("sh -c 'rm -f \"$HOME/.dispense/%s\"'", file.Path))
+		}
+		return fmt.Errorf("failed to save daemon credentials: %w", err)
+	}
+
+	return nil
 }
 
 // startClaudeCommandInBackground starts a Claude command in the background without waiting for completion
@@ -832,7 +1209,12 @@ func startClaudeCommandInBackground(sandboxInfo *sandbox.SandboxInfo, prompt str
 
 	utils.DebugPrintf("Connecting to daemon at %s\n", daemonAddr)
 
-	conn, err := grpc.NewClient(daemonAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	dialOpts, err := daemonDialOptions(sandboxInfo)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to connect to daemon: %w", err)
 	}
@@ -845,13 +1227,23 @@ func startClaudeCommandInBackground(sandboxInfo *sandbox.SandboxInfo, prompt str
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	// Execute Claude command
-	_, err = client.ExecuteClaude(context.Background(), &pb.ExecuteClaudeRequest{
+	req := &pb.ExecuteClaudeRequest{
 		Prompt:           prompt,
 		WorkingDirectory: workDir,
 		EnvironmentVars:  make(map[string]string),
-		AnthropicApiKey:  apiKey,
-	})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	info, err := client.DaemonInfo(ctx, &pb.DaemonInfoRequest{})
+	cancel()
+	if err != nil {
+		utils.DebugPrintf("Could not get daemon info: %v\n", err)
+	}
+	if err := setTaskCredentials(req, info, func() (string, error) { return apiKey, nil }); err != nil {
+		return err
+	}
+
+	// Execute Claude command
+	_, err = client.ExecuteClaude(context.Background(), req)
 	if err != nil {
 		return fmt.Errorf("failed to start Claude command: %w", err)
 	}
@@ -861,6 +1253,77 @@ func startClaudeCommandInBackground(sandboxInfo *sandbox.SandboxInfo, prompt str
 	return nil
 }
 
+// initializeProject asks the daemon to run the project's setup commands, detect
+// its toolchains, install its dependencies and build it, printing the progress
+// as it happens
+func initializeProject(sandboxInfo *sandbox.SandboxInfo, opts *sandbox.CreateOptions) error {
+	fmt.Println("📦 Initializing project...")
+
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxInfo.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get daemon connection: %w", err)
+	}
+	defer cleanup()
+
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	workDir, err := getWorkDirFromProvider(sandboxInfo.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	client := pb.NewProjectServiceClient(conn)
+	stream, err := client.Init(context.Background(), &pb.InitRequest{
+		WorkingDirectory: workDir,
+		SetupCommands:    opts.SetupCommands,
+		TestCommands:     opts.TestCommands,
+		LintCommands:     opts.LintCommands,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start project initialization: %w", err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("project initialization failed: %w", err)
+		}
+
+		switch resp.Type {
+		case pb.ProjectInitResponse_STATUS:
+			fmt.Printf("   %s\n", resp.Content)
+		case pb.ProjectInitResponse_OUTPUT:
+			fmt.Printf("     %s\n", resp.Content)
+		case pb.ProjectInitResponse_ERROR:
+			fmt.Fprintf(os.Stderr, "   ❌ %s\n", resp.Content)
+		case pb.ProjectInitResponse_DONE:
+			if !resp.Success {
+				fmt.Printf("⚠️  %s\n", resp.Content)
+				continue
+			}
+			fmt.Printf("✅ %s\n", resp.Content)
+			if info := resp.Project; info != nil {
+				if len(info.BuildCommands) > 0 {
+					fmt.Printf("   • Build: %s\n", strings.Join(info.BuildCommands, "; "))
+				}
+				if len(info.TestCommands) > 0 {
+					fmt.Printf("   • Test: %s\n", strings.Join(info.TestCommands, "; "))
+				}
+				if len(info.LintCommands) > 0 {
+					fmt.Printf("   • Lint: %s\n", strings.Join(info.LintCommands, "; "))
+				}
+			}
+		}
+	}
+}
+
 func init() {
 	// Add flags for the new command
 	newCmd.Flags().BoolP("remote", "r", false, "Create remote sandbox using Daytona API (default: local Docker)")
@@ -874,72 +1337,23 @@ func init() {
 	newCmd.Flags().Int32P("auto-stop", "a", 60, "Auto-stop interval in minutes (0 = disabled, remote only)")
 	newCmd.Flags().Bool("skip-copy", false, "Skip copying files to sandbox")
 	newCmd.Flags().Bool("skip-daemon", false, "Skip installing daemon to sandbox")
+	newCmd.Flags().Bool("skip-init", false, "Skip the project setup commands, toolchain detection and dependency install")
 	newCmd.Flags().StringP("group", "g", "", "Optional group parameter for organizing sandboxes")
 	newCmd.Flags().StringP("model", "m", "", "Optional model parameter for the sandbox")
 	newCmd.Flags().String("task", "", "Task description (skips task prompt)")
+	newCmd.Flags().Duration("task-timeout", 0, "Default time limit for Claude tasks in the sandbox, e.g. 2h (0 = no limit)")
+	newCmd.Flags().Duration("idle-timeout", 0, "Default time a Claude task in the sandbox may go without output, e.g. 15m (0 = no limit)")
+	newCmd.Flags().Bool("tls", false, "Connect to the sandbox's daemon over mutual TLS with a certificate authority generated for the sandbox")
+	newCmd.Flags().Bool("persist-credentials", false, "Let the sandbox's daemon keep the Anthropic API key in a file only it can read, so it survives daemon restarts (default: memory only)")
+	newCmd.Flags().Bool("auto-pr", false, "Open a GitHub pull request, or push to the pull request's branch, once Claude has finished working on the GitHub issue or pull request")
 }
 
 
 // isDaemonReady checks if the Claude daemon is ready for connections
 func isDaemonReady(sandboxInfo *sandbox.SandboxInfo) bool {
-	var daemonAddr string
-	var cleanup func()
-
-	// Check if this is a remote sandbox and use appropriate connection method
-	if sandboxInfo.Type == sandbox.TypeRemote {
-		// Get remote provider from metadata
-		remoteProvider, err := remote.NewProvider()
-		if err != nil {
-			return false
-		}
-
-		// Get daemon connection with SSH port forwarding
-		addr, cleanupFunc, err := remoteProvider.GetDaemonConnection(sandboxInfo)
-		if err != nil {
-			return false
-		}
-
-		daemonAddr = addr
-		cleanup = cleanupFunc
-		defer cleanup()
-	} else {
-		// Local sandbox - use existing logic
-		ip, err := getSandboxIP(sandboxInfo.Name)
-		if err != nil {
-			return false
-		}
-		daemonAddr = fmt.Sprintf("%s:28080", ip)
-	}
-
-	// Use context with timeout for connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Try to connect to the daemon
-	conn, err := grpc.NewClient(daemonAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
+	if err := checkSandboxDaemonHealth(sandboxInfo, 5*time.Second); err != nil {
+		utils.DebugPrintf("Daemon health check failed: %v\n", err)
 		return false
 	}
-	defer conn.Close()
-
-	// Wait for connection to be ready - keep checking until ready or timeout
-	for {
-		state := conn.GetState()
-
-		if state == connectivity.Ready || state == connectivity.Idle {
-			break
-		}
-		if state == connectivity.TransientFailure || state == connectivity.Shutdown {
-			return false
-		}
-
-		// Wait for state to change or timeout
-		if !conn.WaitForStateChange(ctx, state) {
-			return false
-		}
-	}
-
-	// Check connection state
-	state := conn.GetState()
-	return state == connectivity.Ready || state == connectivity.Idle
+	return true
 }
\ No newline at end of file
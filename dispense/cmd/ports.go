@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"cli/pkg/project"
+	"cli/pkg/sandbox"
+	"cli/pkg/sandbox/remote"
+
+	"github.com/spf13/cobra"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports <sandbox-name> [ports...]",
+	Short: "Show how to reach the ports of a remote sandbox",
+	Long: `Show the Daytona preview URL of ports of a remote sandbox, together with the
+token to send in the x-daytona-preview-token header when the sandbox is private.
+Without ports, the ports listed in the .dispense.yaml of the current directory
+are shown.
+
+Local sandboxes publish their ports on 127.0.0.1 instead, shown by
+docker port <container>.
+
+Examples:
+  dispense ports my-project              # Ports from .dispense.yaml
+  dispense ports my-project 3000 8080    # Specific ports`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sandboxInfo, err := findSandboxByName(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+			os.Exit(1)
+		}
+		if sandboxInfo.Type != sandbox.TypeRemote {
+			fmt.Fprintf(os.Stderr, "❌ Sandbox '%s' is local; its ports are shown by docker port\n", sandboxInfo.Name)
+			os.Exit(1)
+		}
+
+		ports, err := portsToShow(args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+			os.Exit(1)
+		}
+
+		remoteProvider, err := remote.NewProvider()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to create remote provider: %s\n", err)
+			os.Exit(1)
+		}
+
+		for _, port := range ports {
+			url, token, err := remoteProvider.GetPortPreview(sandboxInfo, port)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Port %d: %s\n", port, url)
+			if token != "" {
+				fmt.Printf("  x-daytona-preview-token: %s\n", token)
+			}
+		}
+	},
+}
+
+// portsToShow parses the ports given as arguments, falling back to the ports
+// of the .dispense.yaml in the current directory
+func portsToShow(args []string) ([]int, error) {
+	if len(args) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		config, err := project.LoadConfig(cwd)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil || len(config.Ports) == 0 {
+			return nil, fmt.Errorf("no ports given and no ports listed in .dispense.yaml")
+		}
+		return config.Ports, nil
+	}
+
+	var ports []int
+	for _, arg := range args {
+		port, err := strconv.Atoi(arg)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", arg)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
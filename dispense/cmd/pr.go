@@ -0,0 +1,344 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"cli/pkg/config"
+	"cli/pkg/database"
+	"cli/pkg/github"
+	"cli/pkg/project"
+	"cli/pkg/sandbox"
+	"cli/pkg/utils"
+	pb "cli/proto"
+
+	"github.com/spf13/cobra"
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr <sandbox-name>",
+	Short: "Open a GitHub pull request with the work done in a sandbox",
+	Long: `Push the branch of a sandbox to GitHub and open a pull request for it.
+
+Uncommitted changes in the sandbox's workspace, including untracked files, are
+committed first. The branch is pushed to the GitHub repository the sandbox
+was created for, or else to its origin remote, with the token from the
+GITHUB_TOKEN or GH_TOKEN environment variable, ~/.dispense/github_token or
+gh auth token. Git in the sandbox gets the token from a credential helper that
+expires, and it is never stored in the workspace's git config.
+
+The pull request is described with Claude's summary of its last task, and
+closes the GitHub issue the sandbox was created for. If the branch already
+has an open pull request, it is updated by the push and reported instead.
+
+Sandboxes created for a GitHub pull request push to that pull request's
+branch instead of opening a new one, so --base, --title and --draft do not
+apply to them.
+
+Examples:
+  dispense pr my-project                      # Open a pull request against the default branch
+  dispense pr my-project --base develop       # Open it against develop
+  dispense pr my-project --draft              # Open a draft pull request
+  dispense pr my-project -t "Fix login"       # Set the title`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := &prOptions{}
+		opts.Base, _ = cmd.Flags().GetString("base")
+		opts.Title, _ = cmd.Flags().GetString("title")
+		opts.Message, _ = cmd.Flags().GetString("message")
+		opts.Draft, _ = cmd.Flags().GetBool("draft")
+
+		if strings.HasPrefix(opts.Base, "-") {
+			fmt.Fprintf(os.Stderr, "❌ Invalid --base %q\n", opts.Base)
+			os.Exit(1)
+		}
+
+		sandboxInfo, provider, err := findSandbox(args[0], false, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to find sandbox: %s\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("📤 Opening a pull request for sandbox %s...\n", sandboxInfo.Name)
+
+		result, err := openSandboxPullRequest(sandboxInfo, provider, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to open pull request: %s\n", err)
+			os.Exit(1)
+		}
+		printPullRequestResult(result)
+	},
+}
+
+func init() {
+	prCmd.Flags().String("base", "", "Branch the pull request is merged into (default: the repository's default branch)")
+	prCmd.Flags().StringP("title", "t", "", "Title of the pull request (default: the title of the GitHub issue)")
+	prCmd.Flags().StringP("message", "m", "", "Commit message for uncommitted changes in the sandbox")
+	prCmd.Flags().Bool("draft", false, "Open the pull request as a draft")
+}
+
+// prOptions configures the pull request opened for a sandbox
+type prOptions struct {
+	Base    string
+	Title   string
+	Message string
+	Draft   bool
+}
+
+// prResult describes the pull request of a sandbox's branch
+type prResult struct {
+	PullRequest *github.PullRequest
+	Owner       string
+	Repo        string
+	Branch      string
+	Committed   bool // uncommitted changes were committed in the sandbox first
+	Existing    bool // the branch already had an open pull request
+}
+
+// openSandboxPullRequest commits the uncommitted changes in the workspace of a sandbox, pushes its
+// branch to GitHub and opens a pull request for it, unless the branch already has an open one. The
+// branch of a sandbox created for a pull request is pushed to that pull request.
+func openSandboxPullRequest(sandboxInfo *sandbox.SandboxInfo, provider sandbox.Provider, opts *prOptions) (*prResult, error) {
+	token, err := config.LoadGitHubToken()
+	if err != nil {
+		return nil, err
+	}
+
+	shell, onHost, err := workspaceShell(sandboxInfo, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// Git on this machine is given the token for the push only. Git in a sandbox gets it from its
+	// credential helper, which is renewed first, so the token is not part of any command sent to it.
+	gitAuth := ""
+	if onHost {
+		gitAuth = "-c http.extraheader=" + shellQuote(github.GitAuthHeader(token))
+	} else if err := provider.SetGitHubCredentials(sandboxInfo, token); err != nil {
+		return nil, fmt.Errorf("failed to give the sandbox GitHub credentials: %w", err)
+	}
+
+	message := opts.Message
+	if message == "" {
+		message = fmt.Sprintf("Work from dispense sandbox %s", sandboxInfo.Name)
+	}
+	output, err := shell(commitAllScript(message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit changes in the sandbox: %w", err)
+	}
+	result := &prResult{Committed: strings.Contains(output, "dispense-committed")}
+
+	output, err = shell("git symbolic-ref --short -q HEAD || true")
+	if err != nil {
+		return nil, err
+	}
+	result.Branch = lastLine(output)
+	if result.Branch == "" {
+		result.Branch = sandboxInfo.Name
+	}
+
+	taskData := loadSandboxTaskData(sandboxInfo, provider)
+
+	// A sandbox created for a pull request pushes to the pull request's branch, which updates it
+	if taskData != nil && taskData.GitHubPR != nil {
+		pr := taskData.GitHubPR
+		if pr.HeadOwner == "" {
+			return nil, fmt.Errorf("the repository of the branch of pull request #%d no longer exists", pr.Number)
+		}
+		result.Owner, result.Repo, result.Branch = pr.HeadOwner, pr.HeadRepo, pr.HeadBranch
+		if err := pushWorkspaceBranch(shell, gitAuth, result.Owner, result.Repo, result.Branch); err != nil {
+			return nil, err
+		}
+
+		result.PullRequest, err = github.NewClient(token).GetPullRequest(pr.Owner, pr.Repo, pr.Number)
+		if err != nil {
+			return nil, err
+		}
+		result.Existing = true
+		return result, nil
+	}
+
+	// The sandbox's task names the repository, sandboxes created from a checkout use its origin
+	if taskData != nil && taskData.GitHubIssue != nil {
+		result.Owner, result.Repo = taskData.GitHubIssue.Owner, taskData.GitHubIssue.Repo
+	} else {
+		remoteURL, err := shell("git remote get-url origin")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the origin remote of the sandbox: %w", err)
+		}
+		var ok bool
+		if result.Owner, result.Repo, ok = github.ParseRemoteURL(lastLine(remoteURL)); !ok {
+			return nil, fmt.Errorf("the origin remote of the sandbox, %s, is not a GitHub repository", lastLine(remoteURL))
+		}
+	}
+
+	if err := pushWorkspaceBranch(shell, gitAuth, result.Owner, result.Repo, result.Branch); err != nil {
+		return nil, err
+	}
+
+	client := github.NewClient(token)
+
+	existing, err := client.FindOpenPullRequest(result.Owner, result.Repo, result.Owner, result.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		result.PullRequest = existing
+		result.Existing = true
+		return result, nil
+	}
+
+	base := opts.Base
+	if base == "" {
+		repository, err := client.GetRepository(result.Owner, result.Repo)
+		if err != nil {
+			return nil, err
+		}
+		base = repository.DefaultBranch
+	}
+
+	title := opts.Title
+	issueNumber := 0
+	if taskData != nil && taskData.GitHubIssue != nil {
+		issueNumber = taskData.GitHubIssue.Number
+		if title == "" {
+			title = taskData.GitHubIssue.Title
+		}
+	}
+	if title == "" {
+		title = message
+	}
+
+	latestTask, err := getSandboxTaskStatus(sandboxInfo)
+	if err != nil {
+		utils.DebugPrintf("Warning: Failed to get task summary: %s\n", err)
+	}
+
+	result.PullRequest, err = client.CreatePullRequest(result.Owner, result.Repo, &github.NewPullRequest{
+		Title: title,
+		Head:  result.Branch,
+		Base:  base,
+		Body:  sandboxPullRequestBody(issueNumber, latestTask),
+		Draft: opts.Draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// pushWorkspaceBranch pushes the HEAD of a sandbox's workspace to branch of a GitHub repository. gitAuth
+// holds the git options that authenticate this push only, so the token is never stored in the
+// workspace's git config.
+func pushWorkspaceBranch(shell func(script string) (string, error), gitAuth, owner, repo, branch string) error {
+	repoURL := fmt.Sprintf("https://github.com/%s/%s.git", owner, repo)
+	utils.DebugPrintf("Pushing branch %s to %s\n", branch, repoURL)
+	if _, err := shell(fmt.Sprintf("git %s push -q %s %s",
+		gitAuth, shellQuote(repoURL), shellQuote("HEAD:refs/heads/"+branch))); err != nil {
+		return fmt.Errorf("failed to push branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// sandboxPullRequestBody returns the description of a sandbox's pull request, which is summarized by
+// Claude's final message of the sandbox's latest task. latestTask may be nil if it is not known.
+func sandboxPullRequestBody(issueNumber int, latestTask *pb.TaskStatusResponse) string {
+	return github.PullRequestBody(issueNumber, latestTask.GetResult())
+}
+
+// printPullRequestResult prints the pull request opened for a sandbox
+func printPullRequestResult(result *prResult) {
+	if result.Committed {
+		fmt.Printf("💾 Committed uncommitted changes in the sandbox\n")
+	}
+	fmt.Printf("✅ Pushed branch %s to %s/%s\n", result.Branch, result.Owner, result.Repo)
+	if result.Existing {
+		fmt.Printf("🔗 Updated pull request #%d: %s\n", result.PullRequest.Number, result.PullRequest.HTMLURL)
+	} else {
+		fmt.Printf("🔗 Opened pull request #%d: %s\n", result.PullRequest.Number, result.PullRequest.HTMLURL)
+	}
+}
+
+// workspaceShell returns a function that runs a shell script in the git repository of a sandbox's
+// workspace and returns its output, and whether it runs on this machine. The worktree of a local
+// sandbox is used on this machine, as its .git file points to the repository here.
+func workspaceShell(sandboxInfo *sandbox.SandboxInfo, provider sandbox.Provider) (func(script string) (string, error), bool, error) {
+	if sandboxInfo.Type == sandbox.TypeLocal {
+		if projectPath, ok := sandboxInfo.Metadata["project_path"].(string); ok && project.IsGitWorktree(projectPath) {
+			return func(script string) (string, error) {
+				cmd := exec.Command("sh", "-c", script)
+				cmd.Dir = projectPath
+				output, err := cmd.CombinedOutput()
+				if err != nil {
+					return "", fmt.Errorf("command failed: %w: %s", err, strings.TrimSpace(string(output)))
+				}
+				return string(output), nil
+			}, true, nil
+		}
+	}
+
+	workDir, err := provider.GetWorkDir(sandboxInfo)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return func(script string) (string, error) {
+		return runInSandbox(provider, sandboxInfo, fmt.Sprintf("cd %s && %s", shellQuote(workDir), script))
+	}, false, nil
+}
+
+// loadSandboxTaskData returns the task a sandbox was created for from the local database or the
+// task file in its workspace, or nil if it was not created for a task
+func loadSandboxTaskData(sandboxInfo *sandbox.SandboxInfo, provider sandbox.Provider) *TaskData {
+	var data string
+	if sandboxInfo.Type == sandbox.TypeLocal {
+		if db, err := database.NewSandboxDB(); err == nil {
+			if localSandbox, err := db.GetByName(sandboxInfo.Name); err == nil {
+				data = localSandbox.TaskData
+			}
+		}
+	}
+	if data == "" {
+		if workDir, err := provider.GetWorkDir(sandboxInfo); err == nil {
+			data, _ = runInSandbox(provider, sandboxInfo, "cat "+shellQuote(workDir+"/.claude_task.json")+" 2>/dev/null || true")
+		}
+	}
+	if strings.TrimSpace(data) == "" {
+		return nil
+	}
+
+	var taskData TaskData
+	if err := json.Unmarshal([]byte(data), &taskData); err != nil {
+		utils.DebugPrintf("Warning: Failed to parse task data of sandbox %s: %s\n", sandboxInfo.Name, err)
+		return nil
+	}
+	return &taskData
+}
+
+// waitForSandboxTask waits until the most recent task of a sandbox is no longer pending or running and
+// returns its status. It gives up when the daemon cannot be reached for several minutes.
+func waitForSandboxTask(sandboxInfo *sandbox.SandboxInfo) (*pb.TaskStatusResponse, error) {
+	const pollInterval = 10 * time.Second
+	const maxFailures = 30
+
+	failures := 0
+	for {
+		status, err := getSandboxTaskStatus(sandboxInfo)
+		if err != nil {
+			failures++
+			if failures >= maxFailures {
+				return nil, err
+			}
+			utils.DebugPrintf("Failed to get task status of %s: %s\n", sandboxInfo.Name, err)
+		} else {
+			failures = 0
+			if status.State != pb.TaskStatusResponse_RUNNING && status.State != pb.TaskStatusResponse_PENDING {
+				return status, nil
+			}
+		}
+		time.Sleep(pollInterval)
+	}
+}
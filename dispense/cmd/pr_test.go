@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	pb "cli/proto"
+)
+
+func TestSandboxPullRequestBody(t *testing.T) {
+	latestTask := &pb.TaskStatusResponse{
+		State:  pb.TaskStatusResponse_COMPLETED,
+		Result: "Fixed the login timeout by retrying the token refresh.",
+	}
+
+	body := sandboxPullRequestBody(42, latestTask)
+	if !strings.HasPrefix(body, "Fixes #42\n") {
+		t.Errorf("body %q does not close the issue", body)
+	}
+	if !strings.Contains(body, "## Summary\n\nFixed the login timeout by retrying the token refresh.") {
+		t.Errorf("body %q does not contain the latest task's result", body)
+	}
+
+	if body := sandboxPullRequestBody(0, nil); strings.Contains(body, "Summary") {
+		t.Errorf("body without a task = %q, want no summary", body)
+	}
+}
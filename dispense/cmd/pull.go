@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"cli/pkg/project"
+	"cli/pkg/sandbox"
+	"cli/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull <sandbox-name>",
+	Short: "Fetch the work done in a sandbox into a branch of this repository",
+	Long: `Fetch the work done in a sandbox into a branch of the git repository in the
+current directory.
+
+Uncommitted changes in the sandbox's workspace, including untracked files, are
+committed first. The sandbox's commits are then bundled, downloaded and fetched
+into the branch, which is named after the sandbox unless --branch is given.
+The branch that is checked out is never changed, and an existing branch is
+only moved forward unless --force is given.
+
+Local sandboxes backed by a git worktree already share their branch with this
+repository, so only their uncommitted changes are committed.
+
+Examples:
+  dispense pull my-project                        # Fetch into branch my-project
+  dispense pull my-project --branch fix-login     # Fetch into branch fix-login
+  dispense pull my-project -m "Fix login timeout" # Commit message for uncommitted work
+  dispense pull my-project --force                # Overwrite the branch if it diverged`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		branch, _ := cmd.Flags().GetString("branch")
+		message, _ := cmd.Flags().GetString("message")
+		force, _ := cmd.Flags().GetBool("force")
+
+		sandboxInfo, provider, err := findSandbox(args[0], false, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to find sandbox: %s\n", err)
+			os.Exit(1)
+		}
+
+		workingDir, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to get current directory: %s\n", err)
+			os.Exit(1)
+		}
+		repoDir, err := hostGit(workingDir, "rev-parse", "--show-toplevel")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Run dispense pull inside the git repository to fetch the sandbox's work into\n")
+			os.Exit(1)
+		}
+
+		if branch == "" {
+			branch = sandboxInfo.Name
+		}
+		if message == "" {
+			message = fmt.Sprintf("Work from dispense sandbox %s", sandboxInfo.Name)
+		}
+
+		fmt.Printf("📥 Pulling sandbox %s into branch %s...\n", sandboxInfo.Name, branch)
+
+		result, err := pullSandboxBranch(sandboxInfo, provider, repoDir, branch, message, force)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to pull sandbox: %s\n", err)
+			os.Exit(1)
+		}
+
+		if result.Committed {
+			fmt.Printf("💾 Committed uncommitted changes in the sandbox\n")
+		}
+		fmt.Printf("✅ Branch %s is at %s\n", result.Branch, shortCommit(result.Commit))
+		fmt.Printf("   Review with: git log %s\n", result.Branch)
+	},
+}
+
+func init() {
+	pullCmd.Flags().StringP("branch", "b", "", "Branch to fetch the sandbox's work into (default: the sandbox name)")
+	pullCmd.Flags().StringP("message", "m", "", "Commit message for uncommitted changes in the sandbox")
+	pullCmd.Flags().BoolP("force", "f", false, "Overwrite the branch even if it has commits the sandbox does not have")
+}
+
+// pullResult describes a sandbox's work fetched into a branch of the host repository
+type pullResult struct {
+	Branch    string
+	Commit    string
+	Committed bool // uncommitted changes were committed in the sandbox first
+}
+
+// pullSandboxBranch commits the uncommitted changes in the workspace of a sandbox and fetches its
+// HEAD into branch of the repository at repoDir. The branch checked out in repoDir is never updated.
+func pullSandboxBranch(sandboxInfo *sandbox.SandboxInfo, provider sandbox.Provider, repoDir, branch, message string, force bool) (*pullResult, error) {
+	if _, err := hostGit(repoDir, "check-ref-format", "--branch", branch); err != nil || strings.HasPrefix(branch, "-") {
+		return nil, fmt.Errorf("invalid branch name %q", branch)
+	}
+	if current, _ := hostGit(repoDir, "symbolic-ref", "--short", "-q", "HEAD"); current == branch {
+		return nil, fmt.Errorf("branch %s is checked out, use --branch to pull into another branch", branch)
+	}
+
+	refspec := "refs/heads/" + branch
+	if force {
+		refspec = "+" + refspec
+	}
+
+	// A local worktree shares its repository with the host, so its commits can be fetched directly
+	if sandboxInfo.Type == sandbox.TypeLocal {
+		if projectPath, ok := sandboxInfo.Metadata["project_path"].(string); ok && project.IsGitWorktree(projectPath) {
+			return pullWorktreeBranch(projectPath, repoDir, branch, refspec, message)
+		}
+	}
+
+	workDir, err := provider.GetWorkDir(sandboxInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	output, err := runInSandbox(provider, sandboxInfo, fmt.Sprintf("cd %s && %s", shellQuote(workDir), commitAllScript(message)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit changes in the sandbox: %w", err)
+	}
+	result := &pullResult{Branch: branch, Committed: strings.Contains(output, "dispense-committed")}
+
+	// Bundle the sandbox's HEAD and download it
+	remoteBundlePath := fmt.Sprintf("/tmp/dispense-pull-%s.bundle", sandboxInfo.ID)
+	output, err = runInSandbox(provider, sandboxInfo, fmt.Sprintf("cd %s && git bundle create -q %s HEAD && git rev-parse HEAD", shellQuote(workDir), shellQuote(remoteBundlePath)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git bundle in the sandbox: %w", err)
+	}
+	result.Commit = lastLine(output)
+	defer func() {
+		if _, err := runInSandbox(provider, sandboxInfo, "rm -f "+shellQuote(remoteBundlePath)); err != nil {
+			utils.DebugPrintf("Warning: Failed to remove bundle from sandbox: %s\n", err)
+		}
+	}()
+
+	bundleFile, err := os.CreateTemp("", "dispense-pull-*.bundle")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	bundleFile.Close()
+	defer os.Remove(bundleFile.Name())
+
+	utils.DebugPrintf("Downloading bundle %s to %s\n", remoteBundlePath, bundleFile.Name())
+	if err := provider.DownloadFile(sandboxInfo, remoteBundlePath, bundleFile.Name()); err != nil {
+		return nil, err
+	}
+
+	if _, err := hostGit(repoDir, "fetch", "--no-tags", bundleFile.Name(), "HEAD:"+refspec); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// pullWorktreeBranch commits the uncommitted changes in the worktree of a local sandbox and fetches its
+// HEAD into branch, unless it is the worktree's own branch, which the host repository already has
+func pullWorktreeBranch(projectPath, repoDir, branch, refspec, message string) (*pullResult, error) {
+	cmd := exec.Command("sh", "-c", commitAllScript(message))
+	cmd.Dir = projectPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit changes in the worktree: %w\nOutput: %s", err, string(output))
+	}
+
+	commit, err := hostGit(projectPath, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	result := &pullResult{
+		Branch:    branch,
+		Commit:    commit,
+		Committed: strings.Contains(string(output), "dispense-committed"),
+	}
+
+	worktreeBranch, _ := hostGit(projectPath, "symbolic-ref", "--short", "-q", "HEAD")
+	if branch == worktreeBranch && sameGitRepository(projectPath, repoDir) {
+		return result, nil
+	}
+
+	if _, err := hostGit(repoDir, "fetch", "--no-tags", projectPath, commit+":"+refspec); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// commitAllScript returns a shell script that commits every change in the current git repository,
+// printing dispense-committed if there was anything to commit. Sandboxes without a git identity
+// commit as dispense. The task file dispense leaves in the workspace is not committed.
+func commitAllScript(message string) string {
+	return "git config user.email >/dev/null || export GIT_AUTHOR_NAME=dispense GIT_AUTHOR_EMAIL=dispense@localhost GIT_COMMITTER_NAME=dispense GIT_COMMITTER_EMAIL=dispense@localhost; " +
+		"git add -A -- . ':!.claude_task.json' && if git diff --cached --quiet; then echo dispense-clean; else git commit -q -m " + shellQuote(message) + " && echo dispense-committed; fi"
+}
+
+// runInSandbox runs a shell command in a sandbox and returns its output, failing if it exits with an error
+func runInSandbox(provider sandbox.Provider, sandboxInfo *sandbox.SandboxInfo, command string) (string, error) {
+	result, err := provider.ExecuteCommand(sandboxInfo, "sh -c "+shellQuote(command))
+	if err != nil {
+		return "", err
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("command exited with code %d: %s", result.ExitCode, strings.TrimSpace(result.Stdout+result.Stderr))
+	}
+	return result.Stdout, nil
+}
+
+// hostGit runs git in dir on this machine and returns its trimmed output
+func hostGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w\nOutput: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// sameGitRepository reports whether two working trees belong to the same git repository
+func sameGitRepository(dirA, dirB string) bool {
+	commonDir := func(dir string) string {
+		path, err := hostGit(dir, "rev-parse", "--git-common-dir")
+		if err != nil {
+			return ""
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return resolved
+		}
+		return filepath.Clean(path)
+	}
+
+	a := commonDir(dirA)
+	return a != "" && a == commonDir(dirB)
+}
+
+// lastLine returns the last non-empty line of a command's output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// shellQuote quotes a string for use as a single word in a POSIX shell command
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"cli/pkg/sandbox"
+	"cli/pkg/utils"
+	pb "cli/proto"
+
+	"github.com/spf13/cobra"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show Claude token usage and cost across sandboxes",
+	Long: `Show the tokens and cost Claude reported for tasks, totalled per sandbox and group.
+
+Usage is read from the daemon of each sandbox, so sandboxes that are stopped
+are skipped.
+
+Examples:
+  dispense usage                      # Usage across all sandboxes
+  dispense usage --group backend      # Usage of sandboxes in the backend group
+  dispense usage --since 7d           # Usage of tasks started in the last 7 days
+  dispense usage --since 2025-01-01   # Usage of tasks started since a date`,
+	Run: func(cmd *cobra.Command, args []string) {
+		group, _ := cmd.Flags().GetString("group")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+
+		var since time.Time
+		if sinceFlag != "" {
+			var err error
+			since, err = parseSince(sinceFlag, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := showUsage(group, since); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	usageCmd.Flags().StringP("group", "g", "", "Only include sandboxes in this group")
+	usageCmd.Flags().String("since", "", "Only include tasks started since a duration ago (e.g. 24h, 7d) or a date (YYYY-MM-DD)")
+}
+
+// usageTotals accumulates the usage of a set of tasks
+type usageTotals struct {
+	Tasks                    int
+	InputTokens              int64
+	OutputTokens             int64
+	CacheCreationInputTokens int64
+	CacheReadInputTokens     int64
+	TotalCostUSD             float64
+}
+
+// add adds the usage of a single task
+func (u *usageTotals) add(usage *pb.ClaudeUsage) {
+	u.Tasks++
+	if usage == nil {
+		return
+	}
+	u.InputTokens += usage.InputTokens
+	u.OutputTokens += usage.OutputTokens
+	u.CacheCreationInputTokens += usage.CacheCreationInputTokens
+	u.CacheReadInputTokens += usage.CacheReadInputTokens
+	u.TotalCostUSD += usage.TotalCostUsd
+}
+
+// merge adds the totals of another set of tasks
+func (u *usageTotals) merge(other usageTotals) {
+	u.Tasks += other.Tasks
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationInputTokens += other.CacheCreationInputTokens
+	u.CacheReadInputTokens += other.CacheReadInputTokens
+	u.TotalCostUSD += other.TotalCostUSD
+}
+
+// sandboxUsage is the usage of all matching tasks in one sandbox
+type sandboxUsage struct {
+	Name   string
+	Group  string
+	Totals usageTotals
+}
+
+// showUsage totals task usage across sandboxes and prints it per sandbox and group
+func showUsage(group string, since time.Time) error {
+	fmt.Printf("🔍 Resolving sandboxes...\n")
+
+	var sandboxes []*sandbox.SandboxInfo
+	if group != "" {
+		var err error
+		sandboxes, err = resolveSandboxes(nil, []string{group})
+		if err != nil {
+			return fmt.Errorf("failed to resolve sandboxes: %w", err)
+		}
+	} else {
+		sandboxes = listAllSandboxes()
+	}
+
+	if len(sandboxes) == 0 {
+		fmt.Printf("💡 No sandboxes found\n")
+		return nil
+	}
+
+	var results []sandboxUsage
+	skipped := 0
+	for _, sb := range sandboxes {
+		totals, err := getSandboxUsage(sb, since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping sandbox '%s': %s\n", sb.Name, err)
+			skipped++
+			continue
+		}
+
+		sandboxGroup, _ := sb.Metadata["group"].(string)
+		results = append(results, sandboxUsage{Name: sb.Name, Group: sandboxGroup, Totals: totals})
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("could not read usage from any sandbox")
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Group != results[j].Group {
+			return results[i].Group < results[j].Group
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	fmt.Println()
+	if !since.IsZero() {
+		fmt.Printf("📅 Tasks started since %s\n\n", since.Format("2006-01-02 15:04:05"))
+	}
+
+	var total usageTotals
+	groupTotals := make(map[string]*usageTotals)
+	var groups []string
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SANDBOX\tGROUP\tTASKS\tINPUT\tOUTPUT\tCACHE WRITE\tCACHE READ\tCOST")
+	for _, result := range results {
+		printUsageRow(w, result.Name, displayGroup(result.Group), result.Totals)
+
+		total.merge(result.Totals)
+		if _, exists := groupTotals[result.Group]; !exists {
+			groupTotals[result.Group] = &usageTotals{}
+			groups = append(groups, result.Group)
+		}
+		groupTotals[result.Group].merge(result.Totals)
+	}
+	printUsageRow(w, "TOTAL", "", total)
+	w.Flush()
+
+	// Break the totals down by group when more than one group is shown
+	if len(groups) > 1 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "GROUP\tTASKS\tINPUT\tOUTPUT\tCACHE WRITE\tCACHE READ\tCOST")
+		for _, g := range groups {
+			totals := groupTotals[g]
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t$%.4f\n",
+				displayGroup(g),
+				totals.Tasks,
+				totals.InputTokens,
+				totals.OutputTokens,
+				totals.CacheCreationInputTokens,
+				totals.CacheReadInputTokens,
+				totals.TotalCostUSD,
+			)
+		}
+		w.Flush()
+	}
+
+	fmt.Printf("\n💰 Total cost: $%.4f across %d task(s) in %d sandbox(es)\n", total.TotalCostUSD, total.Tasks, len(results))
+	if skipped > 0 {
+		fmt.Printf("💡 %d sandbox(es) could not be reached and are not included\n", skipped)
+	}
+
+	return nil
+}
+
+// getSandboxUsage totals the usage of the tasks in a sandbox started at or after since
+func getSandboxUsage(sandboxInfo *sandbox.SandboxInfo, since time.Time) (usageTotals, error) {
+	var totals usageTotals
+
+	// The sandbox was already looked up, so connect to it directly instead of by name
+	conn, cleanup, err := dialSandboxDaemon(sandboxInfo)
+	if err != nil {
+		return totals, err
+	}
+	defer cleanup()
+
+	client := pb.NewAgentServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+	if err != nil {
+		return totals, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	for _, task := range resp.Tasks {
+		if !since.IsZero() && time.Unix(task.StartedAt, 0).Before(since) {
+			continue
+		}
+		totals.add(task.Usage)
+	}
+
+	utils.DebugPrintf("Sandbox %s: %d task(s), $%.4f\n", sandboxInfo.Name, totals.Tasks, totals.TotalCostUSD)
+	return totals, nil
+}
+
+// printUsageRow writes one row of the usage table
+func printUsageRow(w *tabwriter.Writer, name, group string, totals usageTotals) {
+	fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t$%.4f\n",
+		name,
+		group,
+		totals.Tasks,
+		totals.InputTokens,
+		totals.OutputTokens,
+		totals.CacheCreationInputTokens,
+		totals.CacheReadInputTokens,
+		totals.TotalCostUSD,
+	)
+}
+
+// displayGroup returns the group name shown for a sandbox
+func displayGroup(group string) string {
+	if group == "" {
+		return "-"
+	}
+	return group
+}
+
+// parseSince parses a --since value, which is either a duration before now
+// (e.g. 24h, 30m or 7d) or a date (YYYY-MM-DD or RFC 3339)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since value %q: use a duration like 24h or 7d, or a date like 2006-01-02", value)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "cli/proto"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"24h", now.Add(-24 * time.Hour)},
+		{"30m", now.Add(-30 * time.Minute)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"0d", now},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)},
+		{"2024-05-01T08:30:00Z", time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	// Negative durations would reach into the future
+	for _, value := range []string{"-24h", "-1d", "yesterday", ""} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q) succeeded", value)
+		}
+	}
+}
+
+func TestUsageTotals(t *testing.T) {
+	var sandbox usageTotals
+	sandbox.add(&pb.ClaudeUsage{InputTokens: 100, OutputTokens: 20, CacheReadInputTokens: 5, TotalCostUsd: 0.25})
+	// Tasks that ended before Claude reported usage still count
+	sandbox.add(nil)
+
+	var group usageTotals
+	group.add(&pb.ClaudeUsage{InputTokens: 1, OutputTokens: 2, CacheCreationInputTokens: 3, TotalCostUsd: 0.5})
+	group.merge(sandbox)
+
+	want := usageTotals{Tasks: 3, InputTokens: 101, OutputTokens: 22, CacheCreationInputTokens: 3, CacheReadInputTokens: 5, TotalCostUSD: 0.75}
+	if group != want {
+		t.Errorf("totals = %+v, want %+v", group, want)
+	}
+}
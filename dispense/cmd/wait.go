@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"cli/pkg/sandbox"
@@ -14,7 +15,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var waitCmd = &cobra.Command{
@@ -78,33 +78,7 @@ func init() {
 
 // resolveSandboxes resolves sandbox names and group names to actual sandbox instances
 func resolveSandboxes(sandboxNames []string, groupNames []string) ([]*sandbox.SandboxInfo, error) {
-	var allSandboxes []*sandbox.SandboxInfo
-	var providers []sandbox.Provider
-
-	// Create providers
-	localProvider, err := local.NewProvider()
-	if err != nil {
-		utils.DebugPrintf("Warning: Could not create local provider: %s\n", err)
-	} else {
-		providers = append(providers, localProvider)
-	}
-
-	remoteProvider, err := remote.NewProviderNonInteractive()
-	if err != nil {
-		utils.DebugPrintf("Warning: Could not create remote provider: %s\n", err)
-	} else {
-		providers = append(providers, remoteProvider)
-	}
-
-	// Collect all sandboxes from providers
-	for _, provider := range providers {
-		sandboxes, err := provider.List()
-		if err != nil {
-			utils.DebugPrintf("Warning: Error listing %s sandboxes: %s\n", provider.GetType(), err)
-			continue
-		}
-		allSandboxes = append(allSandboxes, sandboxes...)
-	}
+	allSandboxes := listAllSandboxes()
 
 	var targetSandboxes []*sandbox.SandboxInfo
 	seenIDs := make(map[string]bool)
@@ -145,12 +119,45 @@ func resolveSandboxes(sandboxNames []string, groupNames []string) ([]*sandbox.Sa
 	return targetSandboxes, nil
 }
 
+// listAllSandboxes lists the sandboxes of every available provider
+func listAllSandboxes() []*sandbox.SandboxInfo {
+	var allSandboxes []*sandbox.SandboxInfo
+	var providers []sandbox.Provider
+
+	// Create providers
+	localProvider, err := local.NewProvider()
+	if err != nil {
+		utils.DebugPrintf("Warning: Could not create local provider: %s\n", err)
+	} else {
+		providers = append(providers, localProvider)
+	}
+
+	remoteProvider, err := remote.NewProviderNonInteractive()
+	if err != nil {
+		utils.DebugPrintf("Warning: Could not create remote provider: %s\n", err)
+	} else {
+		providers = append(providers, remoteProvider)
+	}
+
+	// Collect all sandboxes from providers
+	for _, provider := range providers {
+		sandboxes, err := provider.List()
+		if err != nil {
+			utils.DebugPrintf("Warning: Error listing %s sandboxes: %s\n", provider.GetType(), err)
+			continue
+		}
+		allSandboxes = append(allSandboxes, sandboxes...)
+	}
+
+	return allSandboxes
+}
+
 // waitForSandboxes waits for all sandboxes to complete their tasks
 func waitForSandboxes(sandboxes []*sandbox.SandboxInfo) error {
 	fmt.Printf("⏳ Monitoring sandbox tasks...\n")
 
 	// Keep track of sandbox statuses for display
-	statuses := make([]pb.TaskStatusResponse_TaskState, len(sandboxes))
+	statuses := make([]*pb.TaskStatusResponse, len(sandboxes))
 
 	for {
 		allCompleted := true
@@ -166,7 +173,7 @@ func waitForSandboxes(sandboxes []*sandbox.SandboxInfo) error {
 			statuses[i] = status
 
 			// Check if still working
-			if status == pb.TaskStatusResponse_RUNNING || status == pb.TaskStatusResponse_PENDING {
+			if status.State == pb.TaskStatusResponse_RUNNING || status.State == pb.TaskStatusResponse_PENDING {
 				allCompleted = false
 			}
 		}
@@ -176,8 +183,11 @@ func waitForSandboxes(sandboxes []*sandbox.SandboxInfo) error {
 
 		// Display status for each sandbox
 		for i, sb := range sandboxes {
-			emoji, statusText := formatTaskStatus(statuses[i])
-			fmt.Printf("🔄 [%d/%d] %s: %s %s\n", i+1, len(sandboxes), sb.Name, emoji, statusText)
+			emoji, statusText := formatTaskStatus(statuses[i].GetState())
+			if attempt := statuses[i].GetAttempt(); attempt > 0 {
+				statusText = fmt.Sprintf("%s (fix attempt %d)", statusText, attempt)
+			}
+			fmt.Printf("🔄 [%d/%d] %s: %s %s%s\n", i+1, len(sandboxes), sb.Name, emoji, statusText, formatVerificationSummary(statuses[i].GetVerification()))
 		}
 
 		// Move cursor back up to overwrite on next iteration
@@ -210,12 +220,12 @@ func moveCursorUp(numLines int) {
 	}
 }
 
-// getSandboxTaskStatus gets the current task status for a sandbox
-func getSandboxTaskStatus(sandboxInfo *sandbox.SandboxInfo) (pb.TaskStatusResponse_TaskState, error) {
+// getSandboxTaskStatus gets the status of the most recent task of a sandbox
+func getSandboxTaskStatus(sandboxInfo *sandbox.SandboxInfo) (*pb.TaskStatusResponse, error) {
 	// Get daemon connection
-	daemonAddr, cleanup, err := getDaemonConnection(sandboxInfo.Name)
+	daemonAddr, dialOpts, cleanup, err := getDaemonConnection(sandboxInfo.Name)
 	if err != nil {
-		return pb.TaskStatusResponse_FAILED, fmt.Errorf("failed to get daemon connection: %w", err)
+		return nil, fmt.Errorf("failed to get daemon connection: %w", err)
 	}
 	defer cleanup()
 
@@ -223,9 +233,9 @@ func getSandboxTaskStatus(sandboxInfo *sandbox.SandboxInfo) (pb.TaskStatusRespon
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	conn, err := grpc.NewClient(daemonAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(daemonAddr, dialOpts...)
 	if err != nil {
-		return pb.TaskStatusResponse_FAILED, fmt.Errorf("failed to connect to daemon: %w", err)
+		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
 	defer conn.Close()
 
@@ -236,10 +246,10 @@ func getSandboxTaskStatus(sandboxInfo *sandbox.SandboxInfo) (pb.TaskStatusRespon
 	if err != nil {
 		// If there's an error (e.g., no tasks found), consider it completed
 		utils.DebugPrintf("GetTaskStatus error for %s: %v (treating as completed)\n", sandboxInfo.Name, err)
-		return pb.TaskStatusResponse_COMPLETED, nil
+		return &pb.TaskStatusResponse{State: pb.TaskStatusResponse_COMPLETED}, nil
 	}
 
-	return status.State, nil
+	return status, nil
 }
 
 // formatTaskStatus formats a task status for display
@@ -253,7 +263,38 @@ func formatTaskStatus(state pb.TaskStatusResponse_TaskState) (string, string) {
 		return "✅", "Completed"
 	case pb.TaskStatusResponse_FAILED:
 		return "❌", "Failed"
+	case pb.TaskStatusResponse_CANCELLED:
+		return "⚪", "Cancelled"
+	case pb.TaskStatusResponse_INTERRUPTED:
+		return "🟠", "Interrupted"
+	case pb.TaskStatusResponse_TIMED_OUT:
+		return "⏰", "Timed out"
+	case pb.TaskStatusResponse_VERIFICATION_FAILED:
+		return "🧪", "Verification failed"
 	default:
 		return "❓", "Unknown"
 	}
+}
+
+// formatVerificationSummary formats the verification results of a task as a
+// short suffix for its status line, such as " (verification: 1/2 passed)"
+func formatVerificationSummary(results []*pb.VerificationResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+
+	passed := 0
+	var failed []string
+	for _, result := range results {
+		if result.ExitCode == 0 {
+			passed++
+		} else {
+			failed = append(failed, result.Command)
+		}
+	}
+
+	if len(failed) == 0 {
+		return fmt.Sprintf(" (verification: %d/%d passed)", passed, len(results))
+	}
+	return fmt.Sprintf(" (verification: %d/%d passed, failed: %s)", passed, len(results), strings.Join(failed, ", "))
 }
\ No newline at end of file
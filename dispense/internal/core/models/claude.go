@@ -2,9 +2,15 @@ package models
 
 // ClaudeTaskRequest represents a request to run a Claude task
 type ClaudeTaskRequest struct {
-	SandboxIdentifier string
-	TaskDescription   string
-	Model             string
+	SandboxIdentifier  string
+	TaskDescription    string
+	Model              string
+	ResumeTaskID       string   // optional, continue the Claude conversation of this task
+	ContinueSession    bool     // optional, continue the Claude conversation of the latest task
+	TimeoutSeconds     int32    // optional, stop the task after this many seconds
+	IdleTimeoutSeconds int32    // optional, stop the task after this many seconds without output
+	VerifyCommands     []string // optional, commands that must succeed once Claude has finished
+	MaxFixAttempts     int32    // optional, follow-up runs that fix failed verify commands
 }
 
 // ClaudeStatusRequest represents a request to get Claude status
@@ -19,6 +25,27 @@ type ClaudeTaskResponse struct {
 	ErrorMsg  string `json:"error,omitempty"`
 }
 
+// ClaudeTaskEvent represents a single output event of a running Claude task
+type ClaudeTaskEvent struct {
+	Type       string       `json:"type"` // STDOUT, STDERR, STATUS, ERROR, ASSISTANT, TOOL_USE, TOOL_RESULT, USAGE, RESULT, SYSTEM or VERIFICATION
+	Content    string       `json:"content"`
+	Timestamp  int64        `json:"timestamp"`
+	ExitCode   int32        `json:"exit_code,omitempty"`
+	IsFinished bool         `json:"is_finished,omitempty"`
+	SessionID  string       `json:"session_id,omitempty"`
+	ToolName   string       `json:"tool_name,omitempty"`
+	Usage      *ClaudeUsage `json:"usage,omitempty"`
+}
+
+// ClaudeUsage represents the token usage and cost of a Claude session
+type ClaudeUsage struct {
+	InputTokens              int64   `json:"input_tokens"`
+	OutputTokens             int64   `json:"output_tokens"`
+	CacheCreationInputTokens int64   `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64   `json:"cache_read_input_tokens"`
+	TotalCostUSD             float64 `json:"total_cost_usd"`
+}
+
 // ClaudeStatusResponse represents Claude daemon status
 type ClaudeStatusResponse struct {
 	Connected    bool   `json:"connected"`
@@ -38,4 +65,44 @@ type ClaudeLogsResponse struct {
 	Success bool     `json:"success"`
 	Logs    []string `json:"logs"`
 	ErrorMsg string  `json:"error,omitempty"`
-}
\ No newline at end of file
+}
+
+// ClaudeCancelRequest represents a request to cancel a running Claude task
+type ClaudeCancelRequest struct {
+	SandboxIdentifier string
+	TaskID            string // optional, if empty cancels the most recent task
+}
+
+// ClaudeCancelResponse represents the result of cancelling a Claude task
+type ClaudeCancelResponse struct {
+	Success  bool   `json:"success"`
+	Message  string `json:"message,omitempty"`
+	State    string `json:"state,omitempty"`
+	ErrorMsg string `json:"error,omitempty"`
+}
+// WorkspaceDiffRequest represents a request for the changes in a sandbox's workspace
+type WorkspaceDiffRequest struct {
+	SandboxIdentifier string
+	BaseRef           string // optional, defaults to the commit the workspace started from
+	IncludePatches    bool
+}
+
+// FileDiff represents the changes to one file in a sandbox's workspace
+type FileDiff struct {
+	Path           string `json:"path"`
+	OldPath        string `json:"old_path,omitempty"`
+	Status         string `json:"status"`
+	Additions      int    `json:"additions"`
+	Deletions      int    `json:"deletions"`
+	Binary         bool   `json:"binary,omitempty"`
+	Patch          string `json:"patch,omitempty"`
+	PatchTruncated bool   `json:"patch_truncated,omitempty"`
+}
+
+// WorkspaceDiffResponse represents the changes in a sandbox's workspace
+type WorkspaceDiffResponse struct {
+	BaseCommit string      `json:"base_commit"`
+	HeadCommit string      `json:"head_commit,omitempty"`
+	Branch     string      `json:"branch,omitempty"`
+	Files      []*FileDiff `json:"files"`
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"time"
 
+	"cli/pkg/utils"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 )
@@ -18,7 +20,7 @@ type LoggingInterceptor struct {
 // NewLoggingInterceptor creates a new logging interceptor
 func NewLoggingInterceptor() *LoggingInterceptor {
 	return &LoggingInterceptor{
-		logger: log.New(os.Stdout, "[grpc-middleware] ", log.LstdFlags),
+		logger: log.New(utils.NewRedactingWriter(os.Stdout), "[grpc-middleware] ", log.LstdFlags),
 	}
 }
 
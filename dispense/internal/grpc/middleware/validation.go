@@ -37,6 +37,8 @@ func (v *ValidationInterceptor) validateRequest(req interface{}, method string)
 		return v.validateCreateSandboxRequest(req)
 	case strings.Contains(method, "DeleteSandbox"):
 		return v.validateDeleteSandboxRequest(req)
+	case strings.Contains(method, "GetSandboxDiff"):
+		return v.validateGetSandboxDiffRequest(req)
 	case strings.Contains(method, "GetSandbox"):
 		return v.validateGetSandboxRequest(req)
 	case strings.Contains(method, "WaitForSandbox"):
@@ -47,6 +49,8 @@ func (v *ValidationInterceptor) validateRequest(req interface{}, method string)
 		return v.validateGetClaudeStatusRequest(req)
 	case strings.Contains(method, "GetClaudeLogs"):
 		return v.validateGetClaudeLogsRequest(req)
+	case strings.Contains(method, "CancelClaudeTask"):
+		return v.validateCancelClaudeTaskRequest(req)
 	case strings.Contains(method, "SetAPIKey"):
 		return v.validateSetAPIKeyRequest(req)
 	case strings.Contains(method, "ValidateAPIKey"):
@@ -109,6 +113,24 @@ func (v *ValidationInterceptor) validateGetSandboxRequest(req interface{}) error
 	return nil
 }
 
+// validateGetSandboxDiffRequest validates get sandbox diff request
+func (v *ValidationInterceptor) validateGetSandboxDiffRequest(req interface{}) error {
+	r, ok := req.(*pb.GetSandboxDiffRequest)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "invalid request type")
+	}
+
+	if strings.TrimSpace(r.Identifier) == "" {
+		return status.Error(codes.InvalidArgument, "identifier is required")
+	}
+
+	if strings.HasPrefix(r.BaseRef, "-") {
+		return status.Error(codes.InvalidArgument, "base_ref cannot start with '-'")
+	}
+
+	return nil
+}
+
 // validateWaitForSandboxRequest validates wait for sandbox request
 func (v *ValidationInterceptor) validateWaitForSandboxRequest(req interface{}) error {
 	r, ok := req.(*pb.WaitForSandboxRequest)
@@ -142,6 +164,23 @@ func (v *ValidationInterceptor) validateRunClaudeTaskRequest(req interface{}) er
 		return status.Error(codes.InvalidArgument, "task_description is required")
 	}
 
+	if r.TimeoutSeconds < 0 || r.IdleTimeoutSeconds < 0 {
+		return status.Error(codes.InvalidArgument, "timeout_seconds and idle_timeout_seconds must not be negative")
+	}
+
+	for _, command := range r.VerifyCommands {
+		if strings.TrimSpace(command) == "" {
+			return status.Error(codes.InvalidArgument, "verify_commands must not contain empty commands")
+		}
+	}
+
+	if r.MaxFixAttempts < 0 {
+		return status.Error(codes.InvalidArgument, "max_fix_attempts must not be negative")
+	}
+	if r.MaxFixAttempts > 0 && len(r.VerifyCommands) == 0 {
+		return status.Error(codes.InvalidArgument, "max_fix_attempts requires verify_commands")
+	}
+
 	return nil
 }
 
@@ -173,6 +212,20 @@ func (v *ValidationInterceptor) validateGetClaudeLogsRequest(req interface{}) er
 	return nil
 }
 
+// validateCancelClaudeTaskRequest validates cancel claude task request
+func (v *ValidationInterceptor) validateCancelClaudeTaskRequest(req interface{}) error {
+	r, ok := req.(*pb.CancelClaudeTaskRequest)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "invalid request type")
+	}
+
+	if strings.TrimSpace(r.SandboxIdentifier) == "" {
+		return status.Error(codes.InvalidArgument, "sandbox_identifier is required")
+	}
+
+	return nil
+}
+
 // validateSetAPIKeyRequest validates set API key request
 func (v *ValidationInterceptor) validateSetAPIKeyRequest(req interface{}) error {
 	r, ok := req.(*pb.SetAPIKeyRequest)
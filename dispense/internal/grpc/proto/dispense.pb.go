@@ -25,25 +25,46 @@ const (
 type RunClaudeTaskResponse_ResponseType int32
 
 const (
-	RunClaudeTaskResponse_STDOUT RunClaudeTaskResponse_ResponseType = 0
-	RunClaudeTaskResponse_STDERR RunClaudeTaskResponse_ResponseType = 1
-	RunClaudeTaskResponse_STATUS RunClaudeTaskResponse_ResponseType = 2
-	RunClaudeTaskResponse_ERROR  RunClaudeTaskResponse_ResponseType = 3
+	RunClaudeTaskResponse_STDOUT       RunClaudeTaskResponse_ResponseType = 0
+	RunClaudeTaskResponse_STDERR       RunClaudeTaskResponse_ResponseType = 1
+	RunClaudeTaskResponse_STATUS       RunClaudeTaskResponse_ResponseType = 2
+	RunClaudeTaskResponse_ERROR        RunClaudeTaskResponse_ResponseType = 3
+	RunClaudeTaskResponse_ASSISTANT    RunClaudeTaskResponse_ResponseType = 4
+	RunClaudeTaskResponse_TOOL_USE     RunClaudeTaskResponse_ResponseType = 5
+	RunClaudeTaskResponse_TOOL_RESULT  RunClaudeTaskResponse_ResponseType = 6
+	RunClaudeTaskResponse_USAGE        RunClaudeTaskResponse_ResponseType = 7
+	RunClaudeTaskResponse_RESULT       RunClaudeTaskResponse_ResponseType = 8
+	RunClaudeTaskResponse_SYSTEM       RunClaudeTaskResponse_ResponseType = 9
+	RunClaudeTaskResponse_VERIFICATION RunClaudeTaskResponse_ResponseType = 10
 )
 
 // Enum value maps for RunClaudeTaskResponse_ResponseType.
 var (
 	RunClaudeTaskResponse_ResponseType_name = map[int32]string{
-		0: "STDOUT",
-		1: "STDERR",
-		2: "STATUS",
-		3: "ERROR",
+		0:  "STDOUT",
+		1:  "STDERR",
+		2:  "STATUS",
+		3:  "ERROR",
+		4:  "ASSISTANT",
+		5:  "TOOL_USE",
+		6:  "TOOL_RESULT",
+		7:  "USAGE",
+		8:  "RESULT",
+		9:  "SYSTEM",
+		10: "VERIFICATION",
 	}
 	RunClaudeTaskResponse_ResponseType_value = map[string]int32{
-		"STDOUT": 0,
-		"STDERR": 1,
-		"STATUS": 2,
-		"ERROR":  3,
+		"STDOUT":       0,
+		"STDERR":       1,
+		"STATUS":       2,
+		"ERROR":        3,
+		"ASSISTANT":    4,
+		"TOOL_USE":     5,
+		"TOOL_RESULT":  6,
+		"USAGE":        7,
+		"RESULT":       8,
+		"SYSTEM":       9,
+		"VERIFICATION": 10,
 	}
 )
 
@@ -71,7 +92,7 @@ func (x RunClaudeTaskResponse_ResponseType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use RunClaudeTaskResponse_ResponseType.Descriptor instead.
 func (RunClaudeTaskResponse_ResponseType) EnumDescriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{11, 0}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{14, 0}
 }
 
 // Sandbox service messages
@@ -715,19 +736,261 @@ func (x *WaitForSandboxResponse) GetError() *ErrorResponse {
 	return nil
 }
 
+type GetSandboxDiffRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Identifier     string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	BaseRef        string                 `protobuf:"bytes,2,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`                       // commit to compare with, defaults to where the workspace started
+	IncludePatches bool                   `protobuf:"varint,3,opt,name=include_patches,json=includePatches,proto3" json:"include_patches,omitempty"` // include the unified diff of every file
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetSandboxDiffRequest) Reset() {
+	*x = GetSandboxDiffRequest{}
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSandboxDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSandboxDiffRequest) ProtoMessage() {}
+
+func (x *GetSandboxDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSandboxDiffRequest.ProtoReflect.Descriptor instead.
+func (*GetSandboxDiffRequest) Descriptor() ([]byte, []int) {
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetSandboxDiffRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *GetSandboxDiffRequest) GetBaseRef() string {
+	if x != nil {
+		return x.BaseRef
+	}
+	return ""
+}
+
+func (x *GetSandboxDiffRequest) GetIncludePatches() bool {
+	if x != nil {
+		return x.IncludePatches
+	}
+	return false
+}
+
+type SandboxFileDiff struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Path           string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	OldPath        string                 `protobuf:"bytes,2,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"` // for renamed files
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                  // modified, added, deleted, renamed, untracked or type_changed
+	Additions      int32                  `protobuf:"varint,4,opt,name=additions,proto3" json:"additions,omitempty"`
+	Deletions      int32                  `protobuf:"varint,5,opt,name=deletions,proto3" json:"deletions,omitempty"`
+	Binary         bool                   `protobuf:"varint,6,opt,name=binary,proto3" json:"binary,omitempty"`
+	Patch          string                 `protobuf:"bytes,7,opt,name=patch,proto3" json:"patch,omitempty"`
+	PatchTruncated bool                   `protobuf:"varint,8,opt,name=patch_truncated,json=patchTruncated,proto3" json:"patch_truncated,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SandboxFileDiff) Reset() {
+	*x = SandboxFileDiff{}
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SandboxFileDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SandboxFileDiff) ProtoMessage() {}
+
+func (x *SandboxFileDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SandboxFileDiff.ProtoReflect.Descriptor instead.
+func (*SandboxFileDiff) Descriptor() ([]byte, []int) {
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SandboxFileDiff) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SandboxFileDiff) GetOldPath() string {
+	if x != nil {
+		return x.OldPath
+	}
+	return ""
+}
+
+func (x *SandboxFileDiff) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SandboxFileDiff) GetAdditions() int32 {
+	if x != nil {
+		return x.Additions
+	}
+	return 0
+}
+
+func (x *SandboxFileDiff) GetDeletions() int32 {
+	if x != nil {
+		return x.Deletions
+	}
+	return 0
+}
+
+func (x *SandboxFileDiff) GetBinary() bool {
+	if x != nil {
+		return x.Binary
+	}
+	return false
+}
+
+func (x *SandboxFileDiff) GetPatch() string {
+	if x != nil {
+		return x.Patch
+	}
+	return ""
+}
+
+func (x *SandboxFileDiff) GetPatchTruncated() bool {
+	if x != nil {
+		return x.PatchTruncated
+	}
+	return false
+}
+
+type GetSandboxDiffResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCommit    string                 `protobuf:"bytes,1,opt,name=base_commit,json=baseCommit,proto3" json:"base_commit,omitempty"`
+	HeadCommit    string                 `protobuf:"bytes,2,opt,name=head_commit,json=headCommit,proto3" json:"head_commit,omitempty"`
+	Branch        string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	Files         []*SandboxFileDiff     `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty"`
+	Error         *ErrorResponse         `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSandboxDiffResponse) Reset() {
+	*x = GetSandboxDiffResponse{}
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSandboxDiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSandboxDiffResponse) ProtoMessage() {}
+
+func (x *GetSandboxDiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSandboxDiffResponse.ProtoReflect.Descriptor instead.
+func (*GetSandboxDiffResponse) Descriptor() ([]byte, []int) {
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSandboxDiffResponse) GetBaseCommit() string {
+	if x != nil {
+		return x.BaseCommit
+	}
+	return ""
+}
+
+func (x *GetSandboxDiffResponse) GetHeadCommit() string {
+	if x != nil {
+		return x.HeadCommit
+	}
+	return ""
+}
+
+func (x *GetSandboxDiffResponse) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *GetSandboxDiffResponse) GetFiles() []*SandboxFileDiff {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *GetSandboxDiffResponse) GetError() *ErrorResponse {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
 // Claude service messages
 type RunClaudeTaskRequest struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	SandboxIdentifier string                 `protobuf:"bytes,1,opt,name=sandbox_identifier,json=sandboxIdentifier,proto3" json:"sandbox_identifier,omitempty"`
-	TaskDescription   string                 `protobuf:"bytes,2,opt,name=task_description,json=taskDescription,proto3" json:"task_description,omitempty"`
-	Model             string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SandboxIdentifier  string                 `protobuf:"bytes,1,opt,name=sandbox_identifier,json=sandboxIdentifier,proto3" json:"sandbox_identifier,omitempty"`
+	TaskDescription    string                 `protobuf:"bytes,2,opt,name=task_description,json=taskDescription,proto3" json:"task_description,omitempty"`
+	Model              string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	ResumeTaskId       string                 `protobuf:"bytes,4,opt,name=resume_task_id,json=resumeTaskId,proto3" json:"resume_task_id,omitempty"`                    // optional, continue the Claude conversation of this task
+	ContinueSession    bool                   `protobuf:"varint,5,opt,name=continue_session,json=continueSession,proto3" json:"continue_session,omitempty"`            // optional, continue the Claude conversation of the latest task
+	TimeoutSeconds     int32                  `protobuf:"varint,6,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`               // optional, stop the task after this many seconds (default: sandbox default)
+	IdleTimeoutSeconds int32                  `protobuf:"varint,7,opt,name=idle_timeout_seconds,json=idleTimeoutSeconds,proto3" json:"idle_timeout_seconds,omitempty"` // optional, stop the task after this many seconds without output (default: sandbox default)
+	VerifyCommands     []string               `protobuf:"bytes,8,rep,name=verify_commands,json=verifyCommands,proto3" json:"verify_commands,omitempty"`                // optional, commands that must succeed once Claude has finished
+	MaxFixAttempts     int32                  `protobuf:"varint,9,opt,name=max_fix_attempts,json=maxFixAttempts,proto3" json:"max_fix_attempts,omitempty"`             // optional, follow-up runs that fix failed verify_commands (default: 0)
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *RunClaudeTaskRequest) Reset() {
 	*x = RunClaudeTaskRequest{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[10]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -739,7 +1002,7 @@ func (x *RunClaudeTaskRequest) String() string {
 func (*RunClaudeTaskRequest) ProtoMessage() {}
 
 func (x *RunClaudeTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[10]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -752,7 +1015,7 @@ func (x *RunClaudeTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunClaudeTaskRequest.ProtoReflect.Descriptor instead.
 func (*RunClaudeTaskRequest) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{10}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RunClaudeTaskRequest) GetSandboxIdentifier() string {
@@ -776,6 +1039,48 @@ func (x *RunClaudeTaskRequest) GetModel() string {
 	return ""
 }
 
+func (x *RunClaudeTaskRequest) GetResumeTaskId() string {
+	if x != nil {
+		return x.ResumeTaskId
+	}
+	return ""
+}
+
+func (x *RunClaudeTaskRequest) GetContinueSession() bool {
+	if x != nil {
+		return x.ContinueSession
+	}
+	return false
+}
+
+func (x *RunClaudeTaskRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *RunClaudeTaskRequest) GetIdleTimeoutSeconds() int32 {
+	if x != nil {
+		return x.IdleTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *RunClaudeTaskRequest) GetVerifyCommands() []string {
+	if x != nil {
+		return x.VerifyCommands
+	}
+	return nil
+}
+
+func (x *RunClaudeTaskRequest) GetMaxFixAttempts() int32 {
+	if x != nil {
+		return x.MaxFixAttempts
+	}
+	return 0
+}
+
 type RunClaudeTaskResponse struct {
 	state         protoimpl.MessageState             `protogen:"open.v1"`
 	Type          RunClaudeTaskResponse_ResponseType `protobuf:"varint,1,opt,name=type,proto3,enum=dispense.RunClaudeTaskResponse_ResponseType" json:"type,omitempty"`
@@ -783,13 +1088,16 @@ type RunClaudeTaskResponse struct {
 	Timestamp     int64                              `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	ExitCode      int32                              `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
 	IsFinished    bool                               `protobuf:"varint,5,opt,name=is_finished,json=isFinished,proto3" json:"is_finished,omitempty"`
+	SessionId     string                             `protobuf:"bytes,6,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	ToolName      string                             `protobuf:"bytes,7,opt,name=tool_name,json=toolName,proto3" json:"tool_name,omitempty"` // Only set for TOOL_USE
+	Usage         *ClaudeUsage                       `protobuf:"bytes,8,opt,name=usage,proto3" json:"usage,omitempty"`                       // Only set for USAGE
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RunClaudeTaskResponse) Reset() {
 	*x = RunClaudeTaskResponse{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[11]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -801,7 +1109,7 @@ func (x *RunClaudeTaskResponse) String() string {
 func (*RunClaudeTaskResponse) ProtoMessage() {}
 
 func (x *RunClaudeTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[11]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -814,7 +1122,7 @@ func (x *RunClaudeTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunClaudeTaskResponse.ProtoReflect.Descriptor instead.
 func (*RunClaudeTaskResponse) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{11}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *RunClaudeTaskResponse) GetType() RunClaudeTaskResponse_ResponseType {
@@ -852,6 +1160,103 @@ func (x *RunClaudeTaskResponse) GetIsFinished() bool {
 	return false
 }
 
+func (x *RunClaudeTaskResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RunClaudeTaskResponse) GetToolName() string {
+	if x != nil {
+		return x.ToolName
+	}
+	return ""
+}
+
+func (x *RunClaudeTaskResponse) GetUsage() *ClaudeUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type ClaudeUsage struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	InputTokens              int64                  `protobuf:"varint,1,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens             int64                  `protobuf:"varint,2,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	CacheCreationInputTokens int64                  `protobuf:"varint,3,opt,name=cache_creation_input_tokens,json=cacheCreationInputTokens,proto3" json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int64                  `protobuf:"varint,4,opt,name=cache_read_input_tokens,json=cacheReadInputTokens,proto3" json:"cache_read_input_tokens,omitempty"`
+	TotalCostUsd             float64                `protobuf:"fixed64,5,opt,name=total_cost_usd,json=totalCostUsd,proto3" json:"total_cost_usd,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ClaudeUsage) Reset() {
+	*x = ClaudeUsage{}
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaudeUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaudeUsage) ProtoMessage() {}
+
+func (x *ClaudeUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaudeUsage.ProtoReflect.Descriptor instead.
+func (*ClaudeUsage) Descriptor() ([]byte, []int) {
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ClaudeUsage) GetInputTokens() int64 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetOutputTokens() int64 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetCacheCreationInputTokens() int64 {
+	if x != nil {
+		return x.CacheCreationInputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetCacheReadInputTokens() int64 {
+	if x != nil {
+		return x.CacheReadInputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetTotalCostUsd() float64 {
+	if x != nil {
+		return x.TotalCostUsd
+	}
+	return 0
+}
+
 type GetClaudeStatusRequest struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	SandboxIdentifier string                 `protobuf:"bytes,1,opt,name=sandbox_identifier,json=sandboxIdentifier,proto3" json:"sandbox_identifier,omitempty"`
@@ -861,7 +1266,7 @@ type GetClaudeStatusRequest struct {
 
 func (x *GetClaudeStatusRequest) Reset() {
 	*x = GetClaudeStatusRequest{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[12]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -873,7 +1278,7 @@ func (x *GetClaudeStatusRequest) String() string {
 func (*GetClaudeStatusRequest) ProtoMessage() {}
 
 func (x *GetClaudeStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[12]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -886,7 +1291,7 @@ func (x *GetClaudeStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetClaudeStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetClaudeStatusRequest) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{12}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetClaudeStatusRequest) GetSandboxIdentifier() string {
@@ -908,7 +1313,7 @@ type GetClaudeStatusResponse struct {
 
 func (x *GetClaudeStatusResponse) Reset() {
 	*x = GetClaudeStatusResponse{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[13]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -920,7 +1325,7 @@ func (x *GetClaudeStatusResponse) String() string {
 func (*GetClaudeStatusResponse) ProtoMessage() {}
 
 func (x *GetClaudeStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[13]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -933,7 +1338,7 @@ func (x *GetClaudeStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetClaudeStatusResponse.ProtoReflect.Descriptor instead.
 func (*GetClaudeStatusResponse) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{13}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetClaudeStatusResponse) GetConnected() bool {
@@ -974,7 +1379,7 @@ type GetClaudeLogsRequest struct {
 
 func (x *GetClaudeLogsRequest) Reset() {
 	*x = GetClaudeLogsRequest{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[14]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -986,7 +1391,7 @@ func (x *GetClaudeLogsRequest) String() string {
 func (*GetClaudeLogsRequest) ProtoMessage() {}
 
 func (x *GetClaudeLogsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[14]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -999,7 +1404,7 @@ func (x *GetClaudeLogsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetClaudeLogsRequest.ProtoReflect.Descriptor instead.
 func (*GetClaudeLogsRequest) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{14}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetClaudeLogsRequest) GetSandboxIdentifier() string {
@@ -1027,7 +1432,7 @@ type GetClaudeLogsResponse struct {
 
 func (x *GetClaudeLogsResponse) Reset() {
 	*x = GetClaudeLogsResponse{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[15]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1039,7 +1444,7 @@ func (x *GetClaudeLogsResponse) String() string {
 func (*GetClaudeLogsResponse) ProtoMessage() {}
 
 func (x *GetClaudeLogsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[15]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1052,7 +1457,7 @@ func (x *GetClaudeLogsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetClaudeLogsResponse.ProtoReflect.Descriptor instead.
 func (*GetClaudeLogsResponse) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{15}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *GetClaudeLogsResponse) GetSuccess() bool {
@@ -1076,6 +1481,126 @@ func (x *GetClaudeLogsResponse) GetError() *ErrorResponse {
 	return nil
 }
 
+type CancelClaudeTaskRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SandboxIdentifier string                 `protobuf:"bytes,1,opt,name=sandbox_identifier,json=sandboxIdentifier,proto3" json:"sandbox_identifier,omitempty"`
+	TaskId            string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CancelClaudeTaskRequest) Reset() {
+	*x = CancelClaudeTaskRequest{}
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelClaudeTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelClaudeTaskRequest) ProtoMessage() {}
+
+func (x *CancelClaudeTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelClaudeTaskRequest.ProtoReflect.Descriptor instead.
+func (*CancelClaudeTaskRequest) Descriptor() ([]byte, []int) {
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CancelClaudeTaskRequest) GetSandboxIdentifier() string {
+	if x != nil {
+		return x.SandboxIdentifier
+	}
+	return ""
+}
+
+func (x *CancelClaudeTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type CancelClaudeTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Error         *ErrorResponse         `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelClaudeTaskResponse) Reset() {
+	*x = CancelClaudeTaskResponse{}
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelClaudeTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelClaudeTaskResponse) ProtoMessage() {}
+
+func (x *CancelClaudeTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelClaudeTaskResponse.ProtoReflect.Descriptor instead.
+func (*CancelClaudeTaskResponse) Descriptor() ([]byte, []int) {
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CancelClaudeTaskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelClaudeTaskResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CancelClaudeTaskResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CancelClaudeTaskResponse) GetError() *ErrorResponse {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
 // Config service messages
 type GetAPIKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1086,7 +1611,7 @@ type GetAPIKeyRequest struct {
 
 func (x *GetAPIKeyRequest) Reset() {
 	*x = GetAPIKeyRequest{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[16]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1098,7 +1623,7 @@ func (x *GetAPIKeyRequest) String() string {
 func (*GetAPIKeyRequest) ProtoMessage() {}
 
 func (x *GetAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[16]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1111,7 +1636,7 @@ func (x *GetAPIKeyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAPIKeyRequest.ProtoReflect.Descriptor instead.
 func (*GetAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{16}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *GetAPIKeyRequest) GetInteractive() bool {
@@ -1131,7 +1656,7 @@ type GetAPIKeyResponse struct {
 
 func (x *GetAPIKeyResponse) Reset() {
 	*x = GetAPIKeyResponse{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[17]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1143,7 +1668,7 @@ func (x *GetAPIKeyResponse) String() string {
 func (*GetAPIKeyResponse) ProtoMessage() {}
 
 func (x *GetAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[17]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1156,7 +1681,7 @@ func (x *GetAPIKeyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAPIKeyResponse.ProtoReflect.Descriptor instead.
 func (*GetAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{17}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *GetAPIKeyResponse) GetApiKey() string {
@@ -1182,7 +1707,7 @@ type SetAPIKeyRequest struct {
 
 func (x *SetAPIKeyRequest) Reset() {
 	*x = SetAPIKeyRequest{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[18]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1194,7 +1719,7 @@ func (x *SetAPIKeyRequest) String() string {
 func (*SetAPIKeyRequest) ProtoMessage() {}
 
 func (x *SetAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[18]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1207,7 +1732,7 @@ func (x *SetAPIKeyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetAPIKeyRequest.ProtoReflect.Descriptor instead.
 func (*SetAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{18}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *SetAPIKeyRequest) GetApiKey() string {
@@ -1228,7 +1753,7 @@ type SetAPIKeyResponse struct {
 
 func (x *SetAPIKeyResponse) Reset() {
 	*x = SetAPIKeyResponse{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[19]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1240,7 +1765,7 @@ func (x *SetAPIKeyResponse) String() string {
 func (*SetAPIKeyResponse) ProtoMessage() {}
 
 func (x *SetAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[19]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1253,7 +1778,7 @@ func (x *SetAPIKeyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetAPIKeyResponse.ProtoReflect.Descriptor instead.
 func (*SetAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{19}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *SetAPIKeyResponse) GetSuccess() bool {
@@ -1286,7 +1811,7 @@ type ValidateAPIKeyRequest struct {
 
 func (x *ValidateAPIKeyRequest) Reset() {
 	*x = ValidateAPIKeyRequest{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[20]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1298,7 +1823,7 @@ func (x *ValidateAPIKeyRequest) String() string {
 func (*ValidateAPIKeyRequest) ProtoMessage() {}
 
 func (x *ValidateAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[20]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1311,7 +1836,7 @@ func (x *ValidateAPIKeyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidateAPIKeyRequest.ProtoReflect.Descriptor instead.
 func (*ValidateAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{20}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ValidateAPIKeyRequest) GetApiKey() string {
@@ -1332,7 +1857,7 @@ type ValidateAPIKeyResponse struct {
 
 func (x *ValidateAPIKeyResponse) Reset() {
 	*x = ValidateAPIKeyResponse{}
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[21]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1344,7 +1869,7 @@ func (x *ValidateAPIKeyResponse) String() string {
 func (*ValidateAPIKeyResponse) ProtoMessage() {}
 
 func (x *ValidateAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[21]
+	mi := &file_internal_grpc_proto_dispense_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1357,7 +1882,7 @@ func (x *ValidateAPIKeyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidateAPIKeyResponse.ProtoReflect.Descriptor instead.
 func (*ValidateAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{21}
+	return file_internal_grpc_proto_dispense_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *ValidateAPIKeyResponse) GetValid() bool {
@@ -1442,18 +1967,51 @@ const file_internal_grpc_proto_dispense_proto_rawDesc = "" +
 	"\x16WaitForSandboxResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
-	"\x05error\x18\x03 \x01(\v2\x17.dispense.ErrorResponseR\x05error\"\x86\x01\n" +
+	"\x05error\x18\x03 \x01(\v2\x17.dispense.ErrorResponseR\x05error\"{\n" +
+	"\x15GetSandboxDiffRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x19\n" +
+	"\bbase_ref\x18\x02 \x01(\tR\abaseRef\x12'\n" +
+	"\x0finclude_patches\x18\x03 \x01(\bR\x0eincludePatches\"\xeb\x01\n" +
+	"\x0fSandboxFileDiff\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x19\n" +
+	"\bold_path\x18\x02 \x01(\tR\aoldPath\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1c\n" +
+	"\tadditions\x18\x04 \x01(\x05R\tadditions\x12\x1c\n" +
+	"\tdeletions\x18\x05 \x01(\x05R\tdeletions\x12\x16\n" +
+	"\x06binary\x18\x06 \x01(\bR\x06binary\x12\x14\n" +
+	"\x05patch\x18\a \x01(\tR\x05patch\x12'\n" +
+	"\x0fpatch_truncated\x18\b \x01(\bR\x0epatchTruncated\"\xd2\x01\n" +
+	"\x16GetSandboxDiffResponse\x12\x1f\n" +
+	"\vbase_commit\x18\x01 \x01(\tR\n" +
+	"baseCommit\x12\x1f\n" +
+	"\vhead_commit\x18\x02 \x01(\tR\n" +
+	"headCommit\x12\x16\n" +
+	"\x06branch\x18\x03 \x01(\tR\x06branch\x12/\n" +
+	"\x05files\x18\x04 \x03(\v2\x19.dispense.SandboxFileDiffR\x05files\x12-\n" +
+	"\x05error\x18\x05 \x01(\v2\x17.dispense.ErrorResponseR\x05error\"\x85\x03\n" +
 	"\x14RunClaudeTaskRequest\x12-\n" +
 	"\x12sandbox_identifier\x18\x01 \x01(\tR\x11sandboxIdentifier\x12)\n" +
 	"\x10task_description\x18\x02 \x01(\tR\x0ftaskDescription\x12\x14\n" +
-	"\x05model\x18\x03 \x01(\tR\x05model\"\x8e\x02\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12$\n" +
+	"\x0eresume_task_id\x18\x04 \x01(\tR\fresumeTaskId\x12)\n" +
+	"\x10continue_session\x18\x05 \x01(\bR\x0fcontinueSession\x12'\n" +
+	"\x0ftimeout_seconds\x18\x06 \x01(\x05R\x0etimeoutSeconds\x120\n" +
+	"\x14idle_timeout_seconds\x18\a \x01(\x05R\x12idleTimeoutSeconds\x12'\n" +
+	"\x0fverify_commands\x18\b \x03(\tR\x0everifyCommands\x12(\n" +
+	"\x10max_fix_attempts\x18\t \x01(\x05R\x0emaxFixAttempts\"\xdb\x03\n" +
 	"\x15RunClaudeTaskResponse\x12@\n" +
 	"\x04type\x18\x01 \x01(\x0e2,.dispense.RunClaudeTaskResponse.ResponseTypeR\x04type\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1c\n" +
 	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x1b\n" +
 	"\texit_code\x18\x04 \x01(\x05R\bexitCode\x12\x1f\n" +
 	"\vis_finished\x18\x05 \x01(\bR\n" +
-	"isFinished\"=\n" +
+	"isFinished\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x06 \x01(\tR\tsessionId\x12\x1b\n" +
+	"\ttool_name\x18\a \x01(\tR\btoolName\x12+\n" +
+	"\x05usage\x18\b \x01(\v2\x15.dispense.ClaudeUsageR\x05usage\"\xa0\x01\n" +
 	"\fResponseType\x12\n" +
 	"\n" +
 	"\x06STDOUT\x10\x00\x12\n" +
@@ -1461,7 +2019,23 @@ const file_internal_grpc_proto_dispense_proto_rawDesc = "" +
 	"\x06STDERR\x10\x01\x12\n" +
 	"\n" +
 	"\x06STATUS\x10\x02\x12\t\n" +
-	"\x05ERROR\x10\x03\"G\n" +
+	"\x05ERROR\x10\x03\x12\r\n" +
+	"\tASSISTANT\x10\x04\x12\f\n" +
+	"\bTOOL_USE\x10\x05\x12\x0f\n" +
+	"\vTOOL_RESULT\x10\x06\x12\t\n" +
+	"\x05USAGE\x10\a\x12\n" +
+	"\n" +
+	"\x06RESULT\x10\b\x12\n" +
+	"\n" +
+	"\x06SYSTEM\x10\t\x12\x10\n" +
+	"\fVERIFICATION\x10\n" +
+	"\"\xf1\x01\n" +
+	"\vClaudeUsage\x12!\n" +
+	"\finput_tokens\x18\x01 \x01(\x03R\vinputTokens\x12#\n" +
+	"\routput_tokens\x18\x02 \x01(\x03R\foutputTokens\x12=\n" +
+	"\x1bcache_creation_input_tokens\x18\x03 \x01(\x03R\x18cacheCreationInputTokens\x125\n" +
+	"\x17cache_read_input_tokens\x18\x04 \x01(\x03R\x14cacheReadInputTokens\x12$\n" +
+	"\x0etotal_cost_usd\x18\x05 \x01(\x01R\ftotalCostUsd\"G\n" +
 	"\x16GetClaudeStatusRequest\x12-\n" +
 	"\x12sandbox_identifier\x18\x01 \x01(\tR\x11sandboxIdentifier\"\xa2\x01\n" +
 	"\x17GetClaudeStatusResponse\x12\x1c\n" +
@@ -1476,7 +2050,15 @@ const file_internal_grpc_proto_dispense_proto_rawDesc = "" +
 	"\x15GetClaudeLogsResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x12\n" +
 	"\x04logs\x18\x02 \x03(\tR\x04logs\x12-\n" +
-	"\x05error\x18\x03 \x01(\v2\x17.dispense.ErrorResponseR\x05error\"4\n" +
+	"\x05error\x18\x03 \x01(\v2\x17.dispense.ErrorResponseR\x05error\"a\n" +
+	"\x17CancelClaudeTaskRequest\x12-\n" +
+	"\x12sandbox_identifier\x18\x01 \x01(\tR\x11sandboxIdentifier\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\"\x93\x01\n" +
+	"\x18CancelClaudeTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12-\n" +
+	"\x05error\x18\x04 \x01(\v2\x17.dispense.ErrorResponseR\x05error\"4\n" +
 	"\x10GetAPIKeyRequest\x12 \n" +
 	"\vinteractive\x18\x01 \x01(\bR\vinteractive\"[\n" +
 	"\x11GetAPIKeyResponse\x12\x17\n" +
@@ -1493,18 +2075,19 @@ const file_internal_grpc_proto_dispense_proto_rawDesc = "" +
 	"\x16ValidateAPIKeyResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
-	"\x05error\x18\x03 \x01(\v2\x17.dispense.ErrorResponseR\x05error2\x88\n" +
-	"\n" +
+	"\x05error\x18\x03 \x01(\v2\x17.dispense.ErrorResponseR\x05error2\x9b\f\n" +
 	"\x0fDispenseService\x12j\n" +
 	"\rCreateSandbox\x12\x1e.dispense.CreateSandboxRequest\x1a\x1f.dispense.CreateSandboxResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/sandboxes\x12g\n" +
 	"\rListSandboxes\x12\x1e.dispense.ListSandboxesRequest\x1a\x1f.dispense.ListSandboxesResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/sandboxes\x12t\n" +
 	"\rDeleteSandbox\x12\x1e.dispense.DeleteSandboxRequest\x1a\x1f.dispense.DeleteSandboxResponse\"\"\x82\xd3\xe4\x93\x02\x1c*\x1a/v1/sandboxes/{identifier}\x12k\n" +
 	"\n" +
 	"GetSandbox\x12\x1b.dispense.GetSandboxRequest\x1a\x1c.dispense.GetSandboxResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/sandboxes/{identifier}\x12\x7f\n" +
-	"\x0eWaitForSandbox\x12\x1f.dispense.WaitForSandboxRequest\x1a .dispense.WaitForSandboxResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/sandboxes/{identifier}/wait\x12o\n" +
+	"\x0eWaitForSandbox\x12\x1f.dispense.WaitForSandboxRequest\x1a .dispense.WaitForSandboxResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/sandboxes/{identifier}/wait\x12|\n" +
+	"\x0eGetSandboxDiff\x12\x1f.dispense.GetSandboxDiffRequest\x1a .dispense.GetSandboxDiffResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/v1/sandboxes/{identifier}/diff\x12o\n" +
 	"\rRunClaudeTask\x12\x1e.dispense.RunClaudeTaskRequest\x1a\x1f.dispense.RunClaudeTaskResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/claude/tasks0\x01\x12\x86\x01\n" +
 	"\x0fGetClaudeStatus\x12 .dispense.GetClaudeStatusRequest\x1a!.dispense.GetClaudeStatusResponse\".\x82\xd3\xe4\x93\x02(\x12&/v1/claude/{sandbox_identifier}/status\x12~\n" +
-	"\rGetClaudeLogs\x12\x1e.dispense.GetClaudeLogsRequest\x1a\x1f.dispense.GetClaudeLogsResponse\",\x82\xd3\xe4\x93\x02&\x12$/v1/claude/{sandbox_identifier}/logs\x12`\n" +
+	"\rGetClaudeLogs\x12\x1e.dispense.GetClaudeLogsRequest\x1a\x1f.dispense.GetClaudeLogsResponse\",\x82\xd3\xe4\x93\x02&\x12$/v1/claude/{sandbox_identifier}/logs\x12\x92\x01\n" +
+	"\x10CancelClaudeTask\x12!.dispense.CancelClaudeTaskRequest\x1a\".dispense.CancelClaudeTaskResponse\"7\x82\xd3\xe4\x93\x021*//v1/claude/{sandbox_identifier}/tasks/{task_id}\x12`\n" +
 	"\tGetAPIKey\x12\x1a.dispense.GetAPIKeyRequest\x1a\x1b.dispense.GetAPIKeyResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/config/api-key\x12c\n" +
 	"\tSetAPIKey\x12\x1a.dispense.SetAPIKeyRequest\x1a\x1b.dispense.SetAPIKeyResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/config/api-key\x12{\n" +
 	"\x0eValidateAPIKey\x12\x1f.dispense.ValidateAPIKeyRequest\x1a .dispense.ValidateAPIKeyResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/config/api-key/validateB\x19Z\x17cli/internal/grpc/protob\x06proto3"
@@ -1522,7 +2105,7 @@ func file_internal_grpc_proto_dispense_proto_rawDescGZIP() []byte {
 }
 
 var file_internal_grpc_proto_dispense_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_internal_grpc_proto_dispense_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_internal_grpc_proto_dispense_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_internal_grpc_proto_dispense_proto_goTypes = []any{
 	(RunClaudeTaskResponse_ResponseType)(0), // 0: dispense.RunClaudeTaskResponse.ResponseType
 	(*CreateSandboxRequest)(nil),            // 1: dispense.CreateSandboxRequest
@@ -1535,67 +2118,81 @@ var file_internal_grpc_proto_dispense_proto_goTypes = []any{
 	(*GetSandboxResponse)(nil),              // 8: dispense.GetSandboxResponse
 	(*WaitForSandboxRequest)(nil),           // 9: dispense.WaitForSandboxRequest
 	(*WaitForSandboxResponse)(nil),          // 10: dispense.WaitForSandboxResponse
-	(*RunClaudeTaskRequest)(nil),            // 11: dispense.RunClaudeTaskRequest
-	(*RunClaudeTaskResponse)(nil),           // 12: dispense.RunClaudeTaskResponse
-	(*GetClaudeStatusRequest)(nil),          // 13: dispense.GetClaudeStatusRequest
-	(*GetClaudeStatusResponse)(nil),         // 14: dispense.GetClaudeStatusResponse
-	(*GetClaudeLogsRequest)(nil),            // 15: dispense.GetClaudeLogsRequest
-	(*GetClaudeLogsResponse)(nil),           // 16: dispense.GetClaudeLogsResponse
-	(*GetAPIKeyRequest)(nil),                // 17: dispense.GetAPIKeyRequest
-	(*GetAPIKeyResponse)(nil),               // 18: dispense.GetAPIKeyResponse
-	(*SetAPIKeyRequest)(nil),                // 19: dispense.SetAPIKeyRequest
-	(*SetAPIKeyResponse)(nil),               // 20: dispense.SetAPIKeyResponse
-	(*ValidateAPIKeyRequest)(nil),           // 21: dispense.ValidateAPIKeyRequest
-	(*ValidateAPIKeyResponse)(nil),          // 22: dispense.ValidateAPIKeyResponse
-	(*ResourceAllocation)(nil),              // 23: dispense.ResourceAllocation
-	(*TaskData)(nil),                        // 24: dispense.TaskData
-	(*SandboxInfo)(nil),                     // 25: dispense.SandboxInfo
-	(*ErrorResponse)(nil),                   // 26: dispense.ErrorResponse
+	(*GetSandboxDiffRequest)(nil),           // 11: dispense.GetSandboxDiffRequest
+	(*SandboxFileDiff)(nil),                 // 12: dispense.SandboxFileDiff
+	(*GetSandboxDiffResponse)(nil),          // 13: dispense.GetSandboxDiffResponse
+	(*RunClaudeTaskRequest)(nil),            // 14: dispense.RunClaudeTaskRequest
+	(*RunClaudeTaskResponse)(nil),           // 15: dispense.RunClaudeTaskResponse
+	(*ClaudeUsage)(nil),                     // 16: dispense.ClaudeUsage
+	(*GetClaudeStatusRequest)(nil),          // 17: dispense.GetClaudeStatusRequest
+	(*GetClaudeStatusResponse)(nil),         // 18: dispense.GetClaudeStatusResponse
+	(*GetClaudeLogsRequest)(nil),            // 19: dispense.GetClaudeLogsRequest
+	(*GetClaudeLogsResponse)(nil),           // 20: dispense.GetClaudeLogsResponse
+	(*CancelClaudeTaskRequest)(nil),         // 21: dispense.CancelClaudeTaskRequest
+	(*CancelClaudeTaskResponse)(nil),        // 22: dispense.CancelClaudeTaskResponse
+	(*GetAPIKeyRequest)(nil),                // 23: dispense.GetAPIKeyRequest
+	(*GetAPIKeyResponse)(nil),               // 24: dispense.GetAPIKeyResponse
+	(*SetAPIKeyRequest)(nil),                // 25: dispense.SetAPIKeyRequest
+	(*SetAPIKeyResponse)(nil),               // 26: dispense.SetAPIKeyResponse
+	(*ValidateAPIKeyRequest)(nil),           // 27: dispense.ValidateAPIKeyRequest
+	(*ValidateAPIKeyResponse)(nil),          // 28: dispense.ValidateAPIKeyResponse
+	(*ResourceAllocation)(nil),              // 29: dispense.ResourceAllocation
+	(*TaskData)(nil),                        // 30: dispense.TaskData
+	(*SandboxInfo)(nil),                     // 31: dispense.SandboxInfo
+	(*ErrorResponse)(nil),                   // 32: dispense.ErrorResponse
 }
 var file_internal_grpc_proto_dispense_proto_depIdxs = []int32{
-	23, // 0: dispense.CreateSandboxRequest.resources:type_name -> dispense.ResourceAllocation
-	24, // 1: dispense.CreateSandboxRequest.task_data:type_name -> dispense.TaskData
-	25, // 2: dispense.CreateSandboxResponse.sandbox:type_name -> dispense.SandboxInfo
-	26, // 3: dispense.CreateSandboxResponse.error:type_name -> dispense.ErrorResponse
-	25, // 4: dispense.ListSandboxesResponse.sandboxes:type_name -> dispense.SandboxInfo
-	26, // 5: dispense.ListSandboxesResponse.error:type_name -> dispense.ErrorResponse
-	26, // 6: dispense.DeleteSandboxResponse.error:type_name -> dispense.ErrorResponse
-	25, // 7: dispense.GetSandboxResponse.sandbox:type_name -> dispense.SandboxInfo
-	26, // 8: dispense.GetSandboxResponse.error:type_name -> dispense.ErrorResponse
-	26, // 9: dispense.WaitForSandboxResponse.error:type_name -> dispense.ErrorResponse
-	0,  // 10: dispense.RunClaudeTaskResponse.type:type_name -> dispense.RunClaudeTaskResponse.ResponseType
-	26, // 11: dispense.GetClaudeStatusResponse.error:type_name -> dispense.ErrorResponse
-	26, // 12: dispense.GetClaudeLogsResponse.error:type_name -> dispense.ErrorResponse
-	26, // 13: dispense.GetAPIKeyResponse.error:type_name -> dispense.ErrorResponse
-	26, // 14: dispense.SetAPIKeyResponse.error:type_name -> dispense.ErrorResponse
-	26, // 15: dispense.ValidateAPIKeyResponse.error:type_name -> dispense.ErrorResponse
-	1,  // 16: dispense.DispenseService.CreateSandbox:input_type -> dispense.CreateSandboxRequest
-	3,  // 17: dispense.DispenseService.ListSandboxes:input_type -> dispense.ListSandboxesRequest
-	5,  // 18: dispense.DispenseService.DeleteSandbox:input_type -> dispense.DeleteSandboxRequest
-	7,  // 19: dispense.DispenseService.GetSandbox:input_type -> dispense.GetSandboxRequest
-	9,  // 20: dispense.DispenseService.WaitForSandbox:input_type -> dispense.WaitForSandboxRequest
-	11, // 21: dispense.DispenseService.RunClaudeTask:input_type -> dispense.RunClaudeTaskRequest
-	13, // 22: dispense.DispenseService.GetClaudeStatus:input_type -> dispense.GetClaudeStatusRequest
-	15, // 23: dispense.DispenseService.GetClaudeLogs:input_type -> dispense.GetClaudeLogsRequest
-	17, // 24: dispense.DispenseService.GetAPIKey:input_type -> dispense.GetAPIKeyRequest
-	19, // 25: dispense.DispenseService.SetAPIKey:input_type -> dispense.SetAPIKeyRequest
-	21, // 26: dispense.DispenseService.ValidateAPIKey:input_type -> dispense.ValidateAPIKeyRequest
-	2,  // 27: dispense.DispenseService.CreateSandbox:output_type -> dispense.CreateSandboxResponse
-	4,  // 28: dispense.DispenseService.ListSandboxes:output_type -> dispense.ListSandboxesResponse
-	6,  // 29: dispense.DispenseService.DeleteSandbox:output_type -> dispense.DeleteSandboxResponse
-	8,  // 30: dispense.DispenseService.GetSandbox:output_type -> dispense.GetSandboxResponse
-	10, // 31: dispense.DispenseService.WaitForSandbox:output_type -> dispense.WaitForSandboxResponse
-	12, // 32: dispense.DispenseService.RunClaudeTask:output_type -> dispense.RunClaudeTaskResponse
-	14, // 33: dispense.DispenseService.GetClaudeStatus:output_type -> dispense.GetClaudeStatusResponse
-	16, // 34: dispense.DispenseService.GetClaudeLogs:output_type -> dispense.GetClaudeLogsResponse
-	18, // 35: dispense.DispenseService.GetAPIKey:output_type -> dispense.GetAPIKeyResponse
-	20, // 36: dispense.DispenseService.SetAPIKey:output_type -> dispense.SetAPIKeyResponse
-	22, // 37: dispense.DispenseService.ValidateAPIKey:output_type -> dispense.ValidateAPIKeyResponse
-	27, // [27:38] is the sub-list for method output_type
-	16, // [16:27] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	29, // 0: dispense.CreateSandboxRequest.resources:type_name -> dispense.ResourceAllocation
+	30, // 1: dispense.CreateSandboxRequest.task_data:type_name -> dispense.TaskData
+	31, // 2: dispense.CreateSandboxResponse.sandbox:type_name -> dispense.SandboxInfo
+	32, // 3: dispense.CreateSandboxResponse.error:type_name -> dispense.ErrorResponse
+	31, // 4: dispense.ListSandboxesResponse.sandboxes:type_name -> dispense.SandboxInfo
+	32, // 5: dispense.ListSandboxesResponse.error:type_name -> dispense.ErrorResponse
+	32, // 6: dispense.DeleteSandboxResponse.error:type_name -> dispense.ErrorResponse
+	31, // 7: dispense.GetSandboxResponse.sandbox:type_name -> dispense.SandboxInfo
+	32, // 8: dispense.GetSandboxResponse.error:type_name -> dispense.ErrorResponse
+	32, // 9: dispense.WaitForSandboxResponse.error:type_name -> dispense.ErrorResponse
+	12, // 10: dispense.GetSandboxDiffResponse.files:type_name -> dispense.SandboxFileDiff
+	32, // 11: dispense.GetSandboxDiffResponse.error:type_name -> dispense.ErrorResponse
+	0,  // 12: dispense.RunClaudeTaskResponse.type:type_name -> dispense.RunClaudeTaskResponse.ResponseType
+	16, // 13: dispense.RunClaudeTaskResponse.usage:type_name -> dispense.ClaudeUsage
+	32, // 14: dispense.GetClaudeStatusResponse.error:type_name -> dispense.ErrorResponse
+	32, // 15: dispense.GetClaudeLogsResponse.error:type_name -> dispense.ErrorResponse
+	32, // 16: dispense.CancelClaudeTaskResponse.error:type_name -> dispense.ErrorResponse
+	32, // 17: dispense.GetAPIKeyResponse.error:type_name -> dispense.ErrorResponse
+	32, // 18: dispense.SetAPIKeyResponse.error:type_name -> dispense.ErrorResponse
+	32, // 19: dispense.ValidateAPIKeyResponse.error:type_name -> dispense.ErrorResponse
+	1,  // 20: dispense.DispenseService.CreateSandbox:input_type -> dispense.CreateSandboxRequest
+	3,  // 21: dispense.DispenseService.ListSandboxes:input_type -> dispense.ListSandboxesRequest
+	5,  // 22: dispense.DispenseService.DeleteSandbox:input_type -> dispense.DeleteSandboxRequest
+	7,  // 23: dispense.DispenseService.GetSandbox:input_type -> dispense.GetSandboxRequest
+	9,  // 24: dispense.DispenseService.WaitForSandbox:input_type -> dispense.WaitForSandboxRequest
+	11, // 25: dispense.DispenseService.GetSandboxDiff:input_type -> dispense.GetSandboxDiffRequest
+	14, // 26: dispense.DispenseService.RunClaudeTask:input_type -> dispense.RunClaudeTaskRequest
+	17, // 27: dispense.DispenseService.GetClaudeStatus:input_type -> dispense.GetClaudeStatusRequest
+	19, // 28: dispense.DispenseService.GetClaudeLogs:input_type -> dispense.GetClaudeLogsRequest
+	21, // 29: dispense.DispenseService.CancelClaudeTask:input_type -> dispense.CancelClaudeTaskRequest
+	23, // 30: dispense.DispenseService.GetAPIKey:input_type -> dispense.GetAPIKeyRequest
+	25, // 31: dispense.DispenseService.SetAPIKey:input_type -> dispense.SetAPIKeyRequest
+	27, // 32: dispense.DispenseService.ValidateAPIKey:input_type -> dispense.ValidateAPIKeyRequest
+	2,  // 33: dispense.DispenseService.CreateSandbox:output_type -> dispense.CreateSandboxResponse
+	4,  // 34: dispense.DispenseService.ListSandboxes:output_type -> dispense.ListSandboxesResponse
+	6,  // 35: dispense.DispenseService.DeleteSandbox:output_type -> dispense.DeleteSandboxResponse
+	8,  // 36: dispense.DispenseService.GetSandbox:output_type -> dispense.GetSandboxResponse
+	10, // 37: dispense.DispenseService.WaitForSandbox:output_type -> dispense.WaitForSandboxResponse
+	13, // 38: dispense.DispenseService.GetSandboxDiff:output_type -> dispense.GetSandboxDiffResponse
+	15, // 39: dispense.DispenseService.RunClaudeTask:output_type -> dispense.RunClaudeTaskResponse
+	18, // 40: dispense.DispenseService.GetClaudeStatus:output_type -> dispense.GetClaudeStatusResponse
+	20, // 41: dispense.DispenseService.GetClaudeLogs:output_type -> dispense.GetClaudeLogsResponse
+	22, // 42: dispense.DispenseService.CancelClaudeTask:output_type -> dispense.CancelClaudeTaskResponse
+	24, // 43: dispense.DispenseService.GetAPIKey:output_type -> dispense.GetAPIKeyResponse
+	26, // 44: dispense.DispenseService.SetAPIKey:output_type -> dispense.SetAPIKeyResponse
+	28, // 45: dispense.DispenseService.ValidateAPIKey:output_type -> dispense.ValidateAPIKeyResponse
+	33, // [33:46] is the sub-list for method output_type
+	20, // [20:33] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_internal_grpc_proto_dispense_proto_init() }
@@ -1610,7 +2207,7 @@ func file_internal_grpc_proto_dispense_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_grpc_proto_dispense_proto_rawDesc), len(file_internal_grpc_proto_dispense_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   22,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
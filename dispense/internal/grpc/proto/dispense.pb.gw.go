@@ -234,6 +234,59 @@ func local_request_DispenseService_WaitForSandbox_0(ctx context.Context, marshal
 	return msg, metadata, err
 }
 
+var filter_DispenseService_GetSandboxDiff_0 = &utilities.DoubleArray{Encoding: map[string]int{"identifier": 0}, Base: []int{1, 1, 0}, Check: []int{0, 1, 2}}
+
+func request_DispenseService_GetSandboxDiff_0(ctx context.Context, marshaler runtime.Marshaler, client DispenseServiceClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq GetSandboxDiffRequest
+		metadata runtime.ServerMetadata
+		err      error
+	)
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+	}
+	val, ok := pathParams["identifier"]
+	if !ok {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "identifier")
+	}
+	protoReq.Identifier, err = runtime.String(val)
+	if err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "identifier", err)
+	}
+	if err := req.ParseForm(); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_DispenseService_GetSandboxDiff_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	msg, err := client.GetSandboxDiff(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	return msg, metadata, err
+}
+
+func local_request_DispenseService_GetSandboxDiff_0(ctx context.Context, marshaler runtime.Marshaler, server DispenseServiceServer, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq GetSandboxDiffRequest
+		metadata runtime.ServerMetadata
+		err      error
+	)
+	val, ok := pathParams["identifier"]
+	if !ok {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "identifier")
+	}
+	protoReq.Identifier, err = runtime.String(val)
+	if err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "identifier", err)
+	}
+	if err := req.ParseForm(); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_DispenseService_GetSandboxDiff_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	msg, err := server.GetSandboxDiff(ctx, &protoReq)
+	return msg, metadata, err
+}
+
 func request_DispenseService_RunClaudeTask_0(ctx context.Context, marshaler runtime.Marshaler, client DispenseServiceClient, req *http.Request, pathParams map[string]string) (DispenseService_RunClaudeTaskClient, runtime.ServerMetadata, error) {
 	var (
 		protoReq RunClaudeTaskRequest
@@ -349,6 +402,61 @@ func local_request_DispenseService_GetClaudeLogs_0(ctx context.Context, marshale
 	return msg, metadata, err
 }
 
+func request_DispenseService_CancelClaudeTask_0(ctx context.Context, marshaler runtime.Marshaler, client DispenseServiceClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq CancelClaudeTaskRequest
+		metadata runtime.ServerMetadata
+		err      error
+	)
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+	}
+	val, ok := pathParams["sandbox_identifier"]
+	if !ok {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "sandbox_identifier")
+	}
+	protoReq.SandboxIdentifier, err = runtime.String(val)
+	if err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "sandbox_identifier", err)
+	}
+	val, ok = pathParams["task_id"]
+	if !ok {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "task_id")
+	}
+	protoReq.TaskId, err = runtime.String(val)
+	if err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "task_id", err)
+	}
+	msg, err := client.CancelClaudeTask(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	return msg, metadata, err
+}
+
+func local_request_DispenseService_CancelClaudeTask_0(ctx context.Context, marshaler runtime.Marshaler, server DispenseServiceServer, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq CancelClaudeTaskRequest
+		metadata runtime.ServerMetadata
+		err      error
+	)
+	val, ok := pathParams["sandbox_identifier"]
+	if !ok {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "sandbox_identifier")
+	}
+	protoReq.SandboxIdentifier, err = runtime.String(val)
+	if err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "sandbox_identifier", err)
+	}
+	val, ok = pathParams["task_id"]
+	if !ok {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "task_id")
+	}
+	protoReq.TaskId, err = runtime.String(val)
+	if err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "task_id", err)
+	}
+	msg, err := server.CancelClaudeTask(ctx, &protoReq)
+	return msg, metadata, err
+}
+
 var filter_DispenseService_GetAPIKey_0 = &utilities.DoubleArray{Encoding: map[string]int{}, Base: []int(nil), Check: []int(nil)}
 
 func request_DispenseService_GetAPIKey_0(ctx context.Context, marshaler runtime.Marshaler, client DispenseServiceClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
@@ -544,6 +652,26 @@ func RegisterDispenseServiceHandlerServer(ctx context.Context, mux *runtime.Serv
 		}
 		forward_DispenseService_WaitForSandbox_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodGet, pattern_DispenseService_GetSandboxDiff_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var stream runtime.ServerTransportStream
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &stream)
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateIncomingContext(ctx, mux, req, "/dispense.DispenseService/GetSandboxDiff", runtime.WithHTTPPathPattern("/v1/sandboxes/{identifier}/diff"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := local_request_DispenseService_GetSandboxDiff_0(annotatedContext, inboundMarshaler, server, req, pathParams)
+		md.HeaderMD, md.TrailerMD = metadata.Join(md.HeaderMD, stream.Header()), metadata.Join(md.TrailerMD, stream.Trailer())
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_DispenseService_GetSandboxDiff_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
 
 	mux.Handle(http.MethodPost, pattern_DispenseService_RunClaudeTask_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
@@ -591,6 +719,26 @@ func RegisterDispenseServiceHandlerServer(ctx context.Context, mux *runtime.Serv
 		}
 		forward_DispenseService_GetClaudeLogs_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodDelete, pattern_DispenseService_CancelClaudeTask_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var stream runtime.ServerTransportStream
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &stream)
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateIncomingContext(ctx, mux, req, "/dispense.DispenseService/CancelClaudeTask", runtime.WithHTTPPathPattern("/v1/claude/{sandbox_identifier}/tasks/{task_id}"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := local_request_DispenseService_CancelClaudeTask_0(annotatedContext, inboundMarshaler, server, req, pathParams)
+		md.HeaderMD, md.TrailerMD = metadata.Join(md.HeaderMD, stream.Header()), metadata.Join(md.TrailerMD, stream.Trailer())
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_DispenseService_CancelClaudeTask_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
 	mux.Handle(http.MethodGet, pattern_DispenseService_GetAPIKey_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
@@ -776,6 +924,23 @@ func RegisterDispenseServiceHandlerClient(ctx context.Context, mux *runtime.Serv
 		}
 		forward_DispenseService_WaitForSandbox_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodGet, pattern_DispenseService_GetSandboxDiff_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/dispense.DispenseService/GetSandboxDiff", runtime.WithHTTPPathPattern("/v1/sandboxes/{identifier}/diff"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := request_DispenseService_GetSandboxDiff_0(annotatedContext, inboundMarshaler, client, req, pathParams)
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_DispenseService_GetSandboxDiff_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
 	mux.Handle(http.MethodPost, pattern_DispenseService_RunClaudeTask_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
@@ -827,6 +992,23 @@ func RegisterDispenseServiceHandlerClient(ctx context.Context, mux *runtime.Serv
 		}
 		forward_DispenseService_GetClaudeLogs_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodDelete, pattern_DispenseService_CancelClaudeTask_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/dispense.DispenseService/CancelClaudeTask", runtime.WithHTTPPathPattern("/v1/claude/{sandbox_identifier}/tasks/{task_id}"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := request_DispenseService_CancelClaudeTask_0(annotatedContext, inboundMarshaler, client, req, pathParams)
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_DispenseService_CancelClaudeTask_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
 	mux.Handle(http.MethodGet, pattern_DispenseService_GetAPIKey_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
@@ -882,29 +1064,33 @@ func RegisterDispenseServiceHandlerClient(ctx context.Context, mux *runtime.Serv
 }
 
 var (
-	pattern_DispenseService_CreateSandbox_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "sandboxes"}, ""))
-	pattern_DispenseService_ListSandboxes_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "sandboxes"}, ""))
-	pattern_DispenseService_DeleteSandbox_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2}, []string{"v1", "sandboxes", "identifier"}, ""))
-	pattern_DispenseService_GetSandbox_0      = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2}, []string{"v1", "sandboxes", "identifier"}, ""))
-	pattern_DispenseService_WaitForSandbox_0  = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "sandboxes", "identifier", "wait"}, ""))
-	pattern_DispenseService_RunClaudeTask_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v1", "claude", "tasks"}, ""))
-	pattern_DispenseService_GetClaudeStatus_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "claude", "sandbox_identifier", "status"}, ""))
-	pattern_DispenseService_GetClaudeLogs_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "claude", "sandbox_identifier", "logs"}, ""))
-	pattern_DispenseService_GetAPIKey_0       = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v1", "config", "api-key"}, ""))
-	pattern_DispenseService_SetAPIKey_0       = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v1", "config", "api-key"}, ""))
-	pattern_DispenseService_ValidateAPIKey_0  = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2, 2, 3}, []string{"v1", "config", "api-key", "validate"}, ""))
+	pattern_DispenseService_CreateSandbox_0    = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "sandboxes"}, ""))
+	pattern_DispenseService_ListSandboxes_0    = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "sandboxes"}, ""))
+	pattern_DispenseService_DeleteSandbox_0    = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2}, []string{"v1", "sandboxes", "identifier"}, ""))
+	pattern_DispenseService_GetSandbox_0       = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2}, []string{"v1", "sandboxes", "identifier"}, ""))
+	pattern_DispenseService_WaitForSandbox_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "sandboxes", "identifier", "wait"}, ""))
+	pattern_DispenseService_GetSandboxDiff_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "sandboxes", "identifier", "diff"}, ""))
+	pattern_DispenseService_RunClaudeTask_0    = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v1", "claude", "tasks"}, ""))
+	pattern_DispenseService_GetClaudeStatus_0  = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "claude", "sandbox_identifier", "status"}, ""))
+	pattern_DispenseService_GetClaudeLogs_0    = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3}, []string{"v1", "claude", "sandbox_identifier", "logs"}, ""))
+	pattern_DispenseService_CancelClaudeTask_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 1, 0, 4, 1, 5, 2, 2, 3, 1, 0, 4, 1, 5, 4}, []string{"v1", "claude", "sandbox_identifier", "tasks", "task_id"}, ""))
+	pattern_DispenseService_GetAPIKey_0        = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v1", "config", "api-key"}, ""))
+	pattern_DispenseService_SetAPIKey_0        = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v1", "config", "api-key"}, ""))
+	pattern_DispenseService_ValidateAPIKey_0   = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2, 2, 3}, []string{"v1", "config", "api-key", "validate"}, ""))
 )
 
 var (
-	forward_DispenseService_CreateSandbox_0   = runtime.ForwardResponseMessage
-	forward_DispenseService_ListSandboxes_0   = runtime.ForwardResponseMessage
-	forward_DispenseService_DeleteSandbox_0   = runtime.ForwardResponseMessage
-	forward_DispenseService_GetSandbox_0      = runtime.ForwardResponseMessage
-	forward_DispenseService_WaitForSandbox_0  = runtime.ForwardResponseMessage
-	forward_DispenseService_RunClaudeTask_0   = runtime.ForwardResponseStream
-	forward_DispenseService_GetClaudeStatus_0 = runtime.ForwardResponseMessage
-	forward_DispenseService_GetClaudeLogs_0   = runtime.ForwardResponseMessage
-	forward_DispenseService_GetAPIKey_0       = runtime.ForwardResponseMessage
-	forward_DispenseService_SetAPIKey_0       = runtime.ForwardResponseMessage
-	forward_DispenseService_ValidateAPIKey_0  = runtime.ForwardResponseMessage
+	forward_DispenseService_CreateSandbox_0    = runtime.ForwardResponseMessage
+	forward_DispenseService_ListSandboxes_0    = runtime.ForwardResponseMessage
+	forward_DispenseService_DeleteSandbox_0    = runtime.ForwardResponseMessage
+	forward_DispenseService_GetSandbox_0       = runtime.ForwardResponseMessage
+	forward_DispenseService_WaitForSandbox_0   = runtime.ForwardResponseMessage
+	forward_DispenseService_GetSandboxDiff_0   = runtime.ForwardResponseMessage
+	forward_DispenseService_RunClaudeTask_0    = runtime.ForwardResponseStream
+	forward_DispenseService_GetClaudeStatus_0  = runtime.ForwardResponseMessage
+	forward_DispenseService_GetClaudeLogs_0    = runtime.ForwardResponseMessage
+	forward_DispenseService_CancelClaudeTask_0 = runtime.ForwardResponseMessage
+	forward_DispenseService_GetAPIKey_0        = runtime.ForwardResponseMessage
+	forward_DispenseService_SetAPIKey_0        = runtime.ForwardResponseMessage
+	forward_DispenseService_ValidateAPIKey_0   = runtime.ForwardResponseMessage
 )
@@ -19,17 +19,19 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DispenseService_CreateSandbox_FullMethodName   = "/dispense.DispenseService/CreateSandbox"
-	DispenseService_ListSandboxes_FullMethodName   = "/dispense.DispenseService/ListSandboxes"
-	DispenseService_DeleteSandbox_FullMethodName   = "/dispense.DispenseService/DeleteSandbox"
-	DispenseService_GetSandbox_FullMethodName      = "/dispense.DispenseService/GetSandbox"
-	DispenseService_WaitForSandbox_FullMethodName  = "/dispense.DispenseService/WaitForSandbox"
-	DispenseService_RunClaudeTask_FullMethodName   = "/dispense.DispenseService/RunClaudeTask"
-	DispenseService_GetClaudeStatus_FullMethodName = "/dispense.DispenseService/GetClaudeStatus"
-	DispenseService_GetClaudeLogs_FullMethodName   = "/dispense.DispenseService/GetClaudeLogs"
-	DispenseService_GetAPIKey_FullMethodName       = "/dispense.DispenseService/GetAPIKey"
-	DispenseService_SetAPIKey_FullMethodName       = "/dispense.DispenseService/SetAPIKey"
-	DispenseService_ValidateAPIKey_FullMethodName  = "/dispense.DispenseService/ValidateAPIKey"
+	DispenseService_CreateSandbox_FullMethodName    = "/dispense.DispenseService/CreateSandbox"
+	DispenseService_ListSandboxes_FullMethodName    = "/dispense.DispenseService/ListSandboxes"
+	DispenseService_DeleteSandbox_FullMethodName    = "/dispense.DispenseService/DeleteSandbox"
+	DispenseService_GetSandbox_FullMethodName       = "/dispense.DispenseService/GetSandbox"
+	DispenseService_WaitForSandbox_FullMethodName   = "/dispense.DispenseService/WaitForSandbox"
+	DispenseService_GetSandboxDiff_FullMethodName   = "/dispense.DispenseService/GetSandboxDiff"
+	DispenseService_RunClaudeTask_FullMethodName    = "/dispense.DispenseService/RunClaudeTask"
+	DispenseService_GetClaudeStatus_FullMethodName  = "/dispense.DispenseService/GetClaudeStatus"
+	DispenseService_GetClaudeLogs_FullMethodName    = "/dispense.DispenseService/GetClaudeLogs"
+	DispenseService_CancelClaudeTask_FullMethodName = "/dispense.DispenseService/CancelClaudeTask"
+	DispenseService_GetAPIKey_FullMethodName        = "/dispense.DispenseService/GetAPIKey"
+	DispenseService_SetAPIKey_FullMethodName        = "/dispense.DispenseService/SetAPIKey"
+	DispenseService_ValidateAPIKey_FullMethodName   = "/dispense.DispenseService/ValidateAPIKey"
 )
 
 // DispenseServiceClient is the client API for DispenseService service.
@@ -44,10 +46,12 @@ type DispenseServiceClient interface {
 	DeleteSandbox(ctx context.Context, in *DeleteSandboxRequest, opts ...grpc.CallOption) (*DeleteSandboxResponse, error)
 	GetSandbox(ctx context.Context, in *GetSandboxRequest, opts ...grpc.CallOption) (*GetSandboxResponse, error)
 	WaitForSandbox(ctx context.Context, in *WaitForSandboxRequest, opts ...grpc.CallOption) (*WaitForSandboxResponse, error)
+	GetSandboxDiff(ctx context.Context, in *GetSandboxDiffRequest, opts ...grpc.CallOption) (*GetSandboxDiffResponse, error)
 	// Claude operations
 	RunClaudeTask(ctx context.Context, in *RunClaudeTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunClaudeTaskResponse], error)
 	GetClaudeStatus(ctx context.Context, in *GetClaudeStatusRequest, opts ...grpc.CallOption) (*GetClaudeStatusResponse, error)
 	GetClaudeLogs(ctx context.Context, in *GetClaudeLogsRequest, opts ...grpc.CallOption) (*GetClaudeLogsResponse, error)
+	CancelClaudeTask(ctx context.Context, in *CancelClaudeTaskRequest, opts ...grpc.CallOption) (*CancelClaudeTaskResponse, error)
 	// Configuration management
 	GetAPIKey(ctx context.Context, in *GetAPIKeyRequest, opts ...grpc.CallOption) (*GetAPIKeyResponse, error)
 	SetAPIKey(ctx context.Context, in *SetAPIKeyRequest, opts ...grpc.CallOption) (*SetAPIKeyResponse, error)
@@ -112,6 +116,16 @@ func (c *dispenseServiceClient) WaitForSandbox(ctx context.Context, in *WaitForS
 	return out, nil
 }
 
+func (c *dispenseServiceClient) GetSandboxDiff(ctx context.Context, in *GetSandboxDiffRequest, opts ...grpc.CallOption) (*GetSandboxDiffResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSandboxDiffResponse)
+	err := c.cc.Invoke(ctx, DispenseService_GetSandboxDiff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *dispenseServiceClient) RunClaudeTask(ctx context.Context, in *RunClaudeTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunClaudeTaskResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &DispenseService_ServiceDesc.Streams[0], DispenseService_RunClaudeTask_FullMethodName, cOpts...)
@@ -151,6 +165,16 @@ func (c *dispenseServiceClient) GetClaudeLogs(ctx context.Context, in *GetClaude
 	return out, nil
 }
 
+func (c *dispenseServiceClient) CancelClaudeTask(ctx context.Context, in *CancelClaudeTaskRequest, opts ...grpc.CallOption) (*CancelClaudeTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelClaudeTaskResponse)
+	err := c.cc.Invoke(ctx, DispenseService_CancelClaudeTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *dispenseServiceClient) GetAPIKey(ctx context.Context, in *GetAPIKeyRequest, opts ...grpc.CallOption) (*GetAPIKeyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAPIKeyResponse)
@@ -193,10 +217,12 @@ type DispenseServiceServer interface {
 	DeleteSandbox(context.Context, *DeleteSandboxRequest) (*DeleteSandboxResponse, error)
 	GetSandbox(context.Context, *GetSandboxRequest) (*GetSandboxResponse, error)
 	WaitForSandbox(context.Context, *WaitForSandboxRequest) (*WaitForSandboxResponse, error)
+	GetSandboxDiff(context.Context, *GetSandboxDiffRequest) (*GetSandboxDiffResponse, error)
 	// Claude operations
 	RunClaudeTask(*RunClaudeTaskRequest, grpc.ServerStreamingServer[RunClaudeTaskResponse]) error
 	GetClaudeStatus(context.Context, *GetClaudeStatusRequest) (*GetClaudeStatusResponse, error)
 	GetClaudeLogs(context.Context, *GetClaudeLogsRequest) (*GetClaudeLogsResponse, error)
+	CancelClaudeTask(context.Context, *CancelClaudeTaskRequest) (*CancelClaudeTaskResponse, error)
 	// Configuration management
 	GetAPIKey(context.Context, *GetAPIKeyRequest) (*GetAPIKeyResponse, error)
 	SetAPIKey(context.Context, *SetAPIKeyRequest) (*SetAPIKeyResponse, error)
@@ -226,6 +252,9 @@ func (UnimplementedDispenseServiceServer) GetSandbox(context.Context, *GetSandbo
 func (UnimplementedDispenseServiceServer) WaitForSandbox(context.Context, *WaitForSandboxRequest) (*WaitForSandboxResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WaitForSandbox not implemented")
 }
+func (UnimplementedDispenseServiceServer) GetSandboxDiff(context.Context, *GetSandboxDiffRequest) (*GetSandboxDiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSandboxDiff not implemented")
+}
 func (UnimplementedDispenseServiceServer) RunClaudeTask(*RunClaudeTaskRequest, grpc.ServerStreamingServer[RunClaudeTaskResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method RunClaudeTask not implemented")
 }
@@ -235,6 +264,9 @@ func (UnimplementedDispenseServiceServer) GetClaudeStatus(context.Context, *GetC
 func (UnimplementedDispenseServiceServer) GetClaudeLogs(context.Context, *GetClaudeLogsRequest) (*GetClaudeLogsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetClaudeLogs not implemented")
 }
+func (UnimplementedDispenseServiceServer) CancelClaudeTask(context.Context, *CancelClaudeTaskRequest) (*CancelClaudeTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelClaudeTask not implemented")
+}
 func (UnimplementedDispenseServiceServer) GetAPIKey(context.Context, *GetAPIKeyRequest) (*GetAPIKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAPIKey not implemented")
 }
@@ -355,6 +387,24 @@ func _DispenseService_WaitForSandbox_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DispenseService_GetSandboxDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSandboxDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DispenseServiceServer).GetSandboxDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DispenseService_GetSandboxDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DispenseServiceServer).GetSandboxDiff(ctx, req.(*GetSandboxDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DispenseService_RunClaudeTask_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(RunClaudeTaskRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -402,6 +452,24 @@ func _DispenseService_GetClaudeLogs_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DispenseService_CancelClaudeTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelClaudeTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DispenseServiceServer).CancelClaudeTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DispenseService_CancelClaudeTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DispenseServiceServer).CancelClaudeTask(ctx, req.(*CancelClaudeTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DispenseService_GetAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAPIKeyRequest)
 	if err := dec(in); err != nil {
@@ -483,6 +551,10 @@ var DispenseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WaitForSandbox",
 			Handler:    _DispenseService_WaitForSandbox_Handler,
 		},
+		{
+			MethodName: "GetSandboxDiff",
+			Handler:    _DispenseService_GetSandboxDiff_Handler,
+		},
 		{
 			MethodName: "GetClaudeStatus",
 			Handler:    _DispenseService_GetClaudeStatus_Handler,
@@ -491,6 +563,10 @@ var DispenseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetClaudeLogs",
 			Handler:    _DispenseService_GetClaudeLogs_Handler,
 		},
+		{
+			MethodName: "CancelClaudeTask",
+			Handler:    _DispenseService_CancelClaudeTask_Handler,
+		},
 		{
 			MethodName: "GetAPIKey",
 			Handler:    _DispenseService_GetAPIKey_Handler,
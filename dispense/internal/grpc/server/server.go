@@ -11,6 +11,7 @@ import (
 	"cli/internal/services"
 	"cli/internal/core/models"
 	"cli/internal/core/errors"
+	"cli/pkg/utils"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -26,7 +27,7 @@ type DispenseServer struct {
 func NewDispenseServer(serviceContainer *services.ServiceContainer) *DispenseServer {
 	return &DispenseServer{
 		ServiceContainer: serviceContainer,
-		Logger:           log.New(os.Stdout, "[grpc-server] ", log.LstdFlags),
+		Logger:           log.New(utils.NewRedactingWriter(os.Stdout), "[grpc-server] ", log.LstdFlags),
 	}
 }
 
@@ -161,19 +162,68 @@ func (s *DispenseServer) WaitForSandbox(ctx context.Context, req *pb.WaitForSand
 	}, nil
 }
 
+// GetSandboxDiff gets the changes in a sandbox's workspace
+func (s *DispenseServer) GetSandboxDiff(ctx context.Context, req *pb.GetSandboxDiffRequest) (*pb.GetSandboxDiffResponse, error) {
+	s.Logger.Printf("GetSandboxDiff called for: %s (base %q)", req.Identifier, req.BaseRef)
+
+	// Convert to internal model
+	diffReq := &models.WorkspaceDiffRequest{
+		SandboxIdentifier: req.Identifier,
+		BaseRef:           req.BaseRef,
+		IncludePatches:    req.IncludePatches,
+	}
+
+	// Call service
+	diff, err := s.ServiceContainer.ClaudeService.GetWorkspaceDiff(diffReq)
+	if err != nil {
+		s.Logger.Printf("Failed to get sandbox diff: %v", err)
+		return &pb.GetSandboxDiffResponse{
+			Error: s.convertError(err),
+		}, nil
+	}
+
+	resp := &pb.GetSandboxDiffResponse{
+		BaseCommit: diff.BaseCommit,
+		HeadCommit: diff.HeadCommit,
+		Branch:     diff.Branch,
+	}
+	for _, file := range diff.Files {
+		resp.Files = append(resp.Files, &pb.SandboxFileDiff{
+			Path:           file.Path,
+			OldPath:        file.OldPath,
+			Status:         file.Status,
+			Additions:      int32(file.Additions),
+			Deletions:      int32(file.Deletions),
+			Binary:         file.Binary,
+			Patch:          file.Patch,
+			PatchTruncated: file.PatchTruncated,
+		})
+	}
+
+	return resp, nil
+}
+
 // RunClaudeTask runs a Claude task with streaming response
 func (s *DispenseServer) RunClaudeTask(req *pb.RunClaudeTaskRequest, stream pb.DispenseService_RunClaudeTaskServer) error {
 	s.Logger.Printf("RunClaudeTask called for sandbox: %s", req.SandboxIdentifier)
 
 	// Convert to internal model
 	claudeReq := &models.ClaudeTaskRequest{
-		SandboxIdentifier: req.SandboxIdentifier,
-		TaskDescription:   req.TaskDescription,
-		Model:             req.Model,
-	}
-
-	// Call service (this would need to be modified to support streaming)
-	response, err := s.ServiceContainer.ClaudeService.RunTask(claudeReq)
+		SandboxIdentifier:  req.SandboxIdentifier,
+		TaskDescription:    req.TaskDescription,
+		Model:              req.Model,
+		ResumeTaskID:       req.ResumeTaskId,
+		ContinueSession:    req.ContinueSession,
+		TimeoutSeconds:     req.TimeoutSeconds,
+		IdleTimeoutSeconds: req.IdleTimeoutSeconds,
+		VerifyCommands:     req.VerifyCommands,
+		MaxFixAttempts:     req.MaxFixAttempts,
+	}
+
+	// Forward each task event to the client as it arrives
+	err := s.ServiceContainer.ClaudeService.StreamTask(claudeReq, func(event *models.ClaudeTaskEvent) error {
+		return stream.Send(convertTaskEvent(event))
+	})
 	if err != nil {
 		// Send error response
 		return stream.Send(&pb.RunClaudeTaskResponse{
@@ -184,18 +234,7 @@ func (s *DispenseServer) RunClaudeTask(req *pb.RunClaudeTaskRequest, stream pb.D
 		})
 	}
 
-	// Send success response
-	responseType := pb.RunClaudeTaskResponse_STDOUT
-	if !response.Success {
-		responseType = pb.RunClaudeTaskResponse_ERROR
-	}
-
-	return stream.Send(&pb.RunClaudeTaskResponse{
-		Type:       responseType,
-		Content:    response.Output,
-		Timestamp:  time.Now().Unix(),
-		IsFinished: true,
-	})
+	return nil
 }
 
 // GetClaudeStatus gets Claude daemon status
@@ -248,6 +287,33 @@ func (s *DispenseServer) GetClaudeLogs(ctx context.Context, req *pb.GetClaudeLog
 	}, nil
 }
 
+// CancelClaudeTask cancels a running Claude task
+func (s *DispenseServer) CancelClaudeTask(ctx context.Context, req *pb.CancelClaudeTaskRequest) (*pb.CancelClaudeTaskResponse, error) {
+	s.Logger.Printf("CancelClaudeTask called for: %s (task %s)", req.SandboxIdentifier, req.TaskId)
+
+	// Convert to internal model
+	cancelReq := &models.ClaudeCancelRequest{
+		SandboxIdentifier: req.SandboxIdentifier,
+		TaskID:            req.TaskId,
+	}
+
+	// Call service
+	cancelResp, err := s.ServiceContainer.ClaudeService.CancelTask(cancelReq)
+	if err != nil {
+		s.Logger.Printf("Failed to cancel Claude task: %v", err)
+		return &pb.CancelClaudeTaskResponse{
+			Success: false,
+			Error:   s.convertError(err),
+		}, nil
+	}
+
+	return &pb.CancelClaudeTaskResponse{
+		Success: cancelResp.Success,
+		Message: cancelResp.Message,
+		State:   cancelResp.State,
+	}, nil
+}
+
 // GetAPIKey gets the API key
 func (s *DispenseServer) GetAPIKey(ctx context.Context, req *pb.GetAPIKeyRequest) (*pb.GetAPIKeyResponse, error) {
 	s.Logger.Printf("GetAPIKey called")
@@ -431,4 +497,29 @@ func (s *DispenseServer) convertError(err error) *pb.ErrorResponse {
 		Message: err.Error(),
 		Details: make(map[string]string),
 	}
-}
\ No newline at end of file
+}
+
+// convertTaskEvent converts a task event into a RunClaudeTask stream response
+func convertTaskEvent(event *models.ClaudeTaskEvent) *pb.RunClaudeTaskResponse {
+	resp := &pb.RunClaudeTaskResponse{
+		Type:       pb.RunClaudeTaskResponse_ResponseType(pb.RunClaudeTaskResponse_ResponseType_value[event.Type]),
+		Content:    event.Content,
+		Timestamp:  event.Timestamp,
+		ExitCode:   event.ExitCode,
+		IsFinished: event.IsFinished,
+		SessionId:  event.SessionID,
+		ToolName:   event.ToolName,
+	}
+
+	if event.Usage != nil {
+		resp.Usage = &pb.ClaudeUsage{
+			InputTokens:              event.Usage.InputTokens,
+			OutputTokens:             event.Usage.OutputTokens,
+			CacheCreationInputTokens: event.Usage.CacheCreationInputTokens,
+			CacheReadInputTokens:     event.Usage.CacheReadInputTokens,
+			TotalCostUsd:             event.Usage.TotalCostUSD,
+		}
+	}
+
+	return resp
+}
@@ -10,13 +10,18 @@ import (
 
 	"cli/internal/core/errors"
 	"cli/internal/core/models"
+	"cli/pkg/daemon"
+	"cli/pkg/database"
+	"cli/pkg/project"
 	"cli/pkg/sandbox"
 	"cli/pkg/sandbox/local"
 	"cli/pkg/sandbox/remote"
 	pb "cli/proto"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 // ClaudeService handles Claude integration and daemon communication
@@ -31,24 +36,65 @@ func NewClaudeService(sandboxService *SandboxService) *ClaudeService {
 	}
 }
 
-// RunTask executes a task using Claude in the specified sandbox
+// RunTask executes a task using Claude in the specified sandbox and collects its output
 func (s *ClaudeService) RunTask(req *models.ClaudeTaskRequest) (*models.ClaudeTaskResponse, error) {
+	var output, errorMsg string
+	success := true
+
+	err := s.StreamTask(req, func(event *models.ClaudeTaskEvent) error {
+		switch event.Type {
+		case pb.ExecuteClaudeResponse_STDOUT.String(), pb.ExecuteClaudeResponse_ASSISTANT.String(), pb.ExecuteClaudeResponse_VERIFICATION.String():
+			output += event.Content + "\n"
+		case pb.ExecuteClaudeResponse_STDERR.String():
+			errorMsg += event.Content + "\n"
+		case pb.ExecuteClaudeResponse_ERROR.String():
+			success = false
+			errorMsg += event.Content
+		case pb.ExecuteClaudeResponse_STATUS.String():
+			if event.IsFinished && req.MaxFixAttempts > 0 {
+				// Every fix attempt finishes with a status of its own, the last one decides
+				success = event.ExitCode == 0
+			} else if event.ExitCode != 0 {
+				success = false
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Failures to reach the daemon are errors, a broken output stream fails the task
+		if _, ok := err.(*errors.DispenseError); ok {
+			return nil, err
+		}
+		success = false
+		errorMsg = err.Error()
+	}
+
+	return &models.ClaudeTaskResponse{
+		Success:  success,
+		Output:   output,
+		ErrorMsg: errorMsg,
+	}, nil
+}
+
+// StreamTask executes a task using Claude in the specified sandbox and passes
+// each output event to onEvent as it arrives
+func (s *ClaudeService) StreamTask(req *models.ClaudeTaskRequest, onEvent func(*models.ClaudeTaskEvent) error) error {
 	// Find the sandbox
 	sandboxInfo, err := s.sandboxService.FindByName(req.SandboxIdentifier)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Get daemon connection info
 	daemonAddr, err := s.getDaemonAddress(sandboxInfo)
 	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to get daemon address")
+		return errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to get daemon address")
 	}
 
 	// Connect to daemon
-	conn, err := s.connectToDaemon(daemonAddr)
+	conn, err := s.connectToDaemon(sandboxInfo, daemonAddr)
 	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to connect to daemon")
+		return errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to connect to daemon")
 	}
 	defer conn.Close()
 
@@ -59,49 +105,96 @@ func (s *ClaudeService) RunTask(req *models.ClaudeTaskRequest) (*models.ClaudeTa
 	defer cancel()
 
 	grpcReq := &pb.ExecuteClaudeRequest{
-		Prompt: req.TaskDescription,
+		Prompt:             req.TaskDescription,
+		Model:              req.Model,
+		ResumeTaskId:       req.ResumeTaskID,
+		ContinueSession:    req.ContinueSession,
+		TimeoutSeconds:     req.TimeoutSeconds,
+		IdleTimeoutSeconds: req.IdleTimeoutSeconds,
+		VerifyCommands:     req.VerifyCommands,
+		MaxFixAttempts:     req.MaxFixAttempts,
 	}
 
 	stream, err := client.ExecuteClaude(ctx, grpcReq)
 	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrCodeSystemUnavailable, "failed to execute task")
+		return errors.Wrap(err, errors.ErrCodeSystemUnavailable, "failed to execute task")
 	}
 
-	// Collect all streaming responses
-	var output, errorMsg string
-	success := true
+	taskID, err := forwardTaskEvents(stream, onEvent)
+	if err != nil || taskID == "" || req.MaxFixAttempts <= 0 {
+		return err
+	}
+
+	// Fix attempts are tasks of their own, follow them one after the other
+	for followed := 0; ; followed++ {
+		parent, err := client.GetTaskStatus(ctx, &pb.TaskStatusRequest{TaskId: taskID})
+		if err != nil {
+			return fmt.Errorf("failed to get the fix attempts of task %s: %w", taskID, err)
+		}
+		if followed >= len(parent.FixTaskIds) {
+			return nil
+		}
 
+		attachStream, err := client.AttachTask(ctx, &pb.AttachTaskRequest{TaskId: parent.FixTaskIds[followed]})
+		if err != nil {
+			return fmt.Errorf("failed to attach to fix attempt %s: %w", parent.FixTaskIds[followed], err)
+		}
+		if _, err := forwardTaskEvents(attachStream, onEvent); err != nil {
+			return err
+		}
+	}
+}
+
+// forwardTaskEvents passes the events of a daemon output stream to onEvent
+// until the stream ends and returns the ID of the task they belong to
+func forwardTaskEvents(stream pb.AgentService_ExecuteClaudeClient, onEvent func(*models.ClaudeTaskEvent) error) (string, error) {
+	var taskID string
 	for {
 		resp, err := stream.Recv()
 		if err != nil {
 			if err == io.EOF {
-				break
+				return taskID, nil
 			}
-			success = false
-			errorMsg = err.Error()
-			break
+			return taskID, fmt.Errorf("task output stream failed: %w", err)
 		}
 
-		switch resp.Type {
-		case pb.ExecuteClaudeResponse_STDOUT:
-			output += resp.Content
-		case pb.ExecuteClaudeResponse_STDERR:
-			errorMsg += resp.Content
-		case pb.ExecuteClaudeResponse_ERROR:
-			success = false
-			errorMsg += resp.Content
-		case pb.ExecuteClaudeResponse_STATUS:
-			if resp.ExitCode != 0 {
-				success = false
+		if resp.TaskId != "" {
+			taskID = resp.TaskId
+		}
+
+		if err := onEvent(convertTaskEvent(resp)); err != nil {
+			return taskID, err
+		}
+	}
+}
+
+// convertTaskEvent converts a daemon response into a task event
+func convertTaskEvent(resp *pb.ExecuteClaudeResponse) *models.ClaudeTaskEvent {
+	event := &models.ClaudeTaskEvent{
+		Type:       resp.Type.String(),
+		Content:    resp.Content,
+		Timestamp:  resp.Timestamp,
+		ExitCode:   resp.ExitCode,
+		IsFinished: resp.IsFinished,
+	}
+
+	if resp.Event != nil {
+		event.SessionID = resp.Event.SessionId
+		if resp.Event.ToolUse != nil {
+			event.ToolName = resp.Event.ToolUse.Name
+		}
+		if usage := resp.Event.Usage; usage != nil {
+			event.Usage = &models.ClaudeUsage{
+				InputTokens:              usage.InputTokens,
+				OutputTokens:             usage.OutputTokens,
+				CacheCreationInputTokens: usage.CacheCreationInputTokens,
+				CacheReadInputTokens:     usage.CacheReadInputTokens,
+				TotalCostUSD:             usage.TotalCostUsd,
 			}
 		}
 	}
 
-	return &models.ClaudeTaskResponse{
-		Success:  success,
-		Output:   output,
-		ErrorMsg: errorMsg,
-	}, nil
+	return event
 }
 
 // GetStatus retrieves the status of Claude daemon in the specified sandbox
@@ -122,7 +215,7 @@ func (s *ClaudeService) GetStatus(req *models.ClaudeStatusRequest) (*models.Clau
 	}
 
 	// Try to connect to daemon
-	conn, err := s.connectToDaemon(daemonAddr)
+	conn, err := s.connectToDaemon(sandboxInfo, daemonAddr)
 	if err != nil {
 		return &models.ClaudeStatusResponse{
 			Connected: false,
@@ -137,20 +230,46 @@ func (s *ClaudeService) GetStatus(req *models.ClaudeStatusRequest) (*models.Clau
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := client.Init(ctx, &pb.InitRequest{})
-	if err != nil {
+	health, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: pb.AgentService_ServiceDesc.ServiceName}, grpc.WaitForReady(true))
+	if err == nil && health.Status != healthpb.HealthCheckResponse_SERVING {
+		err = fmt.Errorf("daemon is not serving (%s)", health.Status)
+	}
+	if err != nil && status.Code(err) != codes.Unimplemented {
 		return &models.ClaudeStatusResponse{
 			Connected: false,
 			ErrorMsg:  err.Error(),
 		}, nil
 	}
 
+	var daemonInfo string
+	info, err := client.DaemonInfo(ctx, &pb.DaemonInfoRequest{})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		// Daemons older than DaemonInfo only answer to Init
+		resp, err := client.Init(ctx, &pb.InitRequest{})
+		if err != nil {
+			return &models.ClaudeStatusResponse{
+				Connected: false,
+				ErrorMsg:  err.Error(),
+			}, nil
+		}
+		daemonInfo = resp.Message
+	case err != nil:
+		return &models.ClaudeStatusResponse{
+			Connected: false,
+			ErrorMsg:  err.Error(),
+		}, nil
+	default:
+		daemonInfo = fmt.Sprintf("Daemon %s (commit %s), Claude CLI %s, up %s, %d running and %d queued tasks",
+			info.Version, info.GitCommit, info.ClaudeVersion, time.Duration(info.UptimeSeconds)*time.Second, info.RunningTasks, info.QueuedTasks)
+	}
+
 	// Get working directory
 	workDir, _ := s.getWorkingDirectory(sandboxInfo)
 
 	return &models.ClaudeStatusResponse{
-		Connected:  resp.Success,
-		DaemonInfo: resp.Message,
+		Connected:  true,
+		DaemonInfo: daemonInfo,
 		WorkDir:    workDir,
 	}, nil
 }
@@ -179,6 +298,131 @@ func (s *ClaudeService) GetLogs(req *models.ClaudeLogsRequest) (*models.ClaudeLo
 	}, nil
 }
 
+// CancelTask stops a running Claude task in the specified sandbox
+func (s *ClaudeService) CancelTask(req *models.ClaudeCancelRequest) (*models.ClaudeCancelResponse, error) {
+	// Find the sandbox
+	sandboxInfo, err := s.sandboxService.FindByName(req.SandboxIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get daemon connection info
+	daemonAddr, err := s.getDaemonAddress(sandboxInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to get daemon address")
+	}
+
+	// Connect to daemon
+	conn, err := s.connectToDaemon(sandboxInfo, daemonAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to connect to daemon")
+	}
+	defer conn.Close()
+
+	client := pb.NewAgentServiceClient(conn)
+
+	// The daemon escalates through SIGINT, SIGTERM and SIGKILL before giving up
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	resp, err := client.CancelTask(ctx, &pb.CancelTaskRequest{TaskId: req.TaskID})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeSystemUnavailable, "failed to cancel task")
+	}
+
+	cancelResp := &models.ClaudeCancelResponse{
+		Success: resp.Success,
+		Message: resp.Message,
+		State:   resp.State.String(),
+	}
+	if !resp.Success {
+		cancelResp.ErrorMsg = resp.Message
+	}
+
+	return cancelResp, nil
+}
+
+// GetWorkspaceDiff gets the changes in the workspace of the specified sandbox since the commit it started from
+func (s *ClaudeService) GetWorkspaceDiff(req *models.WorkspaceDiffRequest) (*models.WorkspaceDiffResponse, error) {
+	// Find the sandbox
+	sandboxInfo, err := s.sandboxService.FindByName(req.SandboxIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// Git cannot open a local sandbox's worktree inside the container, so it is diffed on the host
+	if sandboxInfo.Type == models.TypeLocal {
+		if projectPath, ok := sandboxInfo.Metadata["project_path"].(string); ok && project.IsGitWorktree(projectPath) {
+			baseRef := req.BaseRef
+			if baseRef == "" {
+				baseRef, _ = sandboxInfo.Metadata["base_commit"].(string)
+			}
+			diff, err := project.WorkspaceDiff(context.Background(), projectPath, baseRef, req.IncludePatches)
+			if err != nil {
+				return nil, errors.Wrap(err, errors.ErrCodeGitOperationFailed, "failed to diff workspace")
+			}
+			return convertWorkspaceDiff(diff), nil
+		}
+	}
+
+	// Get daemon connection info
+	daemonAddr, err := s.getDaemonAddress(sandboxInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to get daemon address")
+	}
+
+	// Connect to daemon
+	conn, err := s.connectToDaemon(sandboxInfo, daemonAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeDaemonUnavailable, "failed to connect to daemon")
+	}
+	defer conn.Close()
+
+	workDir, err := s.getWorkingDirectory(sandboxInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	client := pb.NewAgentServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	diff, err := client.GetWorkspaceDiff(ctx, &pb.WorkspaceDiffRequest{
+		WorkingDirectory: workDir,
+		BaseRef:          req.BaseRef,
+		IncludePatches:   req.IncludePatches,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeGitOperationFailed, "failed to diff workspace")
+	}
+
+	return convertWorkspaceDiff(diff), nil
+}
+
+// convertWorkspaceDiff converts the daemon's workspace diff to the internal model
+func convertWorkspaceDiff(diff *pb.WorkspaceDiffResponse) *models.WorkspaceDiffResponse {
+	result := &models.WorkspaceDiffResponse{
+		BaseCommit: diff.BaseCommit,
+		HeadCommit: diff.HeadCommit,
+		Branch:     diff.Branch,
+		Files:      []*models.FileDiff{},
+	}
+	for _, file := range diff.Files {
+		result.Files = append(result.Files, &models.FileDiff{
+			Path:           file.Path,
+			OldPath:        file.OldPath,
+			Status:         strings.ToLower(file.Status.String()),
+			Additions:      int(file.Additions),
+			Deletions:      int(file.Deletions),
+			Binary:         file.Binary,
+			Patch:          file.Patch,
+			PatchTruncated: file.PatchTruncated,
+		})
+	}
+	return result
+}
+
 // getDaemonAddress gets the daemon address for the sandbox
 func (s *ClaudeService) getDaemonAddress(sandboxInfo *models.SandboxInfo) (string, error) {
 	// This logic would determine the daemon address based on sandbox type
@@ -201,15 +445,29 @@ func (s *ClaudeService) getDaemonAddress(sandboxInfo *models.SandboxInfo) (strin
 	return "", errors.New(errors.ErrCodeDaemonUnavailable, "daemon address not found in sandbox metadata")
 }
 
-// connectToDaemon establishes a connection to the daemon
-func (s *ClaudeService) connectToDaemon(address string) (*grpc.ClientConn, error) {
+// connectToDaemon establishes a connection to the daemon, authenticated with
+// the credentials generated when the sandbox was created
+func (s *ClaudeService) connectToDaemon(sandboxInfo *models.SandboxInfo, address string) (*grpc.ClientConn, error) {
+	dialOpts := daemon.InsecureDialOptions()
+
+	db, err := database.NewSandboxDB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sandbox database: %w", err)
+	}
+	creds, err := db.GetDaemonCredentials(sandboxInfo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load daemon credentials: %w", err)
+	}
+	if creds != nil {
+		if dialOpts, err = creds.DialOptions(); err != nil {
+			return nil, err
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
+	conn, err := grpc.DialContext(ctx, address, append(dialOpts, grpc.WithBlock())...)
 	if err != nil {
 		return nil, err
 	}
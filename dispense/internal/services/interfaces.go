@@ -16,8 +16,11 @@ type SandboxServiceInterface interface {
 // ClaudeServiceInterface defines the contract for Claude operations
 type ClaudeServiceInterface interface {
 	RunTask(req *models.ClaudeTaskRequest) (*models.ClaudeTaskResponse, error)
+	StreamTask(req *models.ClaudeTaskRequest, onEvent func(*models.ClaudeTaskEvent) error) error
 	GetStatus(req *models.ClaudeStatusRequest) (*models.ClaudeStatusResponse, error)
 	GetLogs(req *models.ClaudeLogsRequest) (*models.ClaudeLogsResponse, error)
+	CancelTask(req *models.ClaudeCancelRequest) (*models.ClaudeCancelResponse, error)
+	GetWorkspaceDiff(req *models.WorkspaceDiffRequest) (*models.WorkspaceDiffResponse, error)
 }
 
 // ConfigManagerInterface defines the contract for configuration management
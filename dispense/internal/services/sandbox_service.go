@@ -8,6 +8,8 @@ import (
 
 	"cli/internal/core/errors"
 	"cli/internal/core/models"
+	"cli/pkg/database"
+	"cli/pkg/project"
 	"cli/pkg/sandbox"
 	"cli/pkg/sandbox/local"
 	"cli/pkg/sandbox/remote"
@@ -65,6 +67,20 @@ func (s *SandboxService) Create(req *models.SandboxCreateRequest) (*models.Sandb
 		Model:       req.Model,
 	}
 
+	// Settings checked in with the project fill in what the request left unset.
+	// Only the sandbox settings apply here: the setup, test and lint commands
+	// run as part of the project initialization, which needs the daemon that
+	// only `dispense new` installs
+	if req.SourceDirectory != "" {
+		projectConfig, err := project.LoadConfig(req.SourceDirectory)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfigInvalid, "failed to load project configuration")
+		}
+		if projectConfig != nil {
+			projectConfig.Apply(opts)
+		}
+	}
+
 	if req.TaskData != nil {
 		taskDataJSON, _ := json.Marshal(req.TaskData)
 		opts.TaskData = string(taskDataJSON)
@@ -327,6 +343,11 @@ func (s *SandboxService) deleteSingleSandbox(identifier string, force bool) erro
 		return errors.Wrap(err, errors.ErrCodeSandboxDeleteFailed, "failed to delete sandbox")
 	}
 
+	// The daemon credentials are useless once the sandbox is gone
+	if db, err := database.NewSandboxDB(); err == nil {
+		db.DeleteDaemonCredentials(sandboxInfo.ID)
+	}
+
 	return nil
 }
 
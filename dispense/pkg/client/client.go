@@ -286,6 +286,32 @@ func (c *Client) StartSandbox(sandboxId string) (*apiclient.Sandbox, error) {
 	return sandbox, nil
 }
 
+// GetPortPreviewURL gets the URL through which a port of a sandbox is reached
+func (c *Client) GetPortPreviewURL(sandboxId string, port int) (*apiclient.PortPreviewUrl, error) {
+	ctx := c.getAuthenticatedContext()
+
+	request := c.apiClient.SandboxAPI.GetPortPreviewUrl(ctx, sandboxId, float32(port))
+	preview, response, err := request.Execute()
+
+	if err != nil {
+		if response != nil {
+			switch response.StatusCode {
+			case http.StatusUnauthorized:
+				return nil, fmt.Errorf("authentication failed: invalid API key")
+			case http.StatusForbidden:
+				return nil, fmt.Errorf("access forbidden: insufficient permissions")
+			case http.StatusNotFound:
+				return nil, fmt.Errorf("sandbox not found: %s", sandboxId)
+			default:
+				return nil, fmt.Errorf("API returned status %d: %s", response.StatusCode, response.Status)
+			}
+		}
+		return nil, fmt.Errorf("failed to get preview URL of port %d: %w", port, err)
+	}
+
+	return preview, nil
+}
+
 // CreateSshAccess creates SSH access for a sandbox
 func (c *Client) CreateSshAccess(sandboxId string, expiresInMinutes float32) (*apiclient.SshAccessDto, error) {
 	ctx := c.getAuthenticatedContext()
@@ -358,6 +384,54 @@ func (c *Client) UploadFile(sandboxId, localPath, remotePath string) error {
 	return nil
 }
 
+// DownloadFile downloads a single file from a sandbox to localPath using the API
+func (c *Client) DownloadFile(sandboxId, remotePath, localPath string) error {
+	ctx := c.getAuthenticatedContext()
+
+	// Create the download request
+	request := c.apiClient.ToolboxAPI.DownloadFile(ctx, sandboxId)
+	request = request.Path(remotePath)
+
+	// Execute the download, the API client stores the file in a temporary file
+	file, response, err := request.Execute()
+	if err != nil {
+		if response != nil {
+			switch response.StatusCode {
+			case http.StatusUnauthorized:
+				return fmt.Errorf("authentication failed: invalid API key")
+			case http.StatusForbidden:
+				return fmt.Errorf("access forbidden: insufficient permissions")
+			case http.StatusNotFound:
+				return fmt.Errorf("file %s not found in sandbox %s", remotePath, sandboxId)
+			case http.StatusBadRequest:
+				return fmt.Errorf("bad request: %s", err.Error())
+			default:
+				return fmt.Errorf("API returned status %d: %s", response.StatusCode, response.Status)
+			}
+		}
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+	if file == nil {
+		return fmt.Errorf("failed to download file: empty response")
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	// Copy the temporary file to its destination
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read downloaded file: %w", err)
+	}
+	out, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file %s: %w", localPath, err)
+	}
+	if _, err := io.Copy(out, file); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write local file %s: %w", localPath, err)
+	}
+	return out.Close()
+}
+
 // UploadTarFile uploads a tar file to a sandbox and extracts it
 func (c *Client) UploadTarFile(sandboxId, localTarPath, remotePath string) error {
 	ctx := c.getAuthenticatedContext()
@@ -420,6 +494,12 @@ func (c *Client) UploadTarFile(sandboxId, localTarPath, remotePath string) error
 
 // RunCommand executes a command in the sandbox using the Daytona API
 func (c *Client) RunCommand(sandboxId, command string, cwd string) (*RunCommandResponse, error) {
+	return c.RunCommandWithTimeout(sandboxId, command, cwd, 0)
+}
+
+// RunCommandWithTimeout executes a command in the sandbox using the Daytona API,
+// allowing it to run for timeout instead of the API's default of 10 seconds
+func (c *Client) RunCommandWithTimeout(sandboxId, command string, cwd string, timeout time.Duration) (*RunCommandResponse, error) {
 	ctx := c.getAuthenticatedContext()
 
 	// Create the execute request
@@ -427,6 +507,10 @@ func (c *Client) RunCommand(sandboxId, command string, cwd string) (*RunCommandR
 	if cwd != "" {
 		executeReq.Cwd = &cwd
 	}
+	if timeout > 0 {
+		seconds := float32(timeout.Seconds())
+		executeReq.Timeout = &seconds
+	}
 
 	// Create the API request
 	request := c.apiClient.ToolboxAPI.ExecuteCommand(ctx, sandboxId)
@@ -2,17 +2,24 @@ package config
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 const (
 	ConfigDirName = ".dispense"
 	APIKeyFileName = "api_key"
+	GitHubTokenFileName = "github_token"
 )
 
+// ghTokenTimeout limits how long the GitHub CLI may take to print its token
+const ghTokenTimeout = 10 * time.Second
+
 // GetConfigDir returns the path to the .dispense directory in the user's home folder
 func GetConfigDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -149,3 +156,54 @@ func GetAPIKeyNonInteractive() (string, error) {
 	return LoadAPIKey()
 }
 
+
+// LoadGitHubToken loads the GitHub token from the GITHUB_TOKEN or GH_TOKEN
+// environment variable, from ~/.dispense/github_token or from the GitHub CLI
+// if it is logged in to github.com
+func LoadGitHubToken() (string, error) {
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token, nil
+		}
+	}
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	tokenBytes, err := os.ReadFile(filepath.Join(configDir, GitHubTokenFileName))
+	if os.IsNotExist(err) {
+		if token := loadGitHubCLIToken(); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("GitHub token not found in environment variable GITHUB_TOKEN or GH_TOKEN, in ~/%s/%s or from gh auth token", ConfigDirName, GitHubTokenFileName)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read GitHub token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(tokenBytes))
+	if token == "" {
+		return "", fmt.Errorf("GitHub token is empty")
+	}
+
+	return token, nil
+}
+
+// loadGitHubCLIToken returns the token the GitHub CLI stored for github.com,
+// or an empty string if gh is not installed or not logged in
+func loadGitHubCLIToken() string {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ghTokenTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", "github.com").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
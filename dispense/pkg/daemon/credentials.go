@@ -0,0 +1,226 @@
+package daemon
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// serverName is the name in the daemon's certificate, it matches the
+// ServerName the daemon expects clients to verify
+const serverName = "dispensed"
+
+// certificateValidity is how long the certificates of a sandbox are valid
+const certificateValidity = 10 * 365 * 24 * time.Hour
+
+// Credentials authenticate the CLI to the daemon of one sandbox. The token is
+// sent with every call; the certificates are only set when the sandbox was
+// created with mutual TLS.
+type Credentials struct {
+	Token      string `json:"token"`
+	CACert     []byte `json:"ca_cert,omitempty"`
+	CAKey      []byte `json:"ca_key,omitempty"`
+	ServerCert []byte `json:"server_cert,omitempty"`
+	ServerKey  []byte `json:"server_key,omitempty"`
+	ClientCert []byte `json:"client_cert,omitempty"`
+	ClientKey  []byte `json:"client_key,omitempty"`
+}
+
+// SandboxFile is a credentials file installed in the sandbox, its path is
+// relative to the daemon's credentials directory ~/.dispense
+type SandboxFile struct {
+	Path    string
+	Content []byte
+}
+
+// GenerateCredentials creates a random token and, if withTLS is set, a
+// self-signed CA with a server certificate for the daemon and a client
+// certificate for the CLI
+func GenerateCredentials(withTLS bool) (*Credentials, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	creds := &Credentials{Token: hex.EncodeToString(token)}
+
+	if !withTLS {
+		return creds, nil
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "dispense sandbox CA"},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caCert, err := createCertificate(caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	caParent, err := x509.ParseCertificate(caCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	serverCert, serverKey, err := issueCertificate(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: serverName},
+		DNSNames:    []string{serverName},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, caParent, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server certificate: %w", err)
+	}
+
+	clientCert, clientKey, err := issueCertificate(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "dispense"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caParent, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client certificate: %w", err)
+	}
+
+	caKeyPEM, err := encodeKey(caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	creds.CACert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert})
+	creds.CAKey = caKeyPEM
+	creds.ServerCert = serverCert
+	creds.ServerKey = serverKey
+	creds.ClientCert = clientCert
+	creds.ClientKey = clientKey
+	return creds, nil
+}
+
+// UsesTLS reports whether the daemon is reached over mutual TLS
+func (c *Credentials) UsesTLS() bool {
+	return len(c.CACert) > 0 && len(c.ClientCert) > 0 && len(c.ClientKey) > 0
+}
+
+// SandboxFiles returns the files the daemon reads its credentials from. The
+// CA key and the client certificate stay on this machine.
+func (c *Credentials) SandboxFiles() []SandboxFile {
+	files := []SandboxFile{{Path: "daemon.token", Content: []byte(c.Token + "\n")}}
+	if c.UsesTLS() {
+		files = append(files,
+			SandboxFile{Path: "tls/ca.pem", Content: c.CACert},
+			SandboxFile{Path: "tls/server.pem", Content: c.ServerCert},
+			SandboxFile{Path: "tls/server-key.pem", Content: c.ServerKey},
+		)
+	}
+	return files
+}
+
+// DialOptions returns the gRPC options for connecting to the daemon with
+// these credentials
+func (c *Credentials) DialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if c.UsesTLS() {
+		certificate, err := tls.X509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(c.CACert) {
+			return nil, fmt.Errorf("failed to load sandbox CA certificate")
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			RootCAs:      roots,
+			ServerName:   serverName,
+			MinVersion:   tls.VersionTLS12,
+		})))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if c.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(c.Token)))
+	}
+	return opts, nil
+}
+
+// InsecureDialOptions returns the gRPC options for daemons of sandboxes that
+// were created without credentials
+func InsecureDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+}
+
+// tokenCredentials sends the token as bearer authorization with every call
+type tokenCredentials string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. The
+// token is also sent without TLS, as the daemon of a sandbox without TLS is
+// reached through an SSH tunnel or the local Docker network.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// issueCertificate creates a key and a certificate for it signed by the CA,
+// both PEM encoded
+func issueCertificate(template, ca *x509.Certificate, caKey *ecdsa.PrivateKey) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	cert, err := createCertificate(template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), keyPEM, nil
+}
+
+// createCertificate fills in the serial number and validity of template and
+// signs it, returning the DER encoded certificate
+func createCertificate(template, parent *x509.Certificate, publicKey *ecdsa.PublicKey, signer *ecdsa.PrivateKey) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(certificateValidity)
+
+	return x509.CreateCertificate(rand.Reader, template, parent, publicKey, signer)
+}
+
+// encodeKey PEM encodes a private key
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
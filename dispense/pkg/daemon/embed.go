@@ -7,20 +7,58 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// Embed the daemon binary (will be populated during build)
+// Embed the daemon binaries (will be populated during build)
 //go:embed daemon-linux-amd64
-var daemonBinaryLinux []byte
+var daemonBinaryLinuxAMD64 []byte
+
+//go:embed daemon-linux-arm64
+var daemonBinaryLinuxARM64 []byte
+
+// daemonArchitectures maps the architectures reported by uname -m, and the Go
+// names for them, to the architecture of the embedded daemon built for them
+var daemonArchitectures = map[string]string{
+	"x86_64":  "amd64",
+	"amd64":   "amd64",
+	"aarch64": "arm64",
+	"arm64":   "arm64",
+}
+
+// Build metadata of the embedded daemon binary - set during compilation. The
+// daemon is built together with the CLI, so it receives the same values.
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
 
 // EmbeddedDaemon handles the embedded daemon binary
 type EmbeddedDaemon struct {
+	binary        []byte
+	arch          string
 	extractedPath string
 }
 
-// NewEmbeddedDaemon creates a new embedded daemon instance
+// NewEmbeddedDaemon creates a new embedded daemon instance for linux/amd64
 func NewEmbeddedDaemon() *EmbeddedDaemon {
-	return &EmbeddedDaemon{}
+	return &EmbeddedDaemon{binary: daemonBinaryLinuxAMD64, arch: "amd64"}
+}
+
+// NewEmbeddedDaemonForArch creates a new embedded daemon instance for a Linux
+// machine architecture as reported by uname -m, such as x86_64 or aarch64
+func NewEmbeddedDaemonForArch(arch string) (*EmbeddedDaemon, error) {
+	switch daemonArchitectures[strings.TrimSpace(arch)] {
+	case "amd64":
+		return NewEmbeddedDaemon(), nil
+	case "arm64":
+		return &EmbeddedDaemon{binary: daemonBinaryLinuxARM64, arch: "arm64"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported sandbox architecture %q: the daemon is only available for x86_64 and aarch64", strings.TrimSpace(arch))
+	}
 }
 
 // Extract extracts the embedded daemon binary to a temporary location
@@ -34,7 +72,7 @@ func (d *EmbeddedDaemon) Extract() error {
 	daemonPath := filepath.Join(tempDir, "daemon")
 
 	// Write the embedded binary to temporary file
-	err = os.WriteFile(daemonPath, daemonBinaryLinux, 0755)
+	err = os.WriteFile(daemonPath, d.binary, 0755)
 	if err != nil {
 		os.RemoveAll(tempDir)
 		return fmt.Errorf("failed to write daemon binary: %w", err)
@@ -101,7 +139,7 @@ func (d *EmbeddedDaemon) Cleanup() error {
 
 // IsSupported checks if the current platform supports the embedded daemon
 func (d *EmbeddedDaemon) IsSupported() bool {
-	// The embedded daemon is compiled for Linux AMD64
+	// The embedded daemon is compiled for Linux AMD64 and ARM64
 	// It can run on Linux systems or via compatibility layers
 	return runtime.GOOS == "linux" ||
 		   runtime.GOOS == "darwin" || // macOS can run Linux binaries via Docker/containers
@@ -110,10 +148,83 @@ func (d *EmbeddedDaemon) IsSupported() bool {
 
 // GetVersion returns version info about the embedded daemon
 func (d *EmbeddedDaemon) GetVersion() string {
-	return "embedded-linux-amd64"
+	return Version
+}
+
+// IsNewerThan reports whether the embedded daemon is a newer build than a
+// daemon reporting the given version and build time. Release versions are
+// compared first; builds of the same or of development versions are compared
+// by their build time. Builds that cannot be ordered are not considered newer.
+func (d *EmbeddedDaemon) IsNewerThan(version, buildTime string) bool {
+	if cmp, ok := compareVersions(Version, version); ok && cmp != 0 {
+		return cmp > 0
+	}
+
+	embeddedBuilt, err := time.Parse(time.RFC3339, BuildTime)
+	if err != nil {
+		return false
+	}
+	runningBuilt, err := time.Parse(time.RFC3339, buildTime)
+	if err != nil {
+		return false
+	}
+	return embeddedBuilt.After(runningBuilt)
+}
+
+// compareVersions compares two versions such as "0.2.0" or "v1.3", returning
+// false if either of them is not a release version
+func compareVersions(a, b string) (int, bool) {
+	partsA, ok := parseVersion(a)
+	if !ok {
+		return 0, false
+	}
+	partsB, ok := parseVersion(b)
+	if !ok {
+		return 0, false
+	}
+
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var numA, numB int
+		if i < len(partsA) {
+			numA = partsA[i]
+		}
+		if i < len(partsB) {
+			numB = partsB[i]
+		}
+		if numA != numB {
+			if numA > numB {
+				return 1, true
+			}
+			return -1, true
+		}
+	}
+	return 0, true
+}
+
+// parseVersion splits a release version into its numeric parts
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return nil, false
+	}
+
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		num, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, num)
+	}
+	return parts, true
 }
 
 // Size returns the size of the embedded daemon binary in bytes
 func (d *EmbeddedDaemon) Size() int {
-	return len(daemonBinaryLinux)
+	return len(d.binary)
+}
+
+// Arch returns the architecture the embedded daemon binary is built for
+func (d *EmbeddedDaemon) Arch() string {
+	return d.arch
 }
\ No newline at end of file
@@ -0,0 +1,65 @@
+package daemon
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	ordered := [][2]string{
+		{"0.10.0", "0.9.1"},
+		{"1.2.1", "1.2"},
+		{" v2.0.0 ", "1.9.9"},
+	}
+	for _, pair := range ordered {
+		if cmp, ok := compareVersions(pair[0], pair[1]); cmp != 1 || !ok {
+			t.Errorf("compareVersions(%q, %q) = %d, %v, want 1, true", pair[0], pair[1], cmp, ok)
+		}
+		if cmp, ok := compareVersions(pair[1], pair[0]); cmp != -1 || !ok {
+			t.Errorf("compareVersions(%q, %q) = %d, %v, want -1, true", pair[1], pair[0], cmp, ok)
+		}
+	}
+
+	if cmp, ok := compareVersions("v1.3", "1.3.0"); cmp != 0 || !ok {
+		t.Errorf("compareVersions() of equal versions = %d, %v, want 0, true", cmp, ok)
+	}
+
+	// Development builds and pre-releases cannot be ordered by version
+	for _, version := range []string{"dev", "", "0.2.0-rc1"} {
+		if _, ok := compareVersions(version, "0.2.0"); ok {
+			t.Errorf("compareVersions(%q, %q) reported an order", version, "0.2.0")
+		}
+	}
+}
+
+func TestIsNewerThan(t *testing.T) {
+	defer func(version, buildTime string) { Version, BuildTime = version, buildTime }(Version, BuildTime)
+
+	isNewerThan := func(embedded, embeddedBuilt, version, buildTime string) bool {
+		Version, BuildTime = embedded, embeddedBuilt
+		return NewEmbeddedDaemon().IsNewerThan(version, buildTime)
+	}
+
+	if !isNewerThan("0.3.0", "2024-05-01T10:00:00Z", "0.2.0", "2024-06-01T10:00:00Z") {
+		t.Error("a newer version built earlier is not newer")
+	}
+	if isNewerThan("0.2.0", "2024-06-01T10:00:00Z", "0.3.0", "2024-05-01T10:00:00Z") {
+		t.Error("an older version built later is newer")
+	}
+
+	// Builds of the same or of development versions are ordered by build time
+	if !isNewerThan("0.2.0", "2024-05-02T10:00:00Z", "0.2.0", "2024-05-01T10:00:00Z") {
+		t.Error("a later build of the same version is not newer")
+	}
+	if isNewerThan("0.2.0", "2024-05-01T10:00:00Z", "0.2.0", "2024-05-01T10:00:00Z") {
+		t.Error("the same build is newer")
+	}
+	if !isNewerThan("dev", "2024-05-02T10:00:00Z", "dev", "2024-05-01T10:00:00Z") {
+		t.Error("a later development build is not newer")
+	}
+	if isNewerThan("dev", "2024-05-01T10:00:00Z", "0.2.0", "2024-05-02T10:00:00Z") {
+		t.Error("an earlier development build is newer than a release")
+	}
+
+	// Builds that cannot be ordered are not replaced
+	if isNewerThan("dev", "unknown", "dev", "2024-05-01T10:00:00Z") || isNewerThan("dev", "2024-05-02T10:00:00Z", "dev", "") {
+		t.Error("a build without a build time is ordered")
+	}
+}
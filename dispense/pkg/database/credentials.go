@@ -0,0 +1,56 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"cli/pkg/daemon"
+
+	"github.com/asdine/storm/v3"
+)
+
+// DaemonCredentials are the credentials for the daemon of a sandbox, kept
+// for as long as the sandbox exists
+type DaemonCredentials struct {
+	SandboxID   string             `storm:"id" json:"sandbox_id"`
+	SandboxName string             `json:"sandbox_name"`
+	Credentials daemon.Credentials `json:"credentials"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// SaveDaemonCredentials stores the daemon credentials of a sandbox
+func (sdb *SandboxDB) SaveDaemonCredentials(sandboxID, sandboxName string, creds *daemon.Credentials) error {
+	return sdb.db.Save(&DaemonCredentials{
+		SandboxID:   sandboxID,
+		SandboxName: sandboxName,
+		Credentials: *creds,
+		CreatedAt:   time.Now(),
+	})
+}
+
+// GetDaemonCredentials retrieves the daemon credentials of a sandbox, nil if
+// the sandbox was created without credentials
+func (sdb *SandboxDB) GetDaemonCredentials(sandboxID string) (*daemon.Credentials, error) {
+	var record DaemonCredentials
+	err := sdb.db.One("SandboxID", sandboxID, &record)
+	if errors.Is(err, storm.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record.Credentials, nil
+}
+
+// DeleteDaemonCredentials removes the daemon credentials of a sandbox
+func (sdb *SandboxDB) DeleteDaemonCredentials(sandboxID string) error {
+	var record DaemonCredentials
+	err := sdb.db.One("SandboxID", sandboxID, &record)
+	if errors.Is(err, storm.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return sdb.db.DeleteStruct(&record)
+}
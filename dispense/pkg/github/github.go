@@ -0,0 +1,491 @@
+package github
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"cli/pkg/utils"
+)
+
+// DefaultBaseURL is the address of the GitHub REST API
+const DefaultBaseURL = "https://api.github.com"
+
+// requestTimeout limits every request to the GitHub API
+const requestTimeout = 30 * time.Second
+
+// pageSize is the number of items requested per page of a list
+const pageSize = 100
+
+// Client calls the GitHub REST API, authenticated with a token if one is set
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for api.github.com, token may be empty for
+// unauthenticated access to public repositories
+func NewClient(token string) *Client {
+	return NewClientWithBaseURL(DefaultBaseURL, token)
+}
+
+// NewClientWithBaseURL creates a client for the GitHub API at baseURL, such as
+// the API of a GitHub Enterprise server
+func NewClientWithBaseURL(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// User is a GitHub account
+type User struct {
+	Login string `json:"login"`
+}
+
+// Repository is the part of a GitHub repository dispense uses
+type Repository struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Owner         User   `json:"owner"`
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+}
+
+// Issue is the part of a GitHub issue dispense uses
+type Issue struct {
+	Number    int    `json:"number"`
+	HTMLURL   string `json:"html_url"`
+	State     string `json:"state"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	User      User   `json:"user"`
+	CreatedAt string `json:"created_at"`
+}
+
+// PullRequest is the part of a GitHub pull request dispense uses
+type PullRequest struct {
+	Number  int               `json:"number"`
+	HTMLURL string            `json:"html_url"`
+	State   string            `json:"state"`
+	Title   string            `json:"title"`
+	Body    string            `json:"body"`
+	Draft   bool              `json:"draft"`
+	User    User              `json:"user"`
+	Head    PullRequestBranch `json:"head"`
+	Base    PullRequestBranch `json:"base"`
+}
+
+// PullRequestBranch is the head or base branch of a pull request. Repo is nil
+// when the repository of the head branch was deleted.
+type PullRequestBranch struct {
+	Ref  string      `json:"ref"`
+	Repo *Repository `json:"repo"`
+}
+
+// ReviewThread is a conversation about a line of a pull request
+type ReviewThread struct {
+	Path     string           `json:"path"`
+	Line     int              `json:"line,omitempty"`
+	Resolved bool             `json:"resolved,omitempty"`
+	Outdated bool             `json:"outdated,omitempty"`
+	Comments []*ReviewComment `json:"comments"`
+}
+
+// ReviewComment is a comment in a review thread
+type ReviewComment struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+// Review is a review of a pull request, with the comment left on the pull
+// request as a whole
+type Review struct {
+	Author string `json:"author"`
+	State  string `json:"state"`
+	Body   string `json:"body"`
+}
+
+// NewPullRequest describes a pull request to open. Head is the branch with the
+// changes, Base the branch they should be merged into.
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body,omitempty"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+// APIError is an error response of the GitHub API
+type APIError struct {
+	StatusCode int
+	Message    string `json:"message"`
+	Errors     []struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"errors"`
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	var details []string
+	for _, detail := range e.Errors {
+		if detail.Message != "" {
+			details = append(details, detail.Message)
+		} else if detail.Code != "" {
+			details = append(details, detail.Code)
+		}
+	}
+	if len(details) > 0 {
+		message += ": " + strings.Join(details, ", ")
+	}
+	return fmt.Sprintf("GitHub API returned status %d: %s", e.StatusCode, message)
+}
+
+// GetRepository gets a repository
+func (c *Client) GetRepository(owner, repo string) (*Repository, error) {
+	var repository Repository
+	if err := c.do(http.MethodGet, fmt.Sprintf("/repos/%s/%s", owner, repo), nil, &repository); err != nil {
+		return nil, err
+	}
+	return &repository, nil
+}
+
+// FindOpenPullRequest returns the open pull request of a repository for the
+// branch head of headOwner, or nil if there is none
+func (c *Client) FindOpenPullRequest(owner, repo, headOwner, head string) (*PullRequest, error) {
+	query := url.Values{}
+	query.Set("state", "open")
+	query.Set("head", headOwner+":"+head)
+
+	var pullRequests []*PullRequest
+	if err := c.do(http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls?%s", owner, repo, query.Encode()), nil, &pullRequests); err != nil {
+		return nil, err
+	}
+	if len(pullRequests) == 0 {
+		return nil, nil
+	}
+	return pullRequests[0], nil
+}
+
+// CreatePullRequest opens a pull request in a repository
+func (c *Client) CreatePullRequest(owner, repo string, pullRequest *NewPullRequest) (*PullRequest, error) {
+	var created PullRequest
+	if err := c.do(http.MethodPost, fmt.Sprintf("/repos/%s/%s/pulls", owner, repo), pullRequest, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetIssue gets an issue
+func (c *Client) GetIssue(owner, repo string, number int) (*Issue, error) {
+	var issue Issue
+	if err := c.do(http.MethodGet, fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// GetPullRequest gets a pull request
+func (c *Client) GetPullRequest(owner, repo string, number int) (*PullRequest, error) {
+	var pullRequest PullRequest
+	if err := c.do(http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number), nil, &pullRequest); err != nil {
+		return nil, err
+	}
+	return &pullRequest, nil
+}
+
+// ListReviews lists the reviews of a pull request that left a comment on the
+// pull request as a whole
+func (c *Client) ListReviews(owner, repo string, number int) ([]*Review, error) {
+	var reviews []*Review
+	for page := 1; ; page++ {
+		var batch []struct {
+			User  User   `json:"user"`
+			State string `json:"state"`
+			Body  string `json:"body"`
+		}
+		if err := c.do(http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews?per_page=%d&page=%d", owner, repo, number, pageSize, page), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, review := range batch {
+			if strings.TrimSpace(review.Body) != "" {
+				reviews = append(reviews, &Review{Author: review.User.Login, State: review.State, Body: review.Body})
+			}
+		}
+		if len(batch) < pageSize {
+			return reviews, nil
+		}
+	}
+}
+
+// ListReviewThreads lists the review threads of a pull request. Whether a
+// thread is resolved is only known to the GraphQL API, which needs a token;
+// without one the threads come from the REST API and none is resolved.
+func (c *Client) ListReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	if c.token == "" {
+		return c.listReviewCommentThreads(owner, repo, number)
+	}
+
+	const query = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          isResolved
+          isOutdated
+          path
+          line
+          originalLine
+          comments(first: 100) { nodes { author { login } body } }
+        }
+      }
+    }
+  }
+}`
+
+	var threads []*ReviewThread
+	var cursor *string
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest *struct {
+					ReviewThreads struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							IsResolved   bool   `json:"isResolved"`
+							IsOutdated   bool   `json:"isOutdated"`
+							Path         string `json:"path"`
+							Line         *int   `json:"line"`
+							OriginalLine *int   `json:"originalLine"`
+							Comments     struct {
+								Nodes []struct {
+									Author *User  `json:"author"`
+									Body   string `json:"body"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		variables := map[string]interface{}{"owner": owner, "repo": repo, "number": number, "cursor": cursor}
+		if err := c.graphQL(query, variables, &data); err != nil {
+			return nil, err
+		}
+		if data.Repository.PullRequest == nil {
+			return nil, fmt.Errorf("pull request %s/%s#%d not found", owner, repo, number)
+		}
+
+		reviewThreads := data.Repository.PullRequest.ReviewThreads
+		for _, node := range reviewThreads.Nodes {
+			thread := &ReviewThread{Path: node.Path, Resolved: node.IsResolved, Outdated: node.IsOutdated}
+			if node.Line != nil {
+				thread.Line = *node.Line
+			} else if node.OriginalLine != nil {
+				thread.Line = *node.OriginalLine
+			}
+			for _, comment := range node.Comments.Nodes {
+				author := "ghost"
+				if comment.Author != nil {
+					author = comment.Author.Login
+				}
+				thread.Comments = append(thread.Comments, &ReviewComment{Author: author, Body: comment.Body})
+			}
+			threads = append(threads, thread)
+		}
+
+		if !reviewThreads.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		cursor = &reviewThreads.PageInfo.EndCursor
+	}
+}
+
+// listReviewCommentThreads lists the review threads of a pull request from its
+// review comments, where replies point to the first comment of their thread
+func (c *Client) listReviewCommentThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	var threads []*ReviewThread
+	byID := make(map[int64]*ReviewThread)
+	for page := 1; ; page++ {
+		var batch []struct {
+			ID           int64  `json:"id"`
+			InReplyToID  int64  `json:"in_reply_to_id"`
+			Path         string `json:"path"`
+			Line         *int   `json:"line"`
+			OriginalLine *int   `json:"original_line"`
+			Body         string `json:"body"`
+			User         User   `json:"user"`
+		}
+		if err := c.do(http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d/comments?per_page=%d&page=%d", owner, repo, number, pageSize, page), nil, &batch); err != nil {
+			return nil, err
+		}
+
+		for _, comment := range batch {
+			reviewComment := &ReviewComment{Author: comment.User.Login, Body: comment.Body}
+			if thread, exists := byID[comment.InReplyToID]; exists {
+				thread.Comments = append(thread.Comments, reviewComment)
+				byID[comment.ID] = thread
+				continue
+			}
+
+			// Comments on lines that changed since have no line anymore
+			thread := &ReviewThread{Path: comment.Path, Outdated: comment.Line == nil, Comments: []*ReviewComment{reviewComment}}
+			if comment.Line != nil {
+				thread.Line = *comment.Line
+			} else if comment.OriginalLine != nil {
+				thread.Line = *comment.OriginalLine
+			}
+			byID[comment.ID] = thread
+			threads = append(threads, thread)
+		}
+
+		if len(batch) < pageSize {
+			return threads, nil
+		}
+	}
+}
+
+// graphQL sends a query to the GraphQL API and decodes its data into result
+func (c *Client) graphQL(query string, variables map[string]interface{}, result interface{}) error {
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	body := map[string]interface{}{"query": query, "variables": variables}
+	if err := c.do(http.MethodPost, "/graphql", body, &response); err != nil {
+		return err
+	}
+
+	if len(response.Errors) > 0 {
+		var messages []string
+		for _, graphQLErr := range response.Errors {
+			messages = append(messages, graphQLErr.Message)
+		}
+		return fmt.Errorf("GitHub GraphQL API returned an error: %s", strings.Join(messages, ", "))
+	}
+	if err := json.Unmarshal(response.Data, result); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	return nil
+}
+
+// do sends a request to the API and decodes the JSON response into result
+func (c *Client) do(method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	utils.DebugPrintf("GitHub API request: %s %s\n", method, path)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(apiErr)
+		return apiErr
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	return nil
+}
+
+// remoteURLRegex matches the HTTPS and SSH URLs of GitHub repositories
+var remoteURLRegex = regexp.MustCompile(`^(?:https://(?:[^@/]+@)?github\.com/|(?:ssh://)?git@github\.com[:/])([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// ParseRemoteURL returns the owner and name of the GitHub repository a git
+// remote URL points to
+func ParseRemoteURL(remoteURL string) (owner, repo string, ok bool) {
+	matches := remoteURLRegex.FindStringSubmatch(strings.TrimSpace(remoteURL))
+	if matches == nil {
+		return "", "", false
+	}
+	return matches[1], matches[2], true
+}
+
+// GitAuthHeader returns the HTTP header git authenticates to github.com with
+// when it is passed as http.extraheader, so the token never has to be part of
+// a remote URL
+func GitAuthHeader(token string) string {
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	return "AUTHORIZATION: basic " + credentials
+}
+
+// GitCredentialHelper returns a git credential helper script that gives git
+// the token for github.com until expiresAt. After that it answers nothing and
+// removes itself, so the token is only usable for a limited time.
+func GitCredentialHelper(token string, expiresAt time.Time) string {
+	return fmt.Sprintf(`#!/bin/sh
+# Git credential helper installed by dispense, valid until %s
+if [ "$(date +%%s)" -ge %d ]; then
+	rm -f "$0"
+	exit 0
+fi
+if [ "$1" = get ]; then
+	echo username=x-access-token
+	echo 'password=%s'
+fi
+`, expiresAt.UTC().Format(time.RFC3339), expiresAt.Unix(), strings.ReplaceAll(token, "'", `'\''`))
+}
+
+// PullRequestBody returns the description of a pull request for work on an
+// issue. The issue is closed by the pull request when issueNumber is set, and
+// summary is Claude's description of the changes.
+func PullRequestBody(issueNumber int, summary string) string {
+	var body strings.Builder
+	if issueNumber > 0 {
+		body.WriteString(fmt.Sprintf("Fixes #%d\n\n", issueNumber))
+	}
+	if summary = strings.TrimSpace(summary); summary != "" {
+		body.WriteString("## Summary\n\n")
+		body.WriteString(summary)
+		body.WriteString("\n\n")
+	}
+	body.WriteString("Opened by dispense.")
+	return body.String()
+}
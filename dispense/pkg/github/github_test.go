@@ -0,0 +1,347 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer starts a stand-in for api.github.com that serves handler
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClientWithBaseURL(server.URL, "test-token")
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/octo/app/pulls" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer test-token")
+		}
+
+		var req NewPullRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Head != "fix-login" || req.Base != "main" || req.Title != "Fix login" || !req.Draft {
+			t.Errorf("unexpected pull request %+v", req)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"number":   7,
+			"html_url": "https://github.com/octo/app/pull/7",
+			"state":    "open",
+			"draft":    true,
+		})
+	})
+
+	pr, err := client.CreatePullRequest("octo", "app", &NewPullRequest{
+		Title: "Fix login",
+		Head:  "fix-login",
+		Base:  "main",
+		Body:  "Fixes #3",
+		Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() failed: %v", err)
+	}
+	if pr.Number != 7 || pr.HTMLURL != "https://github.com/octo/app/pull/7" || !pr.Draft {
+		t.Errorf("CreatePullRequest() = %+v", pr)
+	}
+}
+
+func TestCreatePullRequestError(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"Validation Failed","errors":[{"message":"No commits between main and fix-login"}]}`))
+	})
+
+	_, err := client.CreatePullRequest("octo", "app", &NewPullRequest{Title: "Fix", Head: "fix-login", Base: "main"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("CreatePullRequest() error = %v, want an APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusUnprocessableEntity)
+	}
+	if !strings.Contains(err.Error(), "No commits between main and fix-login") {
+		t.Errorf("error %q does not contain the validation message", err)
+	}
+}
+
+func TestFindOpenPullRequest(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/app/pulls" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("state") != "open" {
+			t.Errorf("state = %q, want open", r.URL.Query().Get("state"))
+		}
+
+		if r.URL.Query().Get("head") == "octo:fix-login" {
+			w.Write([]byte(`[{"number":7,"html_url":"https://github.com/octo/app/pull/7","state":"open"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	pr, err := client.FindOpenPullRequest("octo", "app", "octo", "fix-login")
+	if err != nil {
+		t.Fatalf("FindOpenPullRequest() failed: %v", err)
+	}
+	if pr == nil || pr.Number != 7 {
+		t.Errorf("FindOpenPullRequest() = %+v, want pull request 7", pr)
+	}
+
+	pr, err = client.FindOpenPullRequest("octo", "app", "octo", "other")
+	if err != nil {
+		t.Fatalf("FindOpenPullRequest() failed: %v", err)
+	}
+	if pr != nil {
+		t.Errorf("FindOpenPullRequest() = %+v, want nil", pr)
+	}
+}
+
+func TestGetRepository(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/app" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"full_name":"octo/app","default_branch":"develop","private":true}`))
+	})
+
+	repository, err := client.GetRepository("octo", "app")
+	if err != nil {
+		t.Fatalf("GetRepository() failed: %v", err)
+	}
+	if repository.DefaultBranch != "develop" || !repository.Private {
+		t.Errorf("GetRepository() = %+v", repository)
+	}
+}
+
+func TestUnauthenticatedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want none", got)
+		}
+		w.Write([]byte(`{"default_branch":"main"}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewClientWithBaseURL(server.URL, "").GetRepository("octo", "app"); err != nil {
+		t.Fatalf("GetRepository() failed: %v", err)
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		owner string
+		repo  string
+		ok    bool
+	}{
+		{"https://github.com/octo/app.git", "octo", "app", true},
+		{"https://github.com/octo/app", "octo", "app", true},
+		{"https://x-access-token@github.com/octo/app.git", "octo", "app", true},
+		{"git@github.com:octo/app.git", "octo", "app", true},
+		{"ssh://git@github.com/octo/my.app.git", "octo", "my.app", true},
+		{"https://gitlab.com/octo/app.git", "", "", false},
+		{"/home/user/app", "", "", false},
+	}
+
+	for _, tt := range tests {
+		owner, repo, ok := ParseRemoteURL(tt.url)
+		if owner != tt.owner || repo != tt.repo || ok != tt.ok {
+			t.Errorf("ParseRemoteURL(%q) = %q, %q, %v, want %q, %q, %v", tt.url, owner, repo, ok, tt.owner, tt.repo, tt.ok)
+		}
+	}
+}
+
+func TestPullRequestBody(t *testing.T) {
+	body := PullRequestBody(42, "Fixed the login timeout.\n")
+	if !strings.HasPrefix(body, "Fixes #42\n") {
+		t.Errorf("body %q does not start with the issue reference", body)
+	}
+	if !strings.Contains(body, "Fixed the login timeout.") {
+		t.Errorf("body %q does not contain the summary", body)
+	}
+
+	if body := PullRequestBody(0, ""); strings.Contains(body, "Fixes") || strings.Contains(body, "Summary") {
+		t.Errorf("PullRequestBody(0, \"\") = %q, want neither an issue reference nor a summary", body)
+	}
+}
+
+func TestGetIssue(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/app/issues/3" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer test-token")
+		}
+		w.Write([]byte(`{"number":3,"html_url":"https://github.com/octo/app/issues/3","title":"Login times out",
+			"state":"open","user":{"login":"bob"},"created_at":"2024-05-01T10:00:00Z"}`))
+	})
+
+	issue, err := client.GetIssue("octo", "app", 3)
+	if err != nil {
+		t.Fatalf("GetIssue() failed: %v", err)
+	}
+	if issue.Number != 3 || issue.Title != "Login times out" || issue.User.Login != "bob" || issue.CreatedAt != "2024-05-01T10:00:00Z" {
+		t.Errorf("GetIssue() = %+v", issue)
+	}
+}
+
+func TestGitCredentialHelper(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	// runHelper installs a helper and runs it with the git credential operation
+	runHelper := func(expiresAt time.Time, operation string) (string, string) {
+		path := filepath.Join(t.TempDir(), "git-credential-github")
+		if err := os.WriteFile(path, []byte(GitCredentialHelper("ghp_it's", expiresAt)), 0700); err != nil {
+			t.Fatal(err)
+		}
+		output, err := exec.Command("sh", path, operation).Output()
+		if err != nil {
+			t.Fatalf("helper failed: %v", err)
+		}
+		return string(output), path
+	}
+
+	output, _ := runHelper(time.Now().Add(time.Hour), "get")
+	if output != "username=x-access-token\npassword=ghp_it's\n" {
+		t.Errorf("get = %q, want the token", output)
+	}
+	if output, _ := runHelper(time.Now().Add(time.Hour), "store"); output != "" {
+		t.Errorf("store = %q, want nothing", output)
+	}
+
+	output, path := runHelper(time.Now().Add(-time.Minute), "get")
+	if output != "" {
+		t.Errorf("get after expiry = %q, want nothing", output)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expired helper was not removed: %v", err)
+	}
+}
+
+func TestGetPullRequest(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/app/pulls/7" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"number":7,"title":"Fix login","state":"open","user":{"login":"alice"},
+			"head":{"ref":"fix-login","repo":{"name":"app-fork","owner":{"login":"alice"}}},
+			"base":{"ref":"main","repo":{"name":"app","owner":{"login":"octo"}}}}`))
+	})
+
+	pr, err := client.GetPullRequest("octo", "app", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequest() failed: %v", err)
+	}
+	if pr.Head.Ref != "fix-login" || pr.Head.Repo == nil || pr.Head.Repo.Owner.Login != "alice" || pr.Head.Repo.Name != "app-fork" {
+		t.Errorf("unexpected head %+v", pr.Head)
+	}
+	if pr.Base.Ref != "main" || pr.User.Login != "alice" {
+		t.Errorf("unexpected pull request %+v", pr)
+	}
+}
+
+func TestListReviewThreadsGraphQL(t *testing.T) {
+	requests := 0
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Variables["number"] != float64(7) {
+			t.Errorf("number = %v, want 7", req.Variables["number"])
+		}
+
+		// The second page is requested with the cursor of the first
+		requests++
+		if requests == 1 {
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"abc"},
+				"nodes":[{"isResolved":true,"isOutdated":false,"path":"a.go","line":3,"comments":{"nodes":[{"author":{"login":"bob"},"body":"Done?"}]}}]}}}}}`))
+			return
+		}
+		if req.Variables["cursor"] != "abc" {
+			t.Errorf("cursor = %v, want abc", req.Variables["cursor"])
+		}
+		w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"pageInfo":{"hasNextPage":false,"endCursor":""},
+			"nodes":[{"isResolved":false,"isOutdated":true,"path":"b.go","line":null,"originalLine":9,"comments":{"nodes":[{"author":null,"body":"Rename this"}]}}]}}}}}`))
+	})
+
+	threads, err := client.ListReviewThreads("octo", "app", 7)
+	if err != nil {
+		t.Fatalf("ListReviewThreads() failed: %v", err)
+	}
+	if len(threads) != 2 {
+		t.Fatalf("ListReviewThreads() returned %d threads, want 2", len(threads))
+	}
+	if !threads[0].Resolved || threads[0].Line != 3 || threads[0].Comments[0].Author != "bob" {
+		t.Errorf("unexpected first thread %+v", threads[0])
+	}
+	if threads[1].Resolved || !threads[1].Outdated || threads[1].Line != 9 || threads[1].Comments[0].Author != "ghost" {
+		t.Errorf("unexpected second thread %+v", threads[1])
+	}
+}
+
+func TestListReviewThreadsGraphQLError(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":null,"errors":[{"message":"Could not resolve to a Repository"}]}`))
+	})
+
+	if _, err := client.ListReviewThreads("octo", "app", 7); err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("ListReviewThreads() error = %v, want the GraphQL error", err)
+	}
+}
+
+func TestListReviewThreadsWithoutToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/app/pulls/7/comments" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`[
+			{"id":1,"path":"a.go","line":3,"body":"Handle the error","user":{"login":"bob"}},
+			{"id":2,"in_reply_to_id":1,"path":"a.go","line":3,"body":"Will do","user":{"login":"alice"}},
+			{"id":3,"path":"b.go","line":null,"original_line":9,"body":"Rename this","user":{"login":"bob"}}
+		]`))
+	}))
+	defer server.Close()
+
+	threads, err := NewClientWithBaseURL(server.URL, "").ListReviewThreads("octo", "app", 7)
+	if err != nil {
+		t.Fatalf("ListReviewThreads() failed: %v", err)
+	}
+	if len(threads) != 2 {
+		t.Fatalf("ListReviewThreads() returned %d threads, want 2", len(threads))
+	}
+	if len(threads[0].Comments) != 2 || threads[0].Comments[1].Body != "Will do" {
+		t.Errorf("reply was not added to its thread: %+v", threads[0])
+	}
+	if !threads[1].Outdated || threads[1].Line != 9 {
+		t.Errorf("unexpected second thread %+v", threads[1])
+	}
+}
@@ -47,7 +47,7 @@ func (s *Server) Run(ctx context.Context) error {
 			CreateSandbox(s.executor, s.config),
 			mcp.Input(
 				mcp.Property("name", mcp.Description("Sandbox name using alphanumeric characters, hyphens, and underscores (e.g., 'fix-auth-bug', 'feature_123', 'issue-2283'). Will be used as branch name and container identifier."), mcp.Required(true)),
-				mcp.Property("task", mcp.Description("GitHub issue URL (e.g., 'https://github.com/owner/repo/issues/123') OR detailed task description (e.g., 'Fix authentication bug in login system'). For GitHub issues, provide the full URL first, followed by any additional context."), mcp.Required(true)),
+				mcp.Property("task", mcp.Description("GitHub issue URL (e.g., 'https://github.com/owner/repo/issues/123'), GitHub pull request URL to address its unresolved review comments (e.g., 'https://github.com/owner/repo/pull/45') OR detailed task description (e.g., 'Fix authentication bug in login system'). For GitHub issues and pull requests, provide the full URL first, followed by any additional context."), mcp.Required(true)),
 				mcp.Property("remote", mcp.Description("Set to true for cloud-based Daytona sandbox (recommended for resource-intensive tasks or when Docker is unavailable), false for local Docker container (faster for simple tasks). Defaults to false."), mcp.Required(false)),
 				mcp.Property("model", mcp.Description("Anthropic model to use for Claude Code in the sandbox (e.g., 'claude-3-5-sonnet-20241022', 'claude-3-opus-20240229', 'claude-3-5-haiku-20241022'). If not specified, uses the default model."), mcp.Required(false)),
 			),
@@ -61,9 +61,29 @@ func (s *Server) Run(ctx context.Context) error {
 				mcp.Property("command", mcp.Description("The command string to execute (e.g., 'ls -la', 'cp -r /source /destination', 'echo \"Hello World\"')"), mcp.Required(true)),
 			),
 		),
+		mcp.NewServerTool(
+			"dispense_cancel_task",
+			"Cancel a running Claude task in a sandbox. The Claude process is interrupted gracefully first and forcefully killed if it does not exit. If no task ID is given, the most recent task in the sandbox is cancelled.",
+			CancelTask(s.executor, s.config),
+			mcp.Input(
+				mcp.Property("name", mcp.Description("Sandbox name or ID running the task"), mcp.Required(true)),
+				mcp.Property("task_id", mcp.Description("ID of the task to cancel. Defaults to the most recent task in the sandbox."), mcp.Required(false)),
+			),
+		),
+		mcp.NewServerTool(
+			"dispense_diff",
+			"Show what changed in a sandbox's workspace since it was created: commits, uncommitted edits and new untracked files. Returns a unified diff by default, or a per-file summary with stat or name_only. Use it to review Claude's work before merging it.",
+			WorkspaceDiff(s.executor, s.config),
+			mcp.Input(
+				mcp.Property("name", mcp.Description("Sandbox name or ID to show the changes of"), mcp.Required(true)),
+				mcp.Property("base", mcp.Description("Commit, branch or tag to compare with. Defaults to the commit the workspace started from."), mcp.Required(false)),
+				mcp.Property("stat", mcp.Description("Set to true to list the changed files with added and deleted line counts instead of the full diff"), mcp.Required(false)),
+				mcp.Property("name_only", mcp.Description("Set to true to list only the paths of the changed files"), mcp.Required(false)),
+			),
+		),
 	)
 
-	log.Printf("Registered %d MCP tools", 2)
+	log.Printf("Registered %d MCP tools", 4)
 
 	// Start the server with stdio transport
 	transport := mcp.NewStdioTransport()
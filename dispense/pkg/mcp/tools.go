@@ -264,4 +264,177 @@ func ExecCommand(executor CommandExecutor, config *Config) mcp.ToolHandlerFor[Ex
 			StructuredContent: toolResult,
 		}, nil
 	}
-}
\ No newline at end of file
+}
+
+// CancelTaskParams represents the parameters for cancelling a Claude task
+type CancelTaskParams struct {
+	Name   string `json:"name" validate:"required,min=1"`
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// CancelTaskResult represents the result of cancelling a Claude task
+type CancelTaskResult struct {
+	Success      bool   `json:"success"`
+	SandboxName  string `json:"sandbox_name"`
+	TaskID       string `json:"task_id,omitempty"`
+	Output       string `json:"output"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// CancelTask stops a running Claude task in a sandbox
+func CancelTask(executor CommandExecutor, config *Config) mcp.ToolHandlerFor[CancelTaskParams, CancelTaskResult] {
+	validate := validator.New()
+
+	return func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CancelTaskParams]) (*mcp.CallToolResultFor[CancelTaskResult], error) {
+		p := params.Arguments
+
+		// Validate parameters with user-friendly messages
+		if err := validate.Struct(p); err != nil {
+			if p.Name == "" {
+				return nil, fmt.Errorf("sandbox name is required")
+			}
+			return nil, fmt.Errorf("parameter validation failed: %w", err)
+		}
+
+		// Build command arguments for dispense claude cancel
+		args := []string{"claude", p.Name, "cancel"}
+		if p.TaskID != "" {
+			args = append(args, p.TaskID)
+		}
+
+		result, err := executor.ExecuteWithTimeout(args, config.DefaultTimeout)
+
+		toolResult := CancelTaskResult{
+			Success:     false,
+			SandboxName: p.Name,
+			TaskID:      p.TaskID,
+			Output:      result.Stdout,
+		}
+
+		// A dispense command that fails still runs, so its exit code tells whether the task was cancelled
+		if err != nil {
+			toolResult.ErrorMessage = fmt.Sprintf("Failed to cancel task: %v", err)
+			if result.Stderr != "" {
+				toolResult.ErrorMessage += fmt.Sprintf("\nStderr: %s", result.Stderr)
+			}
+		} else if result.ExitCode != 0 {
+			toolResult.ErrorMessage = fmt.Sprintf("Exit Code: %d, Output: %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+			toolResult.Output = ""
+		} else {
+			toolResult.Success = true
+		}
+
+		var responseText string
+		if toolResult.Success {
+			responseText = fmt.Sprintf("🛑 Cancelled Claude task in sandbox '%s'\n", p.Name)
+		} else {
+			responseText = fmt.Sprintf("❌ Failed to cancel Claude task in sandbox '%s'\n", p.Name)
+			if toolResult.ErrorMessage != "" {
+				responseText += fmt.Sprintf("💥 Error: %s\n", toolResult.ErrorMessage)
+			}
+		}
+		if toolResult.Output != "" {
+			responseText += fmt.Sprintf("\n%s", toolResult.Output)
+		}
+
+		return &mcp.CallToolResultFor[CancelTaskResult]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: responseText},
+			},
+			StructuredContent: toolResult,
+		}, nil
+	}
+}
+
+// WorkspaceDiffParams represents the parameters for showing the changes in a sandbox
+type WorkspaceDiffParams struct {
+	Name     string `json:"name" validate:"required,min=1"`
+	Base     string `json:"base,omitempty"`
+	Stat     bool   `json:"stat,omitempty"`
+	NameOnly bool   `json:"name_only,omitempty"`
+}
+
+// WorkspaceDiffResult represents the changes in a sandbox
+type WorkspaceDiffResult struct {
+	Success      bool   `json:"success"`
+	SandboxName  string `json:"sandbox_name"`
+	Output       string `json:"output"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// WorkspaceDiff shows what changed in the workspace of a sandbox since it was created
+func WorkspaceDiff(executor CommandExecutor, config *Config) mcp.ToolHandlerFor[WorkspaceDiffParams, WorkspaceDiffResult] {
+	validate := validator.New()
+
+	return func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[WorkspaceDiffParams]) (*mcp.CallToolResultFor[WorkspaceDiffResult], error) {
+		p := params.Arguments
+
+		// Validate parameters with user-friendly messages
+		if err := validate.Struct(p); err != nil {
+			if p.Name == "" {
+				return nil, fmt.Errorf("sandbox name is required")
+			}
+			return nil, fmt.Errorf("parameter validation failed: %w", err)
+		}
+		if p.Stat && p.NameOnly {
+			return nil, fmt.Errorf("stat and name_only cannot be used together")
+		}
+		if strings.HasPrefix(p.Base, "-") {
+			return nil, fmt.Errorf("base cannot start with '-'")
+		}
+
+		// Build command arguments for dispense diff
+		args := []string{"diff", p.Name}
+		if p.Base != "" {
+			args = append(args, "--base", p.Base)
+		}
+		if p.Stat {
+			args = append(args, "--stat")
+		}
+		if p.NameOnly {
+			args = append(args, "--name-only")
+		}
+
+		result, err := executor.ExecuteWithTimeout(args, config.DefaultTimeout)
+
+		toolResult := WorkspaceDiffResult{
+			Success:     false,
+			SandboxName: p.Name,
+			Output:      result.Stdout,
+		}
+
+		// The output is only a diff if dispense diff exited successfully, otherwise it is the error
+		if err != nil {
+			toolResult.ErrorMessage = fmt.Sprintf("Failed to get workspace diff: %v", err)
+			if result.Stderr != "" {
+				toolResult.ErrorMessage += fmt.Sprintf("\nStderr: %s", result.Stderr)
+			}
+		} else if result.ExitCode != 0 {
+			toolResult.ErrorMessage = fmt.Sprintf("Exit Code: %d, Output: %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+			toolResult.Output = ""
+		} else {
+			toolResult.Success = true
+		}
+
+		var responseText string
+		if toolResult.Success {
+			if toolResult.Output == "" {
+				responseText = fmt.Sprintf("✅ No changes in sandbox '%s'\n", p.Name)
+			} else {
+				responseText = toolResult.Output
+			}
+		} else {
+			responseText = fmt.Sprintf("❌ Failed to get changes in sandbox '%s'\n", p.Name)
+			if toolResult.ErrorMessage != "" {
+				responseText += fmt.Sprintf("💥 Error: %s\n", toolResult.ErrorMessage)
+			}
+		}
+
+		return &mcp.CallToolResultFor[WorkspaceDiffResult]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: responseText},
+			},
+			StructuredContent: toolResult,
+		}, nil
+	}
+}
@@ -0,0 +1,148 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cli/pkg/sandbox"
+	"cli/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames are the names of the repository-level configuration file, in
+// order of preference
+var ConfigFileNames = []string{".dispense.yaml", ".dispense.yml"}
+
+// Config is the repository-level configuration checked in as .dispense.yaml.
+// It describes how sandboxes for the repository are created and set up.
+type Config struct {
+	Snapshot  string            `yaml:"snapshot"`  // Snapshot (remote) or Docker image (local) to create the sandbox from
+	Resources Resources         `yaml:"resources"` // Resources allocated to the sandbox
+	Group     string            `yaml:"group"`     // Default group of the sandbox
+	Model     string            `yaml:"model"`     // Default model of Claude tasks
+	Env       map[string]string `yaml:"env"`       // Environment variables of the sandbox
+	Ports     []int             `yaml:"ports"`     // Ports the project listens on
+	Setup     []string          `yaml:"setup"`     // Commands run in the workspace once the daemon is installed
+	Test      []string          `yaml:"test"`      // Commands that test the project
+	Lint      []string          `yaml:"lint"`      // Commands that lint the project
+
+	// Path is the file the configuration was loaded from
+	Path string `yaml:"-"`
+}
+
+// Resources is the resource allocation of a sandbox
+type Resources struct {
+	CPU    int32 `yaml:"cpu"`
+	Memory int32 `yaml:"memory"` // MB
+	Disk   int32 `yaml:"disk"`   // GB
+}
+
+// LoadConfig reads the configuration file in dir. It returns nil if the
+// directory has no configuration file.
+func LoadConfig(dir string) (*Config, error) {
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		utils.DebugPrintf("Loading project configuration from %s\n", path)
+		return ParseConfig(data, path)
+	}
+
+	return nil, nil
+}
+
+// ParseConfig parses and validates the contents of the configuration file at
+// path, which may also be a path inside a sandbox
+func ParseConfig(data []byte, path string) (*Config, error) {
+	config := &Config{Path: path}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Validate checks the configuration for values that cannot be applied
+func (c *Config) Validate() error {
+	if c.Resources.CPU < 0 || c.Resources.Memory < 0 || c.Resources.Disk < 0 {
+		return fmt.Errorf("resources must not be negative")
+	}
+
+	for _, port := range c.Ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("port %d is out of range", port)
+		}
+	}
+
+	for key := range c.Env {
+		if key == "" {
+			return fmt.Errorf("environment variable names must not be empty")
+		}
+	}
+
+	return nil
+}
+
+// Apply fills the options that were not set, so values given on the command
+// line take precedence over the configuration file
+func (c *Config) Apply(opts *sandbox.CreateOptions) {
+	if opts.Snapshot == "" {
+		opts.Snapshot = c.Snapshot
+	}
+	if opts.CPU == 0 {
+		opts.CPU = c.Resources.CPU
+	}
+	if opts.Memory == 0 {
+		opts.Memory = c.Resources.Memory
+	}
+	if opts.Disk == 0 {
+		opts.Disk = c.Resources.Disk
+	}
+	if opts.Group == "" {
+		opts.Group = c.Group
+	}
+	if opts.Model == "" {
+		opts.Model = c.Model
+	}
+
+	if len(c.Env) > 0 {
+		env := make(map[string]string, len(c.Env)+len(opts.Env))
+		for key, value := range c.Env {
+			env[key] = value
+		}
+		for key, value := range opts.Env {
+			env[key] = value
+		}
+		opts.Env = env
+	}
+
+	if len(opts.Ports) == 0 {
+		opts.Ports = c.Ports
+	}
+	c.ApplyCommands(opts)
+}
+
+// ApplyCommands fills the project commands that were not set. It is all that
+// still applies once the sandbox has been created.
+func (c *Config) ApplyCommands(opts *sandbox.CreateOptions) {
+	if len(opts.SetupCommands) == 0 {
+		opts.SetupCommands = c.Setup
+	}
+	if len(opts.TestCommands) == 0 {
+		opts.TestCommands = c.Test
+	}
+	if len(opts.LintCommands) == 0 {
+		opts.LintCommands = c.Lint
+	}
+}
@@ -0,0 +1,165 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cli/pkg/sandbox"
+)
+
+// writeConfigFiles writes configuration files into a new directory
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{".dispense.yaml": `
+snapshot: node:20
+resources: {cpu: 2, memory: 4096}
+env: {NODE_ENV: development}
+ports: [3000]
+setup: [cp .env.example .env]
+test: [npm test]
+lint: [npm run lint]
+`})
+
+	config, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	want := &Config{
+		Snapshot:  "node:20",
+		Resources: Resources{CPU: 2, Memory: 4096},
+		Env:       map[string]string{"NODE_ENV": "development"},
+		Ports:     []int{3000},
+		Setup:     []string{"cp .env.example .env"},
+		Test:      []string{"npm test"},
+		Lint:      []string{"npm run lint"},
+		Path:      filepath.Join(dir, ".dispense.yaml"),
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("LoadConfig() = %+v, want %+v", config, want)
+	}
+}
+
+func TestLoadConfigFileNames(t *testing.T) {
+	if config, err := LoadConfig(t.TempDir()); config != nil || err != nil {
+		t.Errorf("LoadConfig() without a file = %+v, %v, want nil", config, err)
+	}
+
+	config, err := LoadConfig(writeConfigFiles(t, map[string]string{".dispense.yml": "group: yml"}))
+	if err != nil || config.Group != "yml" {
+		t.Errorf("LoadConfig() of .dispense.yml = %+v, %v", config, err)
+	}
+
+	config, err = LoadConfig(writeConfigFiles(t, map[string]string{".dispense.yaml": "group: yaml", ".dispense.yml": "group: yml"}))
+	if err != nil || config.Group != "yaml" {
+		t.Errorf("LoadConfig() with both files = %+v, %v, want .dispense.yaml to be preferred", config, err)
+	}
+}
+
+func TestLoadConfigRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]string{
+		"ports: [3000":           "failed to parse",
+		"ports: [70000]":         "port 70000 is out of range",
+		"resources: {disk: -1}":  "must not be negative",
+		"env: {\"\": value}":     "must not be empty",
+		"resources: {cpu: many}": "failed to parse",
+	}
+
+	for content, wantErr := range tests {
+		_, err := LoadConfig(writeConfigFiles(t, map[string]string{".dispense.yaml": content}))
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("LoadConfig() of %q error = %v, want %q", content, err, wantErr)
+		}
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	config := &Config{
+		Snapshot:  "node:20",
+		Resources: Resources{CPU: 2, Memory: 4096, Disk: 20},
+		Group:     "frontend",
+		Model:     "sonnet",
+		Env:       map[string]string{"NODE_ENV": "development", "PORT": "3000"},
+		Ports:     []int{3000},
+		Setup:     []string{"npm run db:migrate"},
+		Test:      []string{"npm test"},
+		Lint:      []string{"npm run lint"},
+	}
+
+	// The configuration fills the options that were not set
+	opts := &sandbox.CreateOptions{}
+	config.Apply(opts)
+	want := &sandbox.CreateOptions{
+		Snapshot:      "node:20",
+		CPU:           2,
+		Memory:        4096,
+		Disk:          20,
+		Group:         "frontend",
+		Model:         "sonnet",
+		Env:           map[string]string{"NODE_ENV": "development", "PORT": "3000"},
+		Ports:         []int{3000},
+		SetupCommands: []string{"npm run db:migrate"},
+		TestCommands:  []string{"npm test"},
+		LintCommands:  []string{"npm run lint"},
+	}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("Apply() to empty options = %+v, want %+v", opts, want)
+	}
+
+	// Values given on the command line take precedence, environment variables are merged
+	opts = &sandbox.CreateOptions{
+		Snapshot:     "node:22",
+		CPU:          4,
+		Group:        "backend",
+		Env:          map[string]string{"NODE_ENV": "test", "DEBUG": "1"},
+		Ports:        []int{8080},
+		TestCommands: []string{"make test"},
+	}
+	config.Apply(opts)
+	want = &sandbox.CreateOptions{
+		Snapshot:      "node:22",
+		CPU:           4,
+		Memory:        4096,
+		Disk:          20,
+		Group:         "backend",
+		Model:         "sonnet",
+		Env:           map[string]string{"NODE_ENV": "test", "PORT": "3000", "DEBUG": "1"},
+		Ports:         []int{8080},
+		SetupCommands: []string{"npm run db:migrate"},
+		TestCommands:  []string{"make test"},
+		LintCommands:  []string{"npm run lint"},
+	}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("Apply() to set options = %+v, want %+v", opts, want)
+	}
+	if config.Env["NODE_ENV"] != "development" {
+		t.Error("Apply() changed the configuration's environment")
+	}
+}
+
+func TestConfigApplyCommands(t *testing.T) {
+	config, err := ParseConfig([]byte("snapshot: node:20\nports: [3000]\nsetup: [make setup]\ntest: [make test]\n"), "/workspace/.dispense.yaml")
+	if err != nil {
+		t.Fatalf("ParseConfig() failed: %v", err)
+	}
+
+	// Only the commands apply to a sandbox that already exists
+	opts := &sandbox.CreateOptions{TestCommands: []string{"go test ./..."}}
+	config.ApplyCommands(opts)
+	want := &sandbox.CreateOptions{SetupCommands: []string{"make setup"}, TestCommands: []string{"go test ./..."}}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("ApplyCommands() = %+v, want %+v", opts, want)
+	}
+}
@@ -0,0 +1,60 @@
+package project
+
+import (
+	"context"
+
+	pb "cli/proto"
+
+	"workspacediff"
+)
+
+// fileDiffStatus maps how a file changed to its status in the daemon protocol
+var fileDiffStatus = map[workspacediff.Status]pb.FileDiff_Status{
+	workspacediff.Modified:    pb.FileDiff_MODIFIED,
+	workspacediff.Added:       pb.FileDiff_ADDED,
+	workspacediff.Deleted:     pb.FileDiff_DELETED,
+	workspacediff.Renamed:     pb.FileDiff_RENAMED,
+	workspacediff.Untracked:   pb.FileDiff_UNTRACKED,
+	workspacediff.TypeChanged: pb.FileDiff_TYPE_CHANGED,
+}
+
+// WorkspaceDiff returns the changes in the git repository at dir since
+// baseRef, or since the commit the workspace started from if baseRef is
+// empty. It is the host side of the daemon's GetWorkspaceDiff for local
+// sandboxes whose project is a git worktree, which git cannot open inside the
+// container, and computes the diff the same way the daemon does.
+func WorkspaceDiff(ctx context.Context, dir, baseRef string, includePatches bool) (*pb.WorkspaceDiffResponse, error) {
+	diff, err := workspacediff.Compute(ctx, dir, baseRef, includePatches)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.WorkspaceDiffResponse{
+		BaseCommit: diff.BaseCommit,
+		HeadCommit: diff.HeadCommit,
+		Branch:     diff.Branch,
+	}
+	for _, file := range diff.Files {
+		resp.Files = append(resp.Files, &pb.FileDiff{
+			Path:           file.Path,
+			OldPath:        file.OldPath,
+			Status:         fileDiffStatus[file.Status],
+			Additions:      int32(file.Additions),
+			Deletions:      int32(file.Deletions),
+			Binary:         file.Binary,
+			Patch:          file.Patch,
+			PatchTruncated: file.PatchTruncated,
+		})
+	}
+	return resp, nil
+}
+
+// HeadCommit returns the commit checked out in the git repository at dir
+func HeadCommit(dir string) (string, error) {
+	return workspacediff.HeadCommit(context.Background(), dir)
+}
+
+// IsGitWorktree reports whether dir is a git worktree of a repository on the host
+func IsGitWorktree(dir string) bool {
+	return isGitWorktree(dir)
+}
@@ -2,6 +2,9 @@ package sandbox
 
 import (
 	"fmt"
+	"path"
+	"strings"
+	"time"
 )
 
 // SandboxType represents the type of sandbox
@@ -30,6 +33,73 @@ type CreateOptions struct {
 	GitHubIssue  bool    // Indicates if this is for a GitHub issue (affects project setup)
 	Group        string  // Optional group parameter for organizing sandboxes
 	Model        string  // Optional model parameter
+	TaskTimeout  time.Duration // Default time limit for Claude tasks, 0 for no limit
+	IdleTimeout  time.Duration // Default time a Claude task may go without output, 0 for no limit
+
+	// Project settings, usually read from the repository's .dispense.yaml
+	Env           map[string]string // Environment variables of the sandbox
+	Ports         []int             // Ports the project listens on
+	SetupCommands []string          // Commands run in the workspace once the daemon is installed
+	TestCommands  []string          // Commands that test the project
+	LintCommands  []string          // Commands that lint the project
+}
+
+// DaemonEnv returns the environment variables that configure the daemon of a
+// sandbox created with these options
+func (o *CreateOptions) DaemonEnv() map[string]string {
+	env := make(map[string]string)
+	if o.TaskTimeout > 0 {
+		env["DISPENSE_TASK_TIMEOUT"] = o.TaskTimeout.String()
+	}
+	if o.IdleTimeout > 0 {
+		env["DISPENSE_IDLE_TIMEOUT"] = o.IdleTimeout.String()
+	}
+	return env
+}
+
+// SandboxEnv returns the environment variables of a sandbox created with
+// these options, the daemon settings taking precedence over Env
+func (o *CreateOptions) SandboxEnv() map[string]string {
+	env := make(map[string]string)
+	for key, value := range o.Env {
+		env[key] = value
+	}
+	for key, value := range o.DaemonEnv() {
+		env[key] = value
+	}
+	return env
+}
+
+// StopDaemonCommand asks the daemon of a sandbox to shut down and waits up to
+// 30 seconds for it to stop its running tasks and finish its requests, killing
+// it if it does not exit in time. Persisted task history is kept.
+const StopDaemonCommand = "pkill -TERM -x dispensed; " +
+	"for i in $(seq 1 30); do pgrep -x dispensed > /dev/null || exit 0; sleep 1; done; " +
+	"pkill -KILL -x dispensed; sleep 1"
+
+// WaitDaemonReadyCommand waits up to 30 seconds for the daemon of a sandbox to
+// report through its health service that it is serving, failing otherwise
+const WaitDaemonReadyCommand = "/usr/local/bin/dispensed --wait-ready 30s"
+
+// GitCredentialLifetime is how long git in a sandbox can use the GitHub token
+// given to it with SetGitHubCredentials
+const GitCredentialLifetime = 12 * time.Hour
+
+// GitCredentialHelperPath is where SetGitHubCredentials installs the git
+// credential helper, relative to the home directory of the sandbox's user
+const GitCredentialHelperPath = ".dispense/git-credential-github"
+
+// ConfigureGitCredentialHelperCommand makes git ask the credential helper at
+// GitCredentialHelperPath for github.com credentials. It is set in the user's
+// global git config, so it is never written to the .git/config of a workspace.
+const ConfigureGitCredentialHelperCommand = `chmod 700 "$HOME/` + GitCredentialHelperPath + `" && ` +
+	`git config --global credential.https://github.com.helper "$HOME/` + GitCredentialHelperPath + `"`
+
+// WriteHomeFileCommand returns a shell command that writes the file read from
+// source, "-" for standard input, to filePath relative to the home directory
+// of the sandbox's user, readable only by that user
+func WriteHomeFileCommand(source, filePath string) string {
+	return fmt.Sprintf(`umask 077 && mkdir -p "$HOME/%s" && cat %s > "$HOME/%s"`, path.Dir(filePath), source, filePath)
 }
 
 // SandboxInfo contains information about a created sandbox
@@ -60,8 +130,23 @@ type Provider interface {
 	// InstallDaemon installs the embedded daemon to the sandbox
 	InstallDaemon(sandboxInfo *SandboxInfo) error
 
-	// CloneGitHubRepo clones a GitHub repository into the sandbox workspace
-	CloneGitHubRepo(sandboxInfo *SandboxInfo, owner, repo, branchName string) error
+	// UpgradeDaemon replaces the daemon running in the sandbox with the
+	// embedded one and restarts it
+	UpgradeDaemon(sandboxInfo *SandboxInfo) error
+
+	// CloneGitHubRepo clones a GitHub repository into the sandbox workspace and creates branchName
+	// from the head of pull request pullRequest, or from the default branch if it is 0
+	CloneGitHubRepo(sandboxInfo *SandboxInfo, owner, repo, branchName string, pullRequest int) error
+
+	// WriteHomeFile writes content to path relative to the home directory of
+	// the sandbox's user, readable only by that user. The content is never part
+	// of a command run in the sandbox, so it can hold secrets.
+	WriteHomeFile(sandboxInfo *SandboxInfo, path string, content []byte) error
+
+	// SetGitHubCredentials gives git in the sandbox a GitHub token for
+	// GitCredentialLifetime through a credential helper, replacing the token
+	// it was given before
+	SetGitHubCredentials(sandboxInfo *SandboxInfo, token string) error
 
 	// GetInfo retrieves information about a sandbox
 	GetInfo(id string) (*SandboxInfo, error)
@@ -83,6 +168,22 @@ type Provider interface {
 
 	// ExecuteCommand executes a command in the sandbox and returns the result
 	ExecuteCommand(sandboxInfo *SandboxInfo, command string) (*ExecResult, error)
+
+	// DownloadFile copies a file from the sandbox to localPath on this machine
+	DownloadFile(sandboxInfo *SandboxInfo, remotePath, localPath string) error
+}
+
+// DetectArchitecture returns the machine architecture of a sandbox as reported
+// by uname -m, such as x86_64 or aarch64
+func DetectArchitecture(provider Provider, sandboxInfo *SandboxInfo) (string, error) {
+	result, err := provider.ExecuteCommand(sandboxInfo, "uname -m")
+	if err != nil {
+		return "", fmt.Errorf("failed to detect sandbox architecture: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("failed to detect sandbox architecture: uname -m exited with code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return strings.TrimSpace(result.Stdout), nil
 }
 
 // These will be implemented by importing the specific provider packages
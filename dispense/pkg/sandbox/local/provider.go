@@ -1,6 +1,7 @@
 package local
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,6 +10,7 @@ import (
 
 	"cli/pkg/daemon"
 	"cli/pkg/database"
+	"cli/pkg/github"
 	"cli/pkg/project"
 	"cli/pkg/sandbox"
 	"cli/pkg/utils"
@@ -127,6 +129,15 @@ func (p *Provider) Create(opts *sandbox.CreateOptions) (*sandbox.SandboxInfo, er
 		metadata["model"] = opts.Model
 	}
 
+	// Record the commit the worktree started from, dispense diff compares with it
+	if project.IsGitWorktree(projectPath) {
+		if baseCommit, err := project.HeadCommit(projectPath); err == nil {
+			metadata["base_commit"] = baseCommit
+		} else {
+			utils.DebugPrintf("Warning: Failed to get base commit of worktree: %s\n", err)
+		}
+	}
+
 	sandboxInfo := &sandbox.SandboxInfo{
 		ID:           opts.BranchName, // Use user-friendly branch name as ID
 		Name:         opts.BranchName, // Use user-friendly branch name as name
@@ -164,6 +175,18 @@ func (p *Provider) CopyFiles(sandboxInfo *sandbox.SandboxInfo, localPath string)
 
 // InstallDaemon installs the embedded daemon to the local sandbox
 func (p *Provider) InstallDaemon(sandboxInfo *sandbox.SandboxInfo) error {
+	return p.installDaemon(sandboxInfo, false)
+}
+
+// UpgradeDaemon replaces the daemon in the local sandbox with the embedded one
+// and restarts it, keeping its task history
+func (p *Provider) UpgradeDaemon(sandboxInfo *sandbox.SandboxInfo) error {
+	return p.installDaemon(sandboxInfo, true)
+}
+
+// installDaemon copies the embedded daemon into the container and starts it,
+// stopping the daemon that is already running first if restart is set
+func (p *Provider) installDaemon(sandboxInfo *sandbox.SandboxInfo, restart bool) error {
 	utils.DebugPrintf("Installing daemon to local sandbox %s\n", sandboxInfo.ID)
 
 	// Get container ID from metadata
@@ -180,9 +203,22 @@ func (p *Provider) InstallDaemon(sandboxInfo *sandbox.SandboxInfo) error {
 
 	fmt.Printf("🔧 Installing daemon in container: %s\n", containerName)
 
+	// Pick the daemon built for the container's architecture
+	arch, err := sandbox.DetectArchitecture(p, sandboxInfo)
+	if err != nil {
+		return err
+	}
+	embeddedDaemon, err := daemon.NewEmbeddedDaemonForArch(arch)
+	if err != nil {
+		return err
+	}
+	if embeddedDaemon.Size() == 0 {
+		return fmt.Errorf("no embedded daemon binary found for %s", embeddedDaemon.Arch())
+	}
+	utils.DebugPrintf("Using daemon for %s (container architecture: %s)\n", embeddedDaemon.Arch(), arch)
+
 	// Extract the embedded daemon binary
-	embeddedDaemon := daemon.NewEmbeddedDaemon()
-	err := embeddedDaemon.Extract()
+	err = embeddedDaemon.Extract()
 	if err != nil {
 		return fmt.Errorf("failed to extract daemon binary: %w", err)
 	}
@@ -204,8 +240,11 @@ func (p *Provider) InstallDaemon(sandboxInfo *sandbox.SandboxInfo) error {
 		"chmod +x /tmp/dispensed",                       // Make executable
 		"sudo mv /tmp/dispensed /usr/local/bin/dispensed", // Move to system path
 		"which dispensed", // Verify installation
-		"nohup /usr/local/bin/dispensed > /dev/null 2>&1 &", // Start daemon in background
 	}
+	if restart {
+		commands = append(commands, sandbox.StopDaemonCommand) // Stop the old daemon, it keeps running from the replaced binary
+	}
+	commands = append(commands, "nohup /usr/local/bin/dispensed --supervise > /dev/null 2>&1 &") // Start daemon in background, restarted if it crashes
 
 	for _, cmd := range commands {
 		if err := p.execInContainer(containerID, cmd); err != nil {
@@ -213,25 +252,19 @@ func (p *Provider) InstallDaemon(sandboxInfo *sandbox.SandboxInfo) error {
 		}
 	}
 
-	// Give the daemon a few seconds to start up after being launched with nohup
+	// Wait until the daemon reports through its health service that it is serving
 	utils.DebugPrintf("Waiting for daemon to start up...\n")
-	time.Sleep(5 * time.Second)
-
-	// Verify daemon process is running
-	checkCmd := "pgrep dispensed"
-	if err := p.execInContainer(containerID, checkCmd); err != nil {
-		utils.DebugPrintf("Warning: Daemon process may not be running: %s\n", err)
-		// Don't fail here as daemon might still be starting
-	} else {
-		utils.DebugPrintf("Daemon process confirmed running\n")
+	if err := p.execInContainer(containerID, sandbox.WaitDaemonReadyCommand); err != nil {
+		return fmt.Errorf("daemon did not become ready (see ~/.dispense/dispensed.log in the sandbox): %w", err)
 	}
+	utils.DebugPrintf("Daemon is ready\n")
 
 	utils.DebugPrintf("Daemon installed successfully as /usr/local/bin/dispensed\n")
 	return nil
 }
 
 // CloneGitHubRepo clones a GitHub repository directly into the container's /workspace
-func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo, branchName string) error {
+func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo, branchName string, pullRequest int) error {
 	utils.DebugPrintf("Cloning GitHub repo %s/%s into sandbox %s\n", owner, repo, sandboxInfo.ID)
 
 	// Get container ID from metadata
@@ -255,8 +288,11 @@ func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
 
-	// Create and checkout a new branch named after the sandbox
+	// Create and checkout a new branch named after the sandbox, starting from the pull request's head if there is one
 	branchCmd := fmt.Sprintf("cd /workspace && git checkout -b %s", branchName)
+	if pullRequest > 0 {
+		branchCmd = fmt.Sprintf("cd /workspace && git fetch origin pull/%d/head && git checkout -b %s FETCH_HEAD", pullRequest, branchName)
+	}
 	if err := p.execInContainer(containerID, branchCmd); err != nil {
 		return fmt.Errorf("failed to create branch %s: %w", branchName, err)
 	}
@@ -265,6 +301,48 @@ func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo
 	return nil
 }
 
+// WriteHomeFile writes a file to the home directory of the container's user. The content is passed
+// through docker exec's standard input, so it is not part of any command.
+func (p *Provider) WriteHomeFile(sandboxInfo *sandbox.SandboxInfo, path string, content []byte) error {
+	utils.DebugPrintf("Writing ~/%s in sandbox %s\n", path, sandboxInfo.ID)
+
+	// Get container ID from metadata
+	containerID, ok := sandboxInfo.Metadata["container_id"].(string)
+	if !ok {
+		return fmt.Errorf("container ID not found in sandbox metadata")
+	}
+
+	cmd := exec.Command("docker", "exec", "-i", containerID, "/bin/sh", "-c", sandbox.WriteHomeFileCommand("-", path))
+	cmd.Stdin = bytes.NewReader(content)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to write ~/%s: %w\nOutput: %s", path, err, string(output))
+	}
+
+	return nil
+}
+
+// SetGitHubCredentials installs a git credential helper with the GitHub token in the container
+func (p *Provider) SetGitHubCredentials(sandboxInfo *sandbox.SandboxInfo, token string) error {
+	utils.DebugPrintf("Setting GitHub credentials in sandbox %s\n", sandboxInfo.ID)
+
+	helper := github.GitCredentialHelper(token, time.Now().Add(sandbox.GitCredentialLifetime))
+	if err := p.WriteHomeFile(sandboxInfo, sandbox.GitCredentialHelperPath, []byte(helper)); err != nil {
+		return fmt.Errorf("failed to install git credential helper: %w", err)
+	}
+
+	// Get container ID from metadata
+	containerID, ok := sandboxInfo.Metadata["container_id"].(string)
+	if !ok {
+		return fmt.Errorf("container ID not found in sandbox metadata")
+	}
+	if err := p.execInContainer(containerID, sandbox.ConfigureGitCredentialHelperCommand); err != nil {
+		return fmt.Errorf("failed to configure git credential helper: %w", err)
+	}
+
+	return nil
+}
+
 // GetInfo retrieves information about a local sandbox
 func (p *Provider) GetInfo(id string) (*sandbox.SandboxInfo, error) {
 	utils.DebugPrintf("Getting info for local sandbox %s\n", id)
@@ -439,8 +517,6 @@ func (p *Provider) createContainer(containerName, projectPath string, opts *sand
 		"--label", "dispense.sandbox=true",
 		"--label", fmt.Sprintf("dispense.name=%s", opts.BranchName),
 		"--label", "dispense.type=local",
-		imageName,
-		// "/bin/bash", "-c", "while true; do sleep 30; done", // Keep container running
 	}
 
 	// Add group label if specified
@@ -456,6 +532,20 @@ func (p *Provider) createContainer(containerName, projectPath string, opts *sand
 		args = append(args, "--memory", fmt.Sprintf("%dM", opts.Memory))
 	}
 
+	// Pass the project environment and daemon settings such as task timeouts
+	for key, value := range opts.SandboxEnv() {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	// Publish project ports on random local ports, several sandboxes of the
+	// same project may run at once
+	for _, port := range opts.Ports {
+		args = append(args, "-p", fmt.Sprintf("127.0.0.1::%d", port))
+	}
+
+	// Options must come before the image, anything after it is passed to the container
+	args = append(args, imageName)
+
 	cmd := exec.Command("docker", args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -833,6 +923,25 @@ func (p *Provider) ExecuteCommand(sandboxInfo *sandbox.SandboxInfo, command stri
 	return result, nil
 }
 
+// DownloadFile copies a file from the local sandbox container to localPath
+func (p *Provider) DownloadFile(sandboxInfo *sandbox.SandboxInfo, remotePath, localPath string) error {
+	utils.DebugPrintf("Downloading %s from local sandbox %s to %s\n", remotePath, sandboxInfo.ID, localPath)
+
+	// Get container ID from metadata
+	containerID, ok := sandboxInfo.Metadata["container_id"].(string)
+	if !ok {
+		return fmt.Errorf("container ID not found in sandbox metadata")
+	}
+
+	cmd := exec.Command("docker", "cp", fmt.Sprintf("%s:%s", containerID, remotePath), localPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to copy file from container: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
 // execCommandWithOutput executes a command and captures stdout, stderr, and exit code
 func (p *Provider) execCommandWithOutput(cmd *exec.Cmd) (string, string, int, error) {
 	var stdout, stderr strings.Builder
@@ -16,6 +16,7 @@ import (
 	"apiclient"
 	"cli/pkg/client"
 	"cli/pkg/daemon"
+	"cli/pkg/github"
 	"cli/pkg/sandbox"
 	"cli/pkg/utils"
 
@@ -66,7 +67,7 @@ func (p *Provider) Create(opts *sandbox.CreateOptions) (*sandbox.SandboxInfo, er
 	utils.DebugPrintf("Generated slug: %s\n", slug)
 
 	// Create sandbox with the slug as dispense-name label
-	remoteSandbox, err := p.createSandboxWithLabel(slug, opts.Snapshot, opts.Target, opts.CPU, opts.Memory, opts.Disk, opts.AutoStop, opts.Group, opts.Model)
+	remoteSandbox, err := p.createSandboxWithLabel(slug, opts.Snapshot, opts.Target, opts.CPU, opts.Memory, opts.Disk, opts.AutoStop, opts.Group, opts.Model, opts.SandboxEnv())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create remote sandbox: %w", err)
 	}
@@ -86,6 +87,11 @@ func (p *Provider) Create(opts *sandbox.CreateOptions) (*sandbox.SandboxInfo, er
 		metadata["model"] = opts.Model
 	}
 
+	// Daytona proxies sandbox ports through preview URLs instead of publishing them
+	if portURLs := p.portPreviewURLs(remoteSandbox.Id, opts.Ports); len(portURLs) > 0 {
+		metadata["port_urls"] = portURLs
+	}
+
 	// Convert to our SandboxInfo format
 	sandboxInfo := &sandbox.SandboxInfo{
 		ID:           remoteSandbox.Id,
@@ -99,6 +105,32 @@ func (p *Provider) Create(opts *sandbox.CreateOptions) (*sandbox.SandboxInfo, er
 	return sandboxInfo, nil
 }
 
+// portPreviewURLs returns the preview URL of each port. The token private
+// sandboxes need is left out, so it does not end up in the sandbox metadata;
+// GetPortPreview returns it on request.
+func (p *Provider) portPreviewURLs(sandboxID string, ports []int) map[int]string {
+	portURLs := make(map[int]string)
+	for _, port := range ports {
+		preview, err := p.apiClient.GetPortPreviewURL(sandboxID, port)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Port %d is not reachable from outside the sandbox: %v\n", port, err)
+			continue
+		}
+		portURLs[port] = preview.Url
+	}
+	return portURLs
+}
+
+// GetPortPreview returns the preview URL of a sandbox port and the token to send
+// in the x-daytona-preview-token header for private sandboxes
+func (p *Provider) GetPortPreview(sandboxInfo *sandbox.SandboxInfo, port int) (string, string, error) {
+	preview, err := p.apiClient.GetPortPreviewURL(sandboxInfo.ID, port)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get preview URL of port %d: %w", port, err)
+	}
+	return preview.Url, preview.Token, nil
+}
+
 // CopyFiles copies files from local directory to remote sandbox
 func (p *Provider) CopyFiles(sandboxInfo *sandbox.SandboxInfo, localPath string) error {
 	utils.DebugPrintf("Copying files to remote sandbox %s from %s\n", sandboxInfo.ID, localPath)
@@ -185,20 +217,39 @@ func (p *Provider) CopyClaudeConfig(sandboxInfo *sandbox.SandboxInfo) error {
 
 // InstallDaemon installs the embedded daemon to the remote sandbox
 func (p *Provider) InstallDaemon(sandboxInfo *sandbox.SandboxInfo) error {
+	return p.installDaemon(sandboxInfo, false)
+}
+
+// UpgradeDaemon replaces the daemon in the remote sandbox with the embedded one
+// and restarts it, keeping its task history
+func (p *Provider) UpgradeDaemon(sandboxInfo *sandbox.SandboxInfo) error {
+	return p.installDaemon(sandboxInfo, true)
+}
+
+// installDaemon uploads the embedded daemon to the remote sandbox and starts
+// it, stopping the daemon that is already running first if restart is set
+func (p *Provider) installDaemon(sandboxInfo *sandbox.SandboxInfo, restart bool) error {
 	utils.DebugPrintf("Installing daemon to remote sandbox %s\n", sandboxInfo.ID)
 
-	// Create embedded daemon instance
-	embeddedDaemon := daemon.NewEmbeddedDaemon()
+	// Create embedded daemon instance for the sandbox's architecture
+	arch, err := sandbox.DetectArchitecture(p, sandboxInfo)
+	if err != nil {
+		return err
+	}
+	embeddedDaemon, err := daemon.NewEmbeddedDaemonForArch(arch)
+	if err != nil {
+		return err
+	}
 
 	// Check if daemon is available
 	if embeddedDaemon.Size() == 0 {
-		return fmt.Errorf("no embedded daemon binary found")
+		return fmt.Errorf("no embedded daemon binary found for %s", embeddedDaemon.Arch())
 	}
 
-	utils.DebugPrintf("Installing embedded daemon (%d bytes) to sandbox %s\n", embeddedDaemon.Size(), sandboxInfo.ID)
+	utils.DebugPrintf("Installing embedded daemon for %s (%d bytes) to sandbox %s (architecture: %s)\n", embeddedDaemon.Arch(), embeddedDaemon.Size(), sandboxInfo.ID, arch)
 
 	// Extract daemon binary locally first
-	err := embeddedDaemon.Extract()
+	err = embeddedDaemon.Extract()
 	if err != nil {
 		return fmt.Errorf("failed to extract daemon binary: %w", err)
 	}
@@ -214,7 +265,7 @@ func (p *Provider) InstallDaemon(sandboxInfo *sandbox.SandboxInfo) error {
 
 	// Move daemon to /usr/local/bin and make executable using API
 	utils.DebugPrintf("Installing daemon to /usr/local/bin/dispensed\n")
-	err = p.installDaemonRemotely(sandboxInfo.ID, tempDaemonPath, "/usr/local/bin/dispensed")
+	err = p.installDaemonRemotely(sandboxInfo.ID, tempDaemonPath, "/usr/local/bin/dispensed", restart)
 	if err != nil {
 		return fmt.Errorf("failed to install daemon: %w", err)
 	}
@@ -247,6 +298,16 @@ func (p *Provider) GetInfo(id string) (*sandbox.SandboxInfo, error) {
 		},
 	}
 
+	// Expose group and model labels the same way local sandboxes do
+	if remoteSandbox.Labels != nil {
+		if group := remoteSandbox.Labels["dispense-group"]; group != "" {
+			sandboxInfo.Metadata["group"] = group
+		}
+		if model := remoteSandbox.Labels["dispense-model"]; model != "" {
+			sandboxInfo.Metadata["model"] = model
+		}
+	}
+
 	return sandboxInfo, nil
 }
 
@@ -285,7 +346,7 @@ func (p *Provider) Delete(id string) error {
 
 // Helper methods (extracted from default.go)
 
-func (p *Provider) createSandboxWithLabel(dispenseName, snapshot, target string, cpu, memory, disk, autoStop int32, group, model string) (*apiclient.Sandbox, error) {
+func (p *Provider) createSandboxWithLabel(dispenseName, snapshot, target string, cpu, memory, disk, autoStop int32, group, model string, sandboxEnv map[string]string) (*apiclient.Sandbox, error) {
 	// Set default values if not provided
 	if snapshot == "" {
 		snapshot = "dispense-sandbox-001" // Default dispense snapshot
@@ -314,6 +375,11 @@ func (p *Provider) createSandboxWithLabel(dispenseName, snapshot, target string,
 		"DISPENSE_NAME": dispenseName,
 	}
 
+	// Add the project environment and daemon settings such as task timeouts
+	for key, value := range sandboxEnv {
+		env[key] = value
+	}
+
 	// Create the sandbox request
 	createSandbox := apiclient.NewCreateSandbox()
 	createSandbox.SetSnapshot(snapshot)
@@ -755,8 +821,17 @@ func (p *Provider) extractGitBundleRemotely(sandboxId, remoteBundlePath, remoteW
     return err
 }
 
-// installDaemonRemotely installs daemon binary via API client and starts it
-func (p *Provider) installDaemonRemotely(sandboxId, tempPath, finalPath string) error {
+// daemonCommandTimeout is how long the commands stopping the daemon and waiting
+// for it to be ready may run, which wait up to 30 seconds themselves
+const daemonCommandTimeout = 45 * time.Second
+
+// daemonListenAddress keeps the daemon of a remote sandbox off the network, it
+// is only reached through SSH port forwarding
+const daemonListenAddress = "127.0.0.1:28080"
+
+// installDaemonRemotely installs daemon binary via API client and starts it,
+// stopping the daemon that is already running first if restart is set
+func (p *Provider) installDaemonRemotely(sandboxId, tempPath, finalPath string, restart bool) error {
 	commands := []string{
 		fmt.Sprintf("chmod +x %s", tempPath),
 		fmt.Sprintf("sudo mv %s %s", tempPath, finalPath),
@@ -770,29 +845,31 @@ func (p *Provider) installDaemonRemotely(sandboxId, tempPath, finalPath string)
 		}
 	}
 
-	// Start the daemon in the background using the installed path
-	utils.DebugPrintf("Running daemon asynchronously: %s\n", finalPath)
-
-	err := p.apiClient.RunAsyncCommand(sandboxId, finalPath)
-	if err != nil {
-		return fmt.Errorf("failed to start daemon asynchronously: %w", err)
+	// The old daemon keeps running from the replaced binary until it is stopped
+	if restart {
+		utils.DebugPrintf("Stopping the running daemon\n")
+		_, err := p.apiClient.RunCommandWithTimeout(sandboxId, fmt.Sprintf("sh -c '%s'", sandbox.StopDaemonCommand), "", daemonCommandTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to stop the running daemon: %w", err)
+		}
 	}
 
-	// Wait a moment for daemon to start
-	time.Sleep(3 * time.Second)
+	// Start the daemon in the background using the installed path, under its
+	// supervisor so that it is restarted if it crashes
+	command := fmt.Sprintf("%s --supervise --listen %s", finalPath, daemonListenAddress)
+	utils.DebugPrintf("Running daemon asynchronously: %s\n", command)
 
-	// Verify daemon is running by checking if port 28080 is listening
-	portCheckCmd := "netstat -plunt | grep :28080 || echo 'Port 28080 not listening'"
-	utils.DebugPrintf("Checking daemon port with command: %s\n", portCheckCmd)
-	response, err := p.apiClient.RunCommand(sandboxId, portCheckCmd, "")
+	err := p.apiClient.RunAsyncCommand(sandboxId, command)
 	if err != nil {
-		utils.DebugPrintf("Daemon port check failed: %v\n", err)
-		return fmt.Errorf("failed to check daemon port: %w", err)
+		return fmt.Errorf("failed to start daemon asynchronously: %w", err)
 	}
-	utils.DebugPrintf("Daemon port check result: %s\n", response.Output)
 
-	if strings.Contains(response.Output, "Port 28080 not listening") {
-		return fmt.Errorf("daemon failed to start - port 28080 not listening")
+	// Wait until the daemon reports through its health service that it is serving
+	utils.DebugPrintf("Waiting for daemon with command: %s\n", sandbox.WaitDaemonReadyCommand)
+	response, err := p.apiClient.RunCommandWithTimeout(sandboxId, sandbox.WaitDaemonReadyCommand, "", daemonCommandTimeout)
+	if err != nil {
+		utils.DebugPrintf("Daemon readiness check failed: %v\n", err)
+		return fmt.Errorf("daemon did not become ready (see ~/.dispense/dispensed.log in the sandbox): %w", err)
 	}
 
 	utils.DebugPrintf("Daemon verification: %s\n", response.Output)
@@ -979,7 +1056,7 @@ func (p *Provider) ExecuteShell(sandboxInfo *sandbox.SandboxInfo) error {
 }
 
 // CloneGitHubRepo clones a GitHub repository into the remote sandbox workspace
-func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo, branchName string) error {
+func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo, branchName string, pullRequest int) error {
 	utils.DebugPrintf("Cloning GitHub repo %s/%s into remote sandbox %s\n", owner, repo, sandboxInfo.ID)
 
 	repoURL := fmt.Sprintf("https://github.com/%s/%s.git", owner, repo)
@@ -1019,8 +1096,18 @@ func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
 
+    // Fetch the head of the pull request, which also works for pull requests from forks
+    startPoint := ""
+    if pullRequest > 0 {
+        fetchCmd := fmt.Sprintf("%s fetch origin pull/%d/head", gitPath, pullRequest)
+        if _, err := p.apiClient.RunCommand(sandboxInfo.ID, fetchCmd, remoteWorkspacePath); err != nil {
+            return fmt.Errorf("failed to fetch pull request #%d: %w", pullRequest, err)
+        }
+        startPoint = " FETCH_HEAD"
+    }
+
     // Create and checkout a new branch named after the sandbox
-    branchCmd := fmt.Sprintf("%s checkout -b %s", gitPath, branchName)
+    branchCmd := fmt.Sprintf("%s checkout -b %s%s", gitPath, branchName, startPoint)
 	if _, err := p.apiClient.RunCommand(sandboxInfo.ID, branchCmd, remoteWorkspacePath); err != nil {
 		return fmt.Errorf("failed to create branch %s: %w", branchName, err)
 	}
@@ -1029,6 +1116,57 @@ func (p *Provider) CloneGitHubRepo(sandboxInfo *sandbox.SandboxInfo, owner, repo
 	return nil
 }
 
+// WriteHomeFile writes a file to the home directory of the remote sandbox's user. The content is
+// uploaded as a file, so it is not part of any command sent to the sandbox.
+func (p *Provider) WriteHomeFile(sandboxInfo *sandbox.SandboxInfo, path string, content []byte) error {
+	utils.DebugPrintf("Writing ~/%s in remote sandbox %s\n", path, sandboxInfo.ID)
+
+	// Write the content to a temporary file only the current user can read
+	tempFile, err := os.CreateTemp("", "dispense-home-file-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	remoteTempPath := "/tmp/" + filepath.Base(tempFile.Name())
+	if err := p.apiClient.UploadFile(sandboxInfo.ID, tempFile.Name(), remoteTempPath); err != nil {
+		return fmt.Errorf("failed to upload ~/%s: %w", path, err)
+	}
+
+	// The uploaded copy is removed whether or not it could be moved into place
+	writeCmd := fmt.Sprintf("sh -c 'trap \"rm -f %s\" EXIT; %s'", remoteTempPath, sandbox.WriteHomeFileCommand(remoteTempPath, path))
+	if _, err := p.executeCommand(sandboxInfo.ID, writeCmd, ""); err != nil {
+		return fmt.Errorf("failed to write ~/%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// SetGitHubCredentials installs a git credential helper with the GitHub token in the remote sandbox
+func (p *Provider) SetGitHubCredentials(sandboxInfo *sandbox.SandboxInfo, token string) error {
+	utils.DebugPrintf("Setting GitHub credentials in remote sandbox %s\n", sandboxInfo.ID)
+
+	helper := github.GitCredentialHelper(token, time.Now().Add(sandbox.GitCredentialLifetime))
+	if err := p.WriteHomeFile(sandboxInfo, sandbox.GitCredentialHelperPath, []byte(helper)); err != nil {
+		return fmt.Errorf("failed to install git credential helper: %w", err)
+	}
+
+	configureCmd := fmt.Sprintf("sh -c '%s'", sandbox.ConfigureGitCredentialHelperCommand)
+	if _, err := p.executeCommand(sandboxInfo.ID, configureCmd, ""); err != nil {
+		return fmt.Errorf("failed to configure git credential helper: %w", err)
+	}
+
+	return nil
+}
+
 // executeCommand executes a command via Daytona API
 func (p *Provider) executeCommand(sandboxId, command, cwd string) (string, error) {
 	utils.DebugPrintf("Executing command: %s\n", command)
@@ -1200,4 +1338,14 @@ func (p *Provider) ExecuteCommand(sandboxInfo *sandbox.SandboxInfo, command stri
 
 	utils.DebugPrintf("Command completed with exit code: %d\n", response.ExitCode)
 	return result, nil
-}
\ No newline at end of file
+}
+
+// DownloadFile downloads a file from the remote sandbox to localPath using the Daytona toolbox API
+func (p *Provider) DownloadFile(sandboxInfo *sandbox.SandboxInfo, remotePath, localPath string) error {
+	utils.DebugPrintf("Downloading %s from remote sandbox %s to %s\n", remotePath, sandboxInfo.ID, localPath)
+
+	if err := p.apiClient.DownloadFile(sandboxInfo.ID, remotePath, localPath); err != nil {
+		return fmt.Errorf("failed to download %s: %w", remotePath, err)
+	}
+	return nil
+}
@@ -12,10 +12,10 @@ import (
 // DebugMode is the global debug flag
 var DebugMode bool
 
-// DebugPrintf prints debug messages only when debug mode is enabled
+// DebugPrintf prints debug messages only when debug mode is enabled, with secrets masked
 func DebugPrintf(format string, args ...interface{}) {
 	if DebugMode {
-		fmt.Printf("DEBUG: "+format, args...)
+		fmt.Print(Redact(fmt.Sprintf("DEBUG: "+format, args...)))
 	}
 }
 
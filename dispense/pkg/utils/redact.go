@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// redactedText replaces secrets in output
+const redactedText = "[REDACTED]"
+
+// secretPatterns match values that look like secrets. The first group is
+// kept so that the kind of secret is still visible. The daemon masks its log
+// with the same patterns.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(sk-ant-)[A-Za-z0-9_-]{8,}`),
+	regexp.MustCompile(`\b(sk-)[A-Za-z0-9_-]{20,}`),
+	regexp.MustCompile(`\b(gh[pousr]_)[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`\b(github_pat_)[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`\b(AKIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]{16,}`),
+	regexp.MustCompile(`(?i)(basic\s+)[A-Za-z0-9+/=]{16,}`),
+	regexp.MustCompile(`(?i)((?:api[_-]?key|token|secret|password)["']?\s*[:=]\s*["']?)[^\s"',]{8,}`),
+}
+
+// Redact masks anything in text that looks like a secret
+func Redact(text string) string {
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+redactedText)
+	}
+	return text
+}
+
+// MaskSecret describes a secret without revealing it, for telling the user
+// which of their keys is used
+func MaskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return fmt.Sprintf("%d characters", len(secret))
+	}
+	return fmt.Sprintf("...%s, %d characters", secret[len(secret)-4:], len(secret))
+}
+
+// redactingWriter masks secrets in everything written through it
+type redactingWriter struct {
+	out io.Writer
+}
+
+// NewRedactingWriter returns a writer that masks secrets before writing to
+// out. Loggers write a line per call, so secrets are not split across writes.
+func NewRedactingWriter(out io.Writer) io.Writer {
+	return &redactingWriter{out: out}
+}
+
+// Write implements io.Writer
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write([]byte(Redact(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -21,28 +21,150 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProjectInitResponse_ResponseType int32
+
+const (
+	ProjectInitResponse_STATUS ProjectInitResponse_ResponseType = 0 // A step is starting
+	ProjectInitResponse_OUTPUT ProjectInitResponse_ResponseType = 1 // Output of the running step
+	ProjectInitResponse_ERROR  ProjectInitResponse_ResponseType = 2 // A step failed
+	ProjectInitResponse_DONE   ProjectInitResponse_ResponseType = 3 // Initialization finished
+)
+
+// Enum value maps for ProjectInitResponse_ResponseType.
+var (
+	ProjectInitResponse_ResponseType_name = map[int32]string{
+		0: "STATUS",
+		1: "OUTPUT",
+		2: "ERROR",
+		3: "DONE",
+	}
+	ProjectInitResponse_ResponseType_value = map[string]int32{
+		"STATUS": 0,
+		"OUTPUT": 1,
+		"ERROR":  2,
+		"DONE":   3,
+	}
+)
+
+func (x ProjectInitResponse_ResponseType) Enum() *ProjectInitResponse_ResponseType {
+	p := new(ProjectInitResponse_ResponseType)
+	*p = x
+	return p
+}
+
+func (x ProjectInitResponse_ResponseType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProjectInitResponse_ResponseType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_daemon_proto_enumTypes[0].Descriptor()
+}
+
+func (ProjectInitResponse_ResponseType) Type() protoreflect.EnumType {
+	return &file_proto_daemon_proto_enumTypes[0]
+}
+
+func (x ProjectInitResponse_ResponseType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProjectInitResponse_ResponseType.Descriptor instead.
+func (ProjectInitResponse_ResponseType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type LogsResponse_Level int32
+
+const (
+	LogsResponse_INFO  LogsResponse_Level = 0
+	LogsResponse_WARN  LogsResponse_Level = 1
+	LogsResponse_ERROR LogsResponse_Level = 2
+)
+
+// Enum value maps for LogsResponse_Level.
+var (
+	LogsResponse_Level_name = map[int32]string{
+		0: "INFO",
+		1: "WARN",
+		2: "ERROR",
+	}
+	LogsResponse_Level_value = map[string]int32{
+		"INFO":  0,
+		"WARN":  1,
+		"ERROR": 2,
+	}
+)
+
+func (x LogsResponse_Level) Enum() *LogsResponse_Level {
+	p := new(LogsResponse_Level)
+	*p = x
+	return p
+}
+
+func (x LogsResponse_Level) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogsResponse_Level) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_daemon_proto_enumTypes[1].Descriptor()
+}
+
+func (LogsResponse_Level) Type() protoreflect.EnumType {
+	return &file_proto_daemon_proto_enumTypes[1]
+}
+
+func (x LogsResponse_Level) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogsResponse_Level.Descriptor instead.
+func (LogsResponse_Level) EnumDescriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{5, 0}
+}
+
 type ExecuteClaudeResponse_ResponseType int32
 
 const (
-	ExecuteClaudeResponse_STDOUT ExecuteClaudeResponse_ResponseType = 0
-	ExecuteClaudeResponse_STDERR ExecuteClaudeResponse_ResponseType = 1
-	ExecuteClaudeResponse_STATUS ExecuteClaudeResponse_ResponseType = 2
-	ExecuteClaudeResponse_ERROR  ExecuteClaudeResponse_ResponseType = 3
+	ExecuteClaudeResponse_STDOUT       ExecuteClaudeResponse_ResponseType = 0
+	ExecuteClaudeResponse_STDERR       ExecuteClaudeResponse_ResponseType = 1
+	ExecuteClaudeResponse_STATUS       ExecuteClaudeResponse_ResponseType = 2
+	ExecuteClaudeResponse_ERROR        ExecuteClaudeResponse_ResponseType = 3
+	ExecuteClaudeResponse_ASSISTANT    ExecuteClaudeResponse_ResponseType = 4  // Text written by Claude
+	ExecuteClaudeResponse_TOOL_USE     ExecuteClaudeResponse_ResponseType = 5  // Claude invoked a tool
+	ExecuteClaudeResponse_TOOL_RESULT  ExecuteClaudeResponse_ResponseType = 6  // A tool returned its result to Claude
+	ExecuteClaudeResponse_USAGE        ExecuteClaudeResponse_ResponseType = 7  // Token usage and cost of the session
+	ExecuteClaudeResponse_RESULT       ExecuteClaudeResponse_ResponseType = 8  // Final result of the session
+	ExecuteClaudeResponse_SYSTEM       ExecuteClaudeResponse_ResponseType = 9  // Session information such as the session ID and model
+	ExecuteClaudeResponse_VERIFICATION ExecuteClaudeResponse_ResponseType = 10 // Output of a verification command
 )
 
 // Enum value maps for ExecuteClaudeResponse_ResponseType.
 var (
 	ExecuteClaudeResponse_ResponseType_name = map[int32]string{
-		0: "STDOUT",
-		1: "STDERR",
-		2: "STATUS",
-		3: "ERROR",
+		0:  "STDOUT",
+		1:  "STDERR",
+		2:  "STATUS",
+		3:  "ERROR",
+		4:  "ASSISTANT",
+		5:  "TOOL_USE",
+		6:  "TOOL_RESULT",
+		7:  "USAGE",
+		8:  "RESULT",
+		9:  "SYSTEM",
+		10: "VERIFICATION",
 	}
 	ExecuteClaudeResponse_ResponseType_value = map[string]int32{
-		"STDOUT": 0,
-		"STDERR": 1,
-		"STATUS": 2,
-		"ERROR":  3,
+		"STDOUT":       0,
+		"STDERR":       1,
+		"STATUS":       2,
+		"ERROR":        3,
+		"ASSISTANT":    4,
+		"TOOL_USE":     5,
+		"TOOL_RESULT":  6,
+		"USAGE":        7,
+		"RESULT":       8,
+		"SYSTEM":       9,
+		"VERIFICATION": 10,
 	}
 )
 
@@ -57,11 +179,11 @@ func (x ExecuteClaudeResponse_ResponseType) String() string {
 }
 
 func (ExecuteClaudeResponse_ResponseType) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_daemon_proto_enumTypes[0].Descriptor()
+	return file_proto_daemon_proto_enumTypes[2].Descriptor()
 }
 
 func (ExecuteClaudeResponse_ResponseType) Type() protoreflect.EnumType {
-	return &file_proto_daemon_proto_enumTypes[0]
+	return &file_proto_daemon_proto_enumTypes[2]
 }
 
 func (x ExecuteClaudeResponse_ResponseType) Number() protoreflect.EnumNumber {
@@ -70,16 +192,20 @@ func (x ExecuteClaudeResponse_ResponseType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ExecuteClaudeResponse_ResponseType.Descriptor instead.
 func (ExecuteClaudeResponse_ResponseType) EnumDescriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{7, 0}
+	return file_proto_daemon_proto_rawDescGZIP(), []int{9, 0}
 }
 
 type TaskStatusResponse_TaskState int32
 
 const (
-	TaskStatusResponse_PENDING   TaskStatusResponse_TaskState = 0
-	TaskStatusResponse_RUNNING   TaskStatusResponse_TaskState = 1
-	TaskStatusResponse_COMPLETED TaskStatusResponse_TaskState = 2
-	TaskStatusResponse_FAILED    TaskStatusResponse_TaskState = 3
+	TaskStatusResponse_PENDING             TaskStatusResponse_TaskState = 0
+	TaskStatusResponse_RUNNING             TaskStatusResponse_TaskState = 1
+	TaskStatusResponse_COMPLETED           TaskStatusResponse_TaskState = 2
+	TaskStatusResponse_FAILED              TaskStatusResponse_TaskState = 3
+	TaskStatusResponse_CANCELLED           TaskStatusResponse_TaskState = 4
+	TaskStatusResponse_INTERRUPTED         TaskStatusResponse_TaskState = 5
+	TaskStatusResponse_TIMED_OUT           TaskStatusResponse_TaskState = 6
+	TaskStatusResponse_VERIFICATION_FAILED TaskStatusResponse_TaskState = 7 // Claude finished but a verification command failed
 )
 
 // Enum value maps for TaskStatusResponse_TaskState.
@@ -89,12 +215,20 @@ var (
 		1: "RUNNING",
 		2: "COMPLETED",
 		3: "FAILED",
+		4: "CANCELLED",
+		5: "INTERRUPTED",
+		6: "TIMED_OUT",
+		7: "VERIFICATION_FAILED",
 	}
 	TaskStatusResponse_TaskState_value = map[string]int32{
-		"PENDING":   0,
-		"RUNNING":   1,
-		"COMPLETED": 2,
-		"FAILED":    3,
+		"PENDING":             0,
+		"RUNNING":             1,
+		"COMPLETED":           2,
+		"FAILED":              3,
+		"CANCELLED":           4,
+		"INTERRUPTED":         5,
+		"TIMED_OUT":           6,
+		"VERIFICATION_FAILED": 7,
 	}
 )
 
@@ -109,11 +243,11 @@ func (x TaskStatusResponse_TaskState) String() string {
 }
 
 func (TaskStatusResponse_TaskState) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_daemon_proto_enumTypes[1].Descriptor()
+	return file_proto_daemon_proto_enumTypes[3].Descriptor()
 }
 
 func (TaskStatusResponse_TaskState) Type() protoreflect.EnumType {
-	return &file_proto_daemon_proto_enumTypes[1]
+	return &file_proto_daemon_proto_enumTypes[3]
 }
 
 func (x TaskStatusResponse_TaskState) Number() protoreflect.EnumNumber {
@@ -122,15 +256,77 @@ func (x TaskStatusResponse_TaskState) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use TaskStatusResponse_TaskState.Descriptor instead.
 func (TaskStatusResponse_TaskState) EnumDescriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{9, 0}
+	return file_proto_daemon_proto_rawDescGZIP(), []int{17, 0}
+}
+
+type FileDiff_Status int32
+
+const (
+	FileDiff_MODIFIED     FileDiff_Status = 0
+	FileDiff_ADDED        FileDiff_Status = 1
+	FileDiff_DELETED      FileDiff_Status = 2
+	FileDiff_RENAMED      FileDiff_Status = 3
+	FileDiff_UNTRACKED    FileDiff_Status = 4 // New file that is not committed or staged
+	FileDiff_TYPE_CHANGED FileDiff_Status = 5
+)
+
+// Enum value maps for FileDiff_Status.
+var (
+	FileDiff_Status_name = map[int32]string{
+		0: "MODIFIED",
+		1: "ADDED",
+		2: "DELETED",
+		3: "RENAMED",
+		4: "UNTRACKED",
+		5: "TYPE_CHANGED",
+	}
+	FileDiff_Status_value = map[string]int32{
+		"MODIFIED":     0,
+		"ADDED":        1,
+		"DELETED":      2,
+		"RENAMED":      3,
+		"UNTRACKED":    4,
+		"TYPE_CHANGED": 5,
+	}
+)
+
+func (x FileDiff_Status) Enum() *FileDiff_Status {
+	p := new(FileDiff_Status)
+	*p = x
+	return p
+}
+
+func (x FileDiff_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FileDiff_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_daemon_proto_enumTypes[4].Descriptor()
+}
+
+func (FileDiff_Status) Type() protoreflect.EnumType {
+	return &file_proto_daemon_proto_enumTypes[4]
+}
+
+func (x FileDiff_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FileDiff_Status.Descriptor instead.
+func (FileDiff_Status) EnumDescriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{32, 0}
 }
 
 // Common request/response types
 type InitRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectType   string                 `protobuf:"bytes,1,opt,name=project_type,json=projectType,proto3" json:"project_type,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ProjectType      string                 `protobuf:"bytes,1,opt,name=project_type,json=projectType,proto3" json:"project_type,omitempty"`                // Toolchain to set up (go, node, python or rust), detected from the project files if empty
+	WorkingDirectory string                 `protobuf:"bytes,2,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"` // Project directory, defaults to /workspace
+	SetupCommands    []string               `protobuf:"bytes,3,rep,name=setup_commands,json=setupCommands,proto3" json:"setup_commands,omitempty"`          // Run before the toolchains are set up, e.g. from .dispense.yaml
+	TestCommands     []string               `protobuf:"bytes,4,rep,name=test_commands,json=testCommands,proto3" json:"test_commands,omitempty"`             // Replace the detected test commands
+	LintCommands     []string               `protobuf:"bytes,5,rep,name=lint_commands,json=lintCommands,proto3" json:"lint_commands,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *InitRequest) Reset() {
@@ -170,10 +366,39 @@ func (x *InitRequest) GetProjectType() string {
 	return ""
 }
 
+func (x *InitRequest) GetWorkingDirectory() string {
+	if x != nil {
+		return x.WorkingDirectory
+	}
+	return ""
+}
+
+func (x *InitRequest) GetSetupCommands() []string {
+	if x != nil {
+		return x.SetupCommands
+	}
+	return nil
+}
+
+func (x *InitRequest) GetTestCommands() []string {
+	if x != nil {
+		return x.TestCommands
+	}
+	return nil
+}
+
+func (x *InitRequest) GetLintCommands() []string {
+	if x != nil {
+		return x.LintCommands
+	}
+	return nil
+}
+
 type InitResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Project       *ProjectInfo           `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"` // Project recorded by ProjectService.Init, unset if it has not run
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -222,26 +447,40 @@ func (x *InitResponse) GetMessage() string {
 	return ""
 }
 
-type LogsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *InitResponse) GetProject() *ProjectInfo {
+	if x != nil {
+		return x.Project
+	}
+	return nil
+}
+
+// ProjectInitResponse streams the progress of a project initialization
+type ProjectInitResponse struct {
+	state         protoimpl.MessageState           `protogen:"open.v1"`
+	Type          ProjectInitResponse_ResponseType `protobuf:"varint,1,opt,name=type,proto3,enum=daemon.ProjectInitResponse_ResponseType" json:"type,omitempty"`
+	Content       string                           `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Timestamp     int64                            `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	IsFinished    bool                             `protobuf:"varint,4,opt,name=is_finished,json=isFinished,proto3" json:"is_finished,omitempty"` // Set on the DONE response
+	Success       bool                             `protobuf:"varint,5,opt,name=success,proto3" json:"success,omitempty"`                         // Only set on the DONE response, false if any step failed
+	Project       *ProjectInfo                     `protobuf:"bytes,6,opt,name=project,proto3" json:"project,omitempty"`                          // Only set on the DONE response
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LogsRequest) Reset() {
-	*x = LogsRequest{}
+func (x *ProjectInitResponse) Reset() {
+	*x = ProjectInitResponse{}
 	mi := &file_proto_daemon_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LogsRequest) String() string {
+func (x *ProjectInitResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogsRequest) ProtoMessage() {}
+func (*ProjectInitResponse) ProtoMessage() {}
 
-func (x *LogsRequest) ProtoReflect() protoreflect.Message {
+func (x *ProjectInitResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_daemon_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -253,90 +492,82 @@ func (x *LogsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogsRequest.ProtoReflect.Descriptor instead.
-func (*LogsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProjectInitResponse.ProtoReflect.Descriptor instead.
+func (*ProjectInitResponse) Descriptor() ([]byte, []int) {
 	return file_proto_daemon_proto_rawDescGZIP(), []int{2}
 }
 
-type LogsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LogEntry      string                 `protobuf:"bytes,1,opt,name=log_entry,json=logEntry,proto3" json:"log_entry,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *LogsResponse) Reset() {
-	*x = LogsResponse{}
-	mi := &file_proto_daemon_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ProjectInitResponse) GetType() ProjectInitResponse_ResponseType {
+	if x != nil {
+		return x.Type
+	}
+	return ProjectInitResponse_STATUS
 }
 
-func (x *LogsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ProjectInitResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
 }
 
-func (*LogsResponse) ProtoMessage() {}
-
-func (x *LogsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[3]
+func (x *ProjectInitResponse) GetTimestamp() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Timestamp
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use LogsResponse.ProtoReflect.Descriptor instead.
-func (*LogsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{3}
+func (x *ProjectInitResponse) GetIsFinished() bool {
+	if x != nil {
+		return x.IsFinished
+	}
+	return false
 }
 
-func (x *LogsResponse) GetLogEntry() string {
+func (x *ProjectInitResponse) GetSuccess() bool {
 	if x != nil {
-		return x.LogEntry
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *LogsResponse) GetTimestamp() int64 {
+func (x *ProjectInitResponse) GetProject() *ProjectInfo {
 	if x != nil {
-		return x.Timestamp
+		return x.Project
 	}
-	return 0
+	return nil
 }
 
-// CreateTaskRequest for creating new tasks
-type CreateTaskRequest struct {
+// ProjectInfo describes the toolchains detected in a project and how to work with it
+type ProjectInfo struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
-	Prompt           string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
-	WorkingDirectory string                 `protobuf:"bytes,2,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
-	EnvironmentVars  map[string]string      `protobuf:"bytes,3,rep,name=environment_vars,json=environmentVars,proto3" json:"environment_vars,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	AnthropicApiKey  string                 `protobuf:"bytes,4,opt,name=anthropic_api_key,json=anthropicApiKey,proto3" json:"anthropic_api_key,omitempty"`
-	Model            string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	WorkingDirectory string                 `protobuf:"bytes,1,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	Toolchains       []string               `protobuf:"bytes,2,rep,name=toolchains,proto3" json:"toolchains,omitempty"` // e.g. go, node, python or rust
+	InstallCommands  []string               `protobuf:"bytes,3,rep,name=install_commands,json=installCommands,proto3" json:"install_commands,omitempty"`
+	BuildCommands    []string               `protobuf:"bytes,4,rep,name=build_commands,json=buildCommands,proto3" json:"build_commands,omitempty"`
+	TestCommands     []string               `protobuf:"bytes,5,rep,name=test_commands,json=testCommands,proto3" json:"test_commands,omitempty"`
+	SetupCommands    []string               `protobuf:"bytes,6,rep,name=setup_commands,json=setupCommands,proto3" json:"setup_commands,omitempty"`
+	LintCommands     []string               `protobuf:"bytes,7,rep,name=lint_commands,json=lintCommands,proto3" json:"lint_commands,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
 
-func (x *CreateTaskRequest) Reset() {
-	*x = CreateTaskRequest{}
-	mi := &file_proto_daemon_proto_msgTypes[4]
+func (x *ProjectInfo) Reset() {
+	*x = ProjectInfo{}
+	mi := &file_proto_daemon_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTaskRequest) String() string {
+func (x *ProjectInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTaskRequest) ProtoMessage() {}
+func (*ProjectInfo) ProtoMessage() {}
 
-func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[4]
+func (x *ProjectInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -347,70 +578,87 @@ func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
-func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ProjectInfo.ProtoReflect.Descriptor instead.
+func (*ProjectInfo) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *CreateTaskRequest) GetPrompt() string {
+func (x *ProjectInfo) GetWorkingDirectory() string {
 	if x != nil {
-		return x.Prompt
+		return x.WorkingDirectory
 	}
 	return ""
 }
 
-func (x *CreateTaskRequest) GetWorkingDirectory() string {
+func (x *ProjectInfo) GetToolchains() []string {
 	if x != nil {
-		return x.WorkingDirectory
+		return x.Toolchains
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateTaskRequest) GetEnvironmentVars() map[string]string {
+func (x *ProjectInfo) GetInstallCommands() []string {
 	if x != nil {
-		return x.EnvironmentVars
+		return x.InstallCommands
 	}
 	return nil
 }
 
-func (x *CreateTaskRequest) GetAnthropicApiKey() string {
+func (x *ProjectInfo) GetBuildCommands() []string {
 	if x != nil {
-		return x.AnthropicApiKey
+		return x.BuildCommands
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateTaskRequest) GetModel() string {
+func (x *ProjectInfo) GetTestCommands() []string {
 	if x != nil {
-		return x.Model
+		return x.TestCommands
 	}
-	return ""
+	return nil
 }
 
-type CreateTaskResponse struct {
+func (x *ProjectInfo) GetSetupCommands() []string {
+	if x != nil {
+		return x.SetupCommands
+	}
+	return nil
+}
+
+func (x *ProjectInfo) GetLintCommands() []string {
+	if x != nil {
+		return x.LintCommands
+	}
+	return nil
+}
+
+// LogsRequest filters the daemon log stream. Entries retained from before the
+// request are sent first, then new entries as they happen if follow is set.
+type LogsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`                                       // Only events of this task, daemon log lines are skipped
+	MinLevel      LogsResponse_Level     `protobuf:"varint,2,opt,name=min_level,json=minLevel,proto3,enum=daemon.LogsResponse_Level" json:"min_level,omitempty"` // Only entries at or above this level
+	Since         int64                  `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`                                                      // Only entries at or after this Unix timestamp
+	Follow        bool                   `protobuf:"varint,4,opt,name=follow,proto3" json:"follow,omitempty"`                                                    // Keep streaming new entries until the client disconnects
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTaskResponse) Reset() {
-	*x = CreateTaskResponse{}
-	mi := &file_proto_daemon_proto_msgTypes[5]
+func (x *LogsRequest) Reset() {
+	*x = LogsRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTaskResponse) String() string {
+func (x *LogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTaskResponse) ProtoMessage() {}
+func (*LogsRequest) ProtoMessage() {}
 
-func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[5]
+func (x *LogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -421,58 +669,149 @@ func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
-func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use LogsRequest.ProtoReflect.Descriptor instead.
+func (*LogsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateTaskResponse) GetSuccess() bool {
+func (x *LogsRequest) GetTaskId() string {
 	if x != nil {
-		return x.Success
+		return x.TaskId
 	}
-	return false
+	return ""
 }
 
-func (x *CreateTaskResponse) GetTaskId() string {
+func (x *LogsRequest) GetMinLevel() LogsResponse_Level {
 	if x != nil {
-		return x.TaskId
+		return x.MinLevel
 	}
-	return ""
+	return LogsResponse_INFO
 }
 
-func (x *CreateTaskResponse) GetMessage() string {
+func (x *LogsRequest) GetSince() int64 {
 	if x != nil {
-		return x.Message
+		return x.Since
 	}
-	return ""
+	return 0
 }
 
-// ExecuteClaudeRequest for running Claude with a prompt
-type ExecuteClaudeRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Prompt           string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
-	WorkingDirectory string                 `protobuf:"bytes,2,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
-	EnvironmentVars  map[string]string      `protobuf:"bytes,3,rep,name=environment_vars,json=environmentVars,proto3" json:"environment_vars,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	AnthropicApiKey  string                 `protobuf:"bytes,4,opt,name=anthropic_api_key,json=anthropicApiKey,proto3" json:"anthropic_api_key,omitempty"`
-	Model            string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *LogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
 }
 
-func (x *ExecuteClaudeRequest) Reset() {
-	*x = ExecuteClaudeRequest{}
+// LogsResponse is a daemon log line or a task lifecycle event
+type LogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LogEntry      string                 `protobuf:"bytes,1,opt,name=log_entry,json=logEntry,proto3" json:"log_entry,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level         LogsResponse_Level     `protobuf:"varint,3,opt,name=level,proto3,enum=daemon.LogsResponse_Level" json:"level,omitempty"`
+	TaskId        string                 `protobuf:"bytes,4,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"` // Set for task events
+	Event         string                 `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`                 // Task event: created, started, output or finished
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogsResponse) Reset() {
+	*x = LogsResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogsResponse) ProtoMessage() {}
+
+func (x *LogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogsResponse.ProtoReflect.Descriptor instead.
+func (*LogsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LogsResponse) GetLogEntry() string {
+	if x != nil {
+		return x.LogEntry
+	}
+	return ""
+}
+
+func (x *LogsResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LogsResponse) GetLevel() LogsResponse_Level {
+	if x != nil {
+		return x.Level
+	}
+	return LogsResponse_INFO
+}
+
+func (x *LogsResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *LogsResponse) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+// CreateTaskRequest for creating new tasks
+type CreateTaskRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Prompt             string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	WorkingDirectory   string                 `protobuf:"bytes,2,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	EnvironmentVars    map[string]string      `protobuf:"bytes,3,rep,name=environment_vars,json=environmentVars,proto3" json:"environment_vars,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	AnthropicApiKey    string                 `protobuf:"bytes,4,opt,name=anthropic_api_key,json=anthropicApiKey,proto3" json:"anthropic_api_key,omitempty"` // Deprecated: set the key once with SetCredentials and use credentials_name
+	Model              string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	ResumeTaskId       string                 `protobuf:"bytes,6,opt,name=resume_task_id,json=resumeTaskId,proto3" json:"resume_task_id,omitempty"`                    // Continue the Claude session of this task
+	ContinueSession    bool                   `protobuf:"varint,7,opt,name=continue_session,json=continueSession,proto3" json:"continue_session,omitempty"`            // Continue the Claude session of the most recent task
+	TimeoutSeconds     int32                  `protobuf:"varint,8,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`               // Kill the task after this many seconds, 0 uses the daemon default
+	IdleTimeoutSeconds int32                  `protobuf:"varint,9,opt,name=idle_timeout_seconds,json=idleTimeoutSeconds,proto3" json:"idle_timeout_seconds,omitempty"` // Kill the task after this many seconds without output, 0 uses the daemon default
+	VerifyCommands     []string               `protobuf:"bytes,10,rep,name=verify_commands,json=verifyCommands,proto3" json:"verify_commands,omitempty"`               // Run in the working directory once Claude exits, the task fails verification if one fails
+	MaxFixAttempts     int32                  `protobuf:"varint,11,opt,name=max_fix_attempts,json=maxFixAttempts,proto3" json:"max_fix_attempts,omitempty"`            // Follow-up Claude runs that fix failed verification commands, 0 disables them
+	CredentialsName    string                 `protobuf:"bytes,12,opt,name=credentials_name,json=credentialsName,proto3" json:"credentials_name,omitempty"`            // Credentials stored with SetCredentials to run with, empty uses "default"
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
 	mi := &file_proto_daemon_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecuteClaudeRequest) String() string {
+func (x *CreateTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecuteClaudeRequest) ProtoMessage() {}
+func (*CreateTaskRequest) ProtoMessage() {}
 
-func (x *ExecuteClaudeRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_daemon_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -484,72 +823,118 @@ func (x *ExecuteClaudeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecuteClaudeRequest.ProtoReflect.Descriptor instead.
-func (*ExecuteClaudeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
 	return file_proto_daemon_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ExecuteClaudeRequest) GetPrompt() string {
+func (x *CreateTaskRequest) GetPrompt() string {
 	if x != nil {
 		return x.Prompt
 	}
 	return ""
 }
 
-func (x *ExecuteClaudeRequest) GetWorkingDirectory() string {
+func (x *CreateTaskRequest) GetWorkingDirectory() string {
 	if x != nil {
 		return x.WorkingDirectory
 	}
 	return ""
 }
 
-func (x *ExecuteClaudeRequest) GetEnvironmentVars() map[string]string {
+func (x *CreateTaskRequest) GetEnvironmentVars() map[string]string {
 	if x != nil {
 		return x.EnvironmentVars
 	}
 	return nil
 }
 
-func (x *ExecuteClaudeRequest) GetAnthropicApiKey() string {
+func (x *CreateTaskRequest) GetAnthropicApiKey() string {
 	if x != nil {
 		return x.AnthropicApiKey
 	}
 	return ""
 }
 
-func (x *ExecuteClaudeRequest) GetModel() string {
+func (x *CreateTaskRequest) GetModel() string {
 	if x != nil {
 		return x.Model
 	}
 	return ""
 }
 
-// ExecuteClaudeResponse streams Claude execution output
-type ExecuteClaudeResponse struct {
-	state         protoimpl.MessageState             `protogen:"open.v1"`
-	Type          ExecuteClaudeResponse_ResponseType `protobuf:"varint,1,opt,name=type,proto3,enum=daemon.ExecuteClaudeResponse_ResponseType" json:"type,omitempty"`
-	Content       string                             `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	Timestamp     int64                              `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	ExitCode      int32                              `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`       // Only set for STATUS type
-	IsFinished    bool                               `protobuf:"varint,5,opt,name=is_finished,json=isFinished,proto3" json:"is_finished,omitempty"` // Indicates if execution is complete
+func (x *CreateTaskRequest) GetResumeTaskId() string {
+	if x != nil {
+		return x.ResumeTaskId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetContinueSession() bool {
+	if x != nil {
+		return x.ContinueSession
+	}
+	return false
+}
+
+func (x *CreateTaskRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *CreateTaskRequest) GetIdleTimeoutSeconds() int32 {
+	if x != nil {
+		return x.IdleTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *CreateTaskRequest) GetVerifyCommands() []string {
+	if x != nil {
+		return x.VerifyCommands
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetMaxFixAttempts() int32 {
+	if x != nil {
+		return x.MaxFixAttempts
+	}
+	return 0
+}
+
+func (x *CreateTaskRequest) GetCredentialsName() string {
+	if x != nil {
+		return x.CredentialsName
+	}
+	return ""
+}
+
+type CreateTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExecuteClaudeResponse) Reset() {
-	*x = ExecuteClaudeResponse{}
+func (x *CreateTaskResponse) Reset() {
+	*x = CreateTaskResponse{}
 	mi := &file_proto_daemon_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecuteClaudeResponse) String() string {
+func (x *CreateTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecuteClaudeResponse) ProtoMessage() {}
+func (*CreateTaskResponse) ProtoMessage() {}
 
-func (x *ExecuteClaudeResponse) ProtoReflect() protoreflect.Message {
+func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_daemon_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -561,69 +946,1679 @@ func (x *ExecuteClaudeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecuteClaudeResponse.ProtoReflect.Descriptor instead.
-func (*ExecuteClaudeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
+func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
 	return file_proto_daemon_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ExecuteClaudeResponse) GetType() ExecuteClaudeResponse_ResponseType {
+func (x *CreateTaskResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Type
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateTaskResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CreateTaskResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ExecuteClaudeRequest for running Claude with a prompt
+type ExecuteClaudeRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Prompt             string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	WorkingDirectory   string                 `protobuf:"bytes,2,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	EnvironmentVars    map[string]string      `protobuf:"bytes,3,rep,name=environment_vars,json=environmentVars,proto3" json:"environment_vars,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	AnthropicApiKey    string                 `protobuf:"bytes,4,opt,name=anthropic_api_key,json=anthropicApiKey,proto3" json:"anthropic_api_key,omitempty"` // Deprecated: set the key once with SetCredentials and use credentials_name
+	Model              string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	ResumeTaskId       string                 `protobuf:"bytes,6,opt,name=resume_task_id,json=resumeTaskId,proto3" json:"resume_task_id,omitempty"`                    // Continue the Claude session of this task
+	ContinueSession    bool                   `protobuf:"varint,7,opt,name=continue_session,json=continueSession,proto3" json:"continue_session,omitempty"`            // Continue the Claude session of the most recent task
+	TimeoutSeconds     int32                  `protobuf:"varint,8,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`               // Kill the task after this many seconds, 0 uses the daemon default
+	IdleTimeoutSeconds int32                  `protobuf:"varint,9,opt,name=idle_timeout_seconds,json=idleTimeoutSeconds,proto3" json:"idle_timeout_seconds,omitempty"` // Kill the task after this many seconds without output, 0 uses the daemon default
+	VerifyCommands     []string               `protobuf:"bytes,10,rep,name=verify_commands,json=verifyCommands,proto3" json:"verify_commands,omitempty"`               // Run in the working directory once Claude exits, the task fails verification if one fails
+	MaxFixAttempts     int32                  `protobuf:"varint,11,opt,name=max_fix_attempts,json=maxFixAttempts,proto3" json:"max_fix_attempts,omitempty"`            // Follow-up Claude runs that fix failed verification commands, 0 disables them
+	CredentialsName    string                 `protobuf:"bytes,12,opt,name=credentials_name,json=credentialsName,proto3" json:"credentials_name,omitempty"`            // Credentials stored with SetCredentials to run with, empty uses "default"
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ExecuteClaudeRequest) Reset() {
+	*x = ExecuteClaudeRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteClaudeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteClaudeRequest) ProtoMessage() {}
+
+func (x *ExecuteClaudeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteClaudeRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteClaudeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExecuteClaudeRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *ExecuteClaudeRequest) GetWorkingDirectory() string {
+	if x != nil {
+		return x.WorkingDirectory
+	}
+	return ""
+}
+
+func (x *ExecuteClaudeRequest) GetEnvironmentVars() map[string]string {
+	if x != nil {
+		return x.EnvironmentVars
+	}
+	return nil
+}
+
+func (x *ExecuteClaudeRequest) GetAnthropicApiKey() string {
+	if x != nil {
+		return x.AnthropicApiKey
+	}
+	return ""
+}
+
+func (x *ExecuteClaudeRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ExecuteClaudeRequest) GetResumeTaskId() string {
+	if x != nil {
+		return x.ResumeTaskId
+	}
+	return ""
+}
+
+func (x *ExecuteClaudeRequest) GetContinueSession() bool {
+	if x != nil {
+		return x.ContinueSession
+	}
+	return false
+}
+
+func (x *ExecuteClaudeRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *ExecuteClaudeRequest) GetIdleTimeoutSeconds() int32 {
+	if x != nil {
+		return x.IdleTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *ExecuteClaudeRequest) GetVerifyCommands() []string {
+	if x != nil {
+		return x.VerifyCommands
+	}
+	return nil
+}
+
+func (x *ExecuteClaudeRequest) GetMaxFixAttempts() int32 {
+	if x != nil {
+		return x.MaxFixAttempts
+	}
+	return 0
+}
+
+func (x *ExecuteClaudeRequest) GetCredentialsName() string {
+	if x != nil {
+		return x.CredentialsName
+	}
+	return ""
+}
+
+// ExecuteClaudeResponse streams Claude execution output
+type ExecuteClaudeResponse struct {
+	state         protoimpl.MessageState             `protogen:"open.v1"`
+	Type          ExecuteClaudeResponse_ResponseType `protobuf:"varint,1,opt,name=type,proto3,enum=daemon.ExecuteClaudeResponse_ResponseType" json:"type,omitempty"`
+	Content       string                             `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"` // Human readable content, for typed events a summary such as "Editing main.go"
+	Timestamp     int64                              `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ExitCode      int32                              `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`       // Only set for STATUS type
+	IsFinished    bool                               `protobuf:"varint,5,opt,name=is_finished,json=isFinished,proto3" json:"is_finished,omitempty"` // Indicates if execution is complete
+	Offset        int64                              `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`                           // Task log offset after this entry, used to resume an attach
+	TaskId        string                             `protobuf:"bytes,7,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`              // Task that produced this output
+	Event         *ClaudeEvent                       `protobuf:"bytes,8,opt,name=event,proto3" json:"event,omitempty"`                              // Structured event, set for the typed response types
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteClaudeResponse) Reset() {
+	*x = ExecuteClaudeResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteClaudeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteClaudeResponse) ProtoMessage() {}
+
+func (x *ExecuteClaudeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteClaudeResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteClaudeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExecuteClaudeResponse) GetType() ExecuteClaudeResponse_ResponseType {
+	if x != nil {
+		return x.Type
+	}
+	return ExecuteClaudeResponse_STDOUT
+}
+
+func (x *ExecuteClaudeResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ExecuteClaudeResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ExecuteClaudeResponse) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *ExecuteClaudeResponse) GetIsFinished() bool {
+	if x != nil {
+		return x.IsFinished
+	}
+	return false
+}
+
+func (x *ExecuteClaudeResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ExecuteClaudeResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ExecuteClaudeResponse) GetEvent() *ClaudeEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// ClaudeEvent is a structured event parsed from Claude's stream-json output
+type ClaudeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"` // Assistant text or final result text
+	ToolUse       *ClaudeToolUse         `protobuf:"bytes,3,opt,name=tool_use,json=toolUse,proto3" json:"tool_use,omitempty"`
+	ToolResult    *ClaudeToolResult      `protobuf:"bytes,4,opt,name=tool_result,json=toolResult,proto3" json:"tool_result,omitempty"`
+	Usage         *ClaudeUsage           `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	Result        *ClaudeResult          `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`
+	Model         string                 `protobuf:"bytes,7,opt,name=model,proto3" json:"model,omitempty"` // Only set for SYSTEM events
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaudeEvent) Reset() {
+	*x = ClaudeEvent{}
+	mi := &file_proto_daemon_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaudeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaudeEvent) ProtoMessage() {}
+
+func (x *ClaudeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaudeEvent.ProtoReflect.Descriptor instead.
+func (*ClaudeEvent) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ClaudeEvent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ClaudeEvent) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ClaudeEvent) GetToolUse() *ClaudeToolUse {
+	if x != nil {
+		return x.ToolUse
+	}
+	return nil
+}
+
+func (x *ClaudeEvent) GetToolResult() *ClaudeToolResult {
+	if x != nil {
+		return x.ToolResult
+	}
+	return nil
+}
+
+func (x *ClaudeEvent) GetUsage() *ClaudeUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *ClaudeEvent) GetResult() *ClaudeResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ClaudeEvent) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// ClaudeToolUse describes a tool invocation by Claude
+type ClaudeToolUse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	InputJson     string                 `protobuf:"bytes,3,opt,name=input_json,json=inputJson,proto3" json:"input_json,omitempty"` // Tool input as a JSON object
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaudeToolUse) Reset() {
+	*x = ClaudeToolUse{}
+	mi := &file_proto_daemon_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaudeToolUse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaudeToolUse) ProtoMessage() {}
+
+func (x *ClaudeToolUse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaudeToolUse.ProtoReflect.Descriptor instead.
+func (*ClaudeToolUse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ClaudeToolUse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClaudeToolUse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClaudeToolUse) GetInputJson() string {
+	if x != nil {
+		return x.InputJson
+	}
+	return ""
+}
+
+// ClaudeToolResult is the result a tool returned to Claude
+type ClaudeToolResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ToolUseId     string                 `protobuf:"bytes,1,opt,name=tool_use_id,json=toolUseId,proto3" json:"tool_use_id,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"` // Truncated for very large results
+	IsError       bool                   `protobuf:"varint,3,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaudeToolResult) Reset() {
+	*x = ClaudeToolResult{}
+	mi := &file_proto_daemon_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaudeToolResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaudeToolResult) ProtoMessage() {}
+
+func (x *ClaudeToolResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaudeToolResult.ProtoReflect.Descriptor instead.
+func (*ClaudeToolResult) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClaudeToolResult) GetToolUseId() string {
+	if x != nil {
+		return x.ToolUseId
+	}
+	return ""
+}
+
+func (x *ClaudeToolResult) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ClaudeToolResult) GetIsError() bool {
+	if x != nil {
+		return x.IsError
+	}
+	return false
+}
+
+// ClaudeUsage is the token usage and cost of a Claude session
+type ClaudeUsage struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	InputTokens              int64                  `protobuf:"varint,1,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens             int64                  `protobuf:"varint,2,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	CacheCreationInputTokens int64                  `protobuf:"varint,3,opt,name=cache_creation_input_tokens,json=cacheCreationInputTokens,proto3" json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int64                  `protobuf:"varint,4,opt,name=cache_read_input_tokens,json=cacheReadInputTokens,proto3" json:"cache_read_input_tokens,omitempty"`
+	TotalCostUsd             float64                `protobuf:"fixed64,5,opt,name=total_cost_usd,json=totalCostUsd,proto3" json:"total_cost_usd,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ClaudeUsage) Reset() {
+	*x = ClaudeUsage{}
+	mi := &file_proto_daemon_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaudeUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaudeUsage) ProtoMessage() {}
+
+func (x *ClaudeUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaudeUsage.ProtoReflect.Descriptor instead.
+func (*ClaudeUsage) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ClaudeUsage) GetInputTokens() int64 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetOutputTokens() int64 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetCacheCreationInputTokens() int64 {
+	if x != nil {
+		return x.CacheCreationInputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetCacheReadInputTokens() int64 {
+	if x != nil {
+		return x.CacheReadInputTokens
+	}
+	return 0
+}
+
+func (x *ClaudeUsage) GetTotalCostUsd() float64 {
+	if x != nil {
+		return x.TotalCostUsd
+	}
+	return 0
+}
+
+// ClaudeResult is the final outcome of a Claude session
+type ClaudeResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subtype       string                 `protobuf:"bytes,1,opt,name=subtype,proto3" json:"subtype,omitempty"` // e.g. "success" or "error_max_turns"
+	IsError       bool                   `protobuf:"varint,2,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,3,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	NumTurns      int32                  `protobuf:"varint,4,opt,name=num_turns,json=numTurns,proto3" json:"num_turns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaudeResult) Reset() {
+	*x = ClaudeResult{}
+	mi := &file_proto_daemon_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaudeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaudeResult) ProtoMessage() {}
+
+func (x *ClaudeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaudeResult.ProtoReflect.Descriptor instead.
+func (*ClaudeResult) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ClaudeResult) GetSubtype() string {
+	if x != nil {
+		return x.Subtype
+	}
+	return ""
+}
+
+func (x *ClaudeResult) GetIsError() bool {
+	if x != nil {
+		return x.IsError
+	}
+	return false
+}
+
+func (x *ClaudeResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ClaudeResult) GetNumTurns() int32 {
+	if x != nil {
+		return x.NumTurns
+	}
+	return 0
+}
+
+// AttachTaskRequest for joining the output of an existing task
+type AttachTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`              // Empty attaches to the most recent task
+	FromOffset    int64                  `protobuf:"varint,2,opt,name=from_offset,json=fromOffset,proto3" json:"from_offset,omitempty"` // Task log offset to replay from
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachTaskRequest) Reset() {
+	*x = AttachTaskRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachTaskRequest) ProtoMessage() {}
+
+func (x *AttachTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachTaskRequest.ProtoReflect.Descriptor instead.
+func (*AttachTaskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AttachTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AttachTaskRequest) GetFromOffset() int64 {
+	if x != nil {
+		return x.FromOffset
+	}
+	return 0
+}
+
+// TaskStatusRequest for checking task status
+type TaskStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskStatusRequest) Reset() {
+	*x = TaskStatusRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStatusRequest) ProtoMessage() {}
+
+func (x *TaskStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStatusRequest.ProtoReflect.Descriptor instead.
+func (*TaskStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TaskStatusRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// TaskStatusResponse returns current task status
+type TaskStatusResponse struct {
+	state            protoimpl.MessageState       `protogen:"open.v1"`
+	State            TaskStatusResponse_TaskState `protobuf:"varint,1,opt,name=state,proto3,enum=daemon.TaskStatusResponse_TaskState" json:"state,omitempty"`
+	Message          string                       `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ExitCode         int32                        `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	StartedAt        int64                        `protobuf:"varint,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt       int64                        `protobuf:"varint,5,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Error            string                       `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Prompt           string                       `protobuf:"bytes,7,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	WorkingDirectory string                       `protobuf:"bytes,8,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	QueuePosition    int32                        `protobuf:"varint,9,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"` // Position in the task queue (1 runs next), 0 when not queued
+	SessionId        string                       `protobuf:"bytes,10,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`             // Claude session of the task
+	ResumeTaskId     string                       `protobuf:"bytes,11,opt,name=resume_task_id,json=resumeTaskId,proto3" json:"resume_task_id,omitempty"`  // Task whose Claude session this task continues
+	Usage            *ClaudeUsage                 `protobuf:"bytes,12,opt,name=usage,proto3" json:"usage,omitempty"`                                      // Token usage and cost, set once Claude reports them at the end of a run
+	Model            string                       `protobuf:"bytes,13,opt,name=model,proto3" json:"model,omitempty"`                                      // Model requested for the task, empty for the default model
+	Verification     []*VerificationResult        `protobuf:"bytes,14,rep,name=verification,proto3" json:"verification,omitempty"`                        // Results of the verification commands run so far
+	ParentTaskId     string                       `protobuf:"bytes,15,opt,name=parent_task_id,json=parentTaskId,proto3" json:"parent_task_id,omitempty"`  // Task whose failed verification this fix attempt works on
+	Attempt          int32                        `protobuf:"varint,16,opt,name=attempt,proto3" json:"attempt,omitempty"`                                 // Number of the fix attempt, 0 for a task that is not a fix attempt
+	FixTaskIds       []string                     `protobuf:"bytes,17,rep,name=fix_task_ids,json=fixTaskIds,proto3" json:"fix_task_ids,omitempty"`        // Fix attempts started for this task, in order
+	Result           string                       `protobuf:"bytes,18,opt,name=result,proto3" json:"result,omitempty"`                                    // Final message Claude reported at the end of the run, such as a summary of its changes
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TaskStatusResponse) Reset() {
+	*x = TaskStatusResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStatusResponse) ProtoMessage() {}
+
+func (x *TaskStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStatusResponse.ProtoReflect.Descriptor instead.
+func (*TaskStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TaskStatusResponse) GetState() TaskStatusResponse_TaskState {
+	if x != nil {
+		return x.State
+	}
+	return TaskStatusResponse_PENDING
+}
+
+func (x *TaskStatusResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *TaskStatusResponse) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *TaskStatusResponse) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *TaskStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetWorkingDirectory() string {
+	if x != nil {
+		return x.WorkingDirectory
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *TaskStatusResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetResumeTaskId() string {
+	if x != nil {
+		return x.ResumeTaskId
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetUsage() *ClaudeUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *TaskStatusResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetVerification() []*VerificationResult {
+	if x != nil {
+		return x.Verification
+	}
+	return nil
+}
+
+func (x *TaskStatusResponse) GetParentTaskId() string {
+	if x != nil {
+		return x.ParentTaskId
+	}
+	return ""
+}
+
+func (x *TaskStatusResponse) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *TaskStatusResponse) GetFixTaskIds() []string {
+	if x != nil {
+		return x.FixTaskIds
+	}
+	return nil
+}
+
+func (x *TaskStatusResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+// VerificationResult is the outcome of a verification command run after Claude exits
+type VerificationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Command       string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	ExitCode      int32                  `protobuf:"varint,2,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	OutputTail    string                 `protobuf:"bytes,3,opt,name=output_tail,json=outputTail,proto3" json:"output_tail,omitempty"` // Last lines of the command's combined output
+	DurationMs    int64                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerificationResult) Reset() {
+	*x = VerificationResult{}
+	mi := &file_proto_daemon_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerificationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerificationResult) ProtoMessage() {}
+
+func (x *VerificationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerificationResult.ProtoReflect.Descriptor instead.
+func (*VerificationResult) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *VerificationResult) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *VerificationResult) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *VerificationResult) GetOutputTail() string {
+	if x != nil {
+		return x.OutputTail
+	}
+	return ""
+}
+
+func (x *VerificationResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+// ListTasksRequest for listing all tasks
+type ListTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional: filter by state
+	StateFilter   *TaskStatusResponse_TaskState `protobuf:"varint,1,opt,name=state_filter,json=stateFilter,proto3,enum=daemon.TaskStatusResponse_TaskState,oneof" json:"state_filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListTasksRequest) GetStateFilter() TaskStatusResponse_TaskState {
+	if x != nil && x.StateFilter != nil {
+		return *x.StateFilter
+	}
+	return TaskStatusResponse_PENDING
+}
+
+// TaskInfo contains information about a task
+type TaskInfo struct {
+	state            protoimpl.MessageState       `protogen:"open.v1"`
+	TaskId           string                       `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Prompt           string                       `protobuf:"bytes,2,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	State            TaskStatusResponse_TaskState `protobuf:"varint,3,opt,name=state,proto3,enum=daemon.TaskStatusResponse_TaskState" json:"state,omitempty"`
+	StartedAt        int64                        `protobuf:"varint,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt       int64                        `protobuf:"varint,5,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	ExitCode         int32                        `protobuf:"varint,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Error            string                       `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	WorkingDirectory string                       `protobuf:"bytes,8,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	QueuePosition    int32                        `protobuf:"varint,9,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"` // Position in the task queue (1 runs next), 0 when not queued
+	SessionId        string                       `protobuf:"bytes,10,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`             // Claude session of the task
+	ResumeTaskId     string                       `protobuf:"bytes,11,opt,name=resume_task_id,json=resumeTaskId,proto3" json:"resume_task_id,omitempty"`  // Task whose Claude session this task continues
+	Usage            *ClaudeUsage                 `protobuf:"bytes,12,opt,name=usage,proto3" json:"usage,omitempty"`                                      // Token usage and cost, set once Claude reports them at the end of a run
+	Model            string                       `protobuf:"bytes,13,opt,name=model,proto3" json:"model,omitempty"`                                      // Model requested for the task, empty for the default model
+	Verification     []*VerificationResult        `protobuf:"bytes,14,rep,name=verification,proto3" json:"verification,omitempty"`                        // Results of the verification commands run so far
+	ParentTaskId     string                       `protobuf:"bytes,15,opt,name=parent_task_id,json=parentTaskId,proto3" json:"parent_task_id,omitempty"`  // Task whose failed verification this fix attempt works on
+	Attempt          int32                        `protobuf:"varint,16,opt,name=attempt,proto3" json:"attempt,omitempty"`                                 // Number of the fix attempt, 0 for a task that is not a fix attempt
+	FixTaskIds       []string                     `protobuf:"bytes,17,rep,name=fix_task_ids,json=fixTaskIds,proto3" json:"fix_task_ids,omitempty"`        // Fix attempts started for this task, in order
+	Result           string                       `protobuf:"bytes,18,opt,name=result,proto3" json:"result,omitempty"`                                    // Final message Claude reported at the end of the run, such as a summary of its changes
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TaskInfo) Reset() {
+	*x = TaskInfo{}
+	mi := &file_proto_daemon_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskInfo) ProtoMessage() {}
+
+func (x *TaskInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskInfo.ProtoReflect.Descriptor instead.
+func (*TaskInfo) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TaskInfo) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetState() TaskStatusResponse_TaskState {
+	if x != nil {
+		return x.State
+	}
+	return TaskStatusResponse_PENDING
+}
+
+func (x *TaskInfo) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetWorkingDirectory() string {
+	if x != nil {
+		return x.WorkingDirectory
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetResumeTaskId() string {
+	if x != nil {
+		return x.ResumeTaskId
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetUsage() *ClaudeUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *TaskInfo) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetVerification() []*VerificationResult {
+	if x != nil {
+		return x.Verification
+	}
+	return nil
+}
+
+func (x *TaskInfo) GetParentTaskId() string {
+	if x != nil {
+		return x.ParentTaskId
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetFixTaskIds() []string {
+	if x != nil {
+		return x.FixTaskIds
+	}
+	return nil
+}
+
+func (x *TaskInfo) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+// ListTasksResponse returns list of tasks
+type ListTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*TaskInfo            `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListTasksResponse) GetTasks() []*TaskInfo {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// CancelTaskRequest for stopping a running task
+type CancelTaskRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TaskId             string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`                                        // Empty cancels the most recent task; queued tasks are removed from the queue
+	GracePeriodSeconds int32                  `protobuf:"varint,2,opt,name=grace_period_seconds,json=gracePeriodSeconds,proto3" json:"grace_period_seconds,omitempty"` // Time to wait after each signal before escalating
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CancelTaskRequest) Reset() {
+	*x = CancelTaskRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTaskRequest) ProtoMessage() {}
+
+func (x *CancelTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTaskRequest.ProtoReflect.Descriptor instead.
+func (*CancelTaskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CancelTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CancelTaskRequest) GetGracePeriodSeconds() int32 {
+	if x != nil {
+		return x.GracePeriodSeconds
+	}
+	return 0
+}
+
+// CancelTaskResponse returns the result of a cancellation
+type CancelTaskResponse struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Success       bool                         `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                       `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TaskId        string                       `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	State         TaskStatusResponse_TaskState `protobuf:"varint,4,opt,name=state,proto3,enum=daemon.TaskStatusResponse_TaskState" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTaskResponse) Reset() {
+	*x = CancelTaskResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTaskResponse) ProtoMessage() {}
+
+func (x *CancelTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTaskResponse.ProtoReflect.Descriptor instead.
+func (*CancelTaskResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CancelTaskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelTaskResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CancelTaskResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CancelTaskResponse) GetState() TaskStatusResponse_TaskState {
+	if x != nil {
+		return x.State
+	}
+	return TaskStatusResponse_PENDING
+}
+
+// MoveTaskRequest for reordering a queued task
+type MoveTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Position      int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"` // New queue position, 1 runs next
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveTaskRequest) Reset() {
+	*x = MoveTaskRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveTaskRequest) ProtoMessage() {}
+
+func (x *MoveTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveTaskRequest.ProtoReflect.Descriptor instead.
+func (*MoveTaskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MoveTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *MoveTaskRequest) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+// MoveTaskResponse returns the result of reordering a queued task
+type MoveTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	QueuePosition int32                  `protobuf:"varint,3,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveTaskResponse) Reset() {
+	*x = MoveTaskResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveTaskResponse) ProtoMessage() {}
+
+func (x *MoveTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveTaskResponse.ProtoReflect.Descriptor instead.
+func (*MoveTaskResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MoveTaskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MoveTaskResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MoveTaskResponse) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+// DaemonInfoRequest asks for the build and runtime information of the daemon
+type DaemonInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DaemonInfoRequest) Reset() {
+	*x = DaemonInfoRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DaemonInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DaemonInfoRequest) ProtoMessage() {}
+
+func (x *DaemonInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DaemonInfoRequest.ProtoReflect.Descriptor instead.
+func (*DaemonInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{26}
+}
+
+// DaemonInfoResponse describes the running daemon
+type DaemonInfoResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Version            string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	GitCommit          string                 `protobuf:"bytes,2,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
+	BuildTime          string                 `protobuf:"bytes,3,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`
+	StartedAt          int64                  `protobuf:"varint,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	UptimeSeconds      int64                  `protobuf:"varint,5,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	ClaudeVersion      string                 `protobuf:"bytes,6,opt,name=claude_version,json=claudeVersion,proto3" json:"claude_version,omitempty"`           // Output of claude --version, empty if the CLI is not available
+	ClaudeConfigured   bool                   `protobuf:"varint,7,opt,name=claude_configured,json=claudeConfigured,proto3" json:"claude_configured,omitempty"` // Whether the Claude settings tasks need were applied without errors
+	Workspace          string                 `protobuf:"bytes,8,opt,name=workspace,proto3" json:"workspace,omitempty"`                                        // Project directory of the sandbox, where the project was initialized
+	RunningTasks       int32                  `protobuf:"varint,9,opt,name=running_tasks,json=runningTasks,proto3" json:"running_tasks,omitempty"`
+	QueuedTasks        int32                  `protobuf:"varint,10,opt,name=queued_tasks,json=queuedTasks,proto3" json:"queued_tasks,omitempty"`
+	MaxConcurrentTasks int32                  `protobuf:"varint,11,opt,name=max_concurrent_tasks,json=maxConcurrentTasks,proto3" json:"max_concurrent_tasks,omitempty"`
+	Credentials        []string               `protobuf:"bytes,12,rep,name=credentials,proto3" json:"credentials,omitempty"` // Names of the credentials set with SetCredentials, which tasks can refer to
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DaemonInfoResponse) Reset() {
+	*x = DaemonInfoResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DaemonInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DaemonInfoResponse) ProtoMessage() {}
+
+func (x *DaemonInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DaemonInfoResponse.ProtoReflect.Descriptor instead.
+func (*DaemonInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DaemonInfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *DaemonInfoResponse) GetGitCommit() string {
+	if x != nil {
+		return x.GitCommit
+	}
+	return ""
+}
+
+func (x *DaemonInfoResponse) GetBuildTime() string {
+	if x != nil {
+		return x.BuildTime
+	}
+	return ""
+}
+
+func (x *DaemonInfoResponse) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *DaemonInfoResponse) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *DaemonInfoResponse) GetClaudeVersion() string {
+	if x != nil {
+		return x.ClaudeVersion
+	}
+	return ""
+}
+
+func (x *DaemonInfoResponse) GetClaudeConfigured() bool {
+	if x != nil {
+		return x.ClaudeConfigured
+	}
+	return false
+}
+
+func (x *DaemonInfoResponse) GetWorkspace() string {
+	if x != nil {
+		return x.Workspace
 	}
-	return ExecuteClaudeResponse_STDOUT
+	return ""
 }
 
-func (x *ExecuteClaudeResponse) GetContent() string {
+func (x *DaemonInfoResponse) GetRunningTasks() int32 {
 	if x != nil {
-		return x.Content
+		return x.RunningTasks
 	}
-	return ""
+	return 0
 }
 
-func (x *ExecuteClaudeResponse) GetTimestamp() int64 {
+func (x *DaemonInfoResponse) GetQueuedTasks() int32 {
 	if x != nil {
-		return x.Timestamp
+		return x.QueuedTasks
 	}
 	return 0
 }
 
-func (x *ExecuteClaudeResponse) GetExitCode() int32 {
+func (x *DaemonInfoResponse) GetMaxConcurrentTasks() int32 {
 	if x != nil {
-		return x.ExitCode
+		return x.MaxConcurrentTasks
 	}
 	return 0
 }
 
-func (x *ExecuteClaudeResponse) GetIsFinished() bool {
+func (x *DaemonInfoResponse) GetCredentials() []string {
 	if x != nil {
-		return x.IsFinished
+		return x.Credentials
 	}
-	return false
+	return nil
 }
 
-// TaskStatusRequest for checking task status
-type TaskStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// SetCredentialsRequest stores an Anthropic API key in the daemon under a name
+// that tasks refer to, so the key is not sent with every task
+type SetCredentialsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                // Empty stores the "default" credentials
+	AnthropicApiKey string                 `protobuf:"bytes,2,opt,name=anthropic_api_key,json=anthropicApiKey,proto3" json:"anthropic_api_key,omitempty"` // Empty removes the credentials
+	Persist         bool                   `protobuf:"varint,3,opt,name=persist,proto3" json:"persist,omitempty"`                                         // Also keep them in a file only the daemon's user can read, so they survive restarts
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *TaskStatusRequest) Reset() {
-	*x = TaskStatusRequest{}
-	mi := &file_proto_daemon_proto_msgTypes[8]
+func (x *SetCredentialsRequest) Reset() {
+	*x = SetCredentialsRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskStatusRequest) String() string {
+func (x *SetCredentialsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskStatusRequest) ProtoMessage() {}
+func (*SetCredentialsRequest) ProtoMessage() {}
 
-func (x *TaskStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[8]
+func (x *SetCredentialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -634,48 +2629,55 @@ func (x *TaskStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskStatusRequest.ProtoReflect.Descriptor instead.
-func (*TaskStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use SetCredentialsRequest.ProtoReflect.Descriptor instead.
+func (*SetCredentialsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *TaskStatusRequest) GetTaskId() string {
+func (x *SetCredentialsRequest) GetName() string {
 	if x != nil {
-		return x.TaskId
+		return x.Name
 	}
 	return ""
 }
 
-// TaskStatusResponse returns current task status
-type TaskStatusResponse struct {
-	state            protoimpl.MessageState       `protogen:"open.v1"`
-	State            TaskStatusResponse_TaskState `protobuf:"varint,1,opt,name=state,proto3,enum=daemon.TaskStatusResponse_TaskState" json:"state,omitempty"`
-	Message          string                       `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	ExitCode         int32                        `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
-	StartedAt        int64                        `protobuf:"varint,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	FinishedAt       int64                        `protobuf:"varint,5,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
-	Error            string                       `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
-	Prompt           string                       `protobuf:"bytes,7,opt,name=prompt,proto3" json:"prompt,omitempty"`
-	WorkingDirectory string                       `protobuf:"bytes,8,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *SetCredentialsRequest) GetAnthropicApiKey() string {
+	if x != nil {
+		return x.AnthropicApiKey
+	}
+	return ""
 }
 
-func (x *TaskStatusResponse) Reset() {
-	*x = TaskStatusResponse{}
-	mi := &file_proto_daemon_proto_msgTypes[9]
+func (x *SetCredentialsRequest) GetPersist() bool {
+	if x != nil {
+		return x.Persist
+	}
+	return false
+}
+
+type SetCredentialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCredentialsResponse) Reset() {
+	*x = SetCredentialsResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskStatusResponse) String() string {
+func (x *SetCredentialsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskStatusResponse) ProtoMessage() {}
+func (*SetCredentialsResponse) ProtoMessage() {}
 
-func (x *TaskStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[9]
+func (x *SetCredentialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -686,91 +2688,112 @@ func (x *TaskStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskStatusResponse.ProtoReflect.Descriptor instead.
-func (*TaskStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use SetCredentialsResponse.ProtoReflect.Descriptor instead.
+func (*SetCredentialsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *TaskStatusResponse) GetState() TaskStatusResponse_TaskState {
+func (x *SetCredentialsResponse) GetSuccess() bool {
 	if x != nil {
-		return x.State
+		return x.Success
 	}
-	return TaskStatusResponse_PENDING
+	return false
 }
 
-func (x *TaskStatusResponse) GetMessage() string {
+func (x *SetCredentialsResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *TaskStatusResponse) GetExitCode() int32 {
-	if x != nil {
-		return x.ExitCode
-	}
-	return 0
+// WorkspaceDiffRequest asks for the changes in a workspace since the commit it
+// was created from, including uncommitted and untracked files
+type WorkspaceDiffRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	WorkingDirectory string                 `protobuf:"bytes,1,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"` // Defaults to the project directory of the sandbox
+	BaseRef          string                 `protobuf:"bytes,2,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`                            // Commit to compare with, empty detects the commit the workspace started from
+	IncludePatches   bool                   `protobuf:"varint,3,opt,name=include_patches,json=includePatches,proto3" json:"include_patches,omitempty"`      // Include the unified diff of every file, not only its status and line counts
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *TaskStatusResponse) GetStartedAt() int64 {
-	if x != nil {
-		return x.StartedAt
-	}
-	return 0
+func (x *WorkspaceDiffRequest) Reset() {
+	*x = WorkspaceDiffRequest{}
+	mi := &file_proto_daemon_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskStatusResponse) GetFinishedAt() int64 {
+func (x *WorkspaceDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceDiffRequest) ProtoMessage() {}
+
+func (x *WorkspaceDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[30]
 	if x != nil {
-		return x.FinishedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *TaskStatusResponse) GetError() string {
+// Deprecated: Use WorkspaceDiffRequest.ProtoReflect.Descriptor instead.
+func (*WorkspaceDiffRequest) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WorkspaceDiffRequest) GetWorkingDirectory() string {
 	if x != nil {
-		return x.Error
+		return x.WorkingDirectory
 	}
 	return ""
 }
 
-func (x *TaskStatusResponse) GetPrompt() string {
+func (x *WorkspaceDiffRequest) GetBaseRef() string {
 	if x != nil {
-		return x.Prompt
+		return x.BaseRef
 	}
 	return ""
 }
 
-func (x *TaskStatusResponse) GetWorkingDirectory() string {
+func (x *WorkspaceDiffRequest) GetIncludePatches() bool {
 	if x != nil {
-		return x.WorkingDirectory
+		return x.IncludePatches
 	}
-	return ""
+	return false
 }
 
-// ListTasksRequest for listing all tasks
-type ListTasksRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Optional: filter by state
-	StateFilter   TaskStatusResponse_TaskState `protobuf:"varint,1,opt,name=state_filter,json=stateFilter,proto3,enum=daemon.TaskStatusResponse_TaskState" json:"state_filter,omitempty"`
+type WorkspaceDiffResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCommit    string                 `protobuf:"bytes,1,opt,name=base_commit,json=baseCommit,proto3" json:"base_commit,omitempty"`
+	HeadCommit    string                 `protobuf:"bytes,2,opt,name=head_commit,json=headCommit,proto3" json:"head_commit,omitempty"` // Current commit, the diff also covers changes not committed yet
+	Branch        string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	Files         []*FileDiff            `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksRequest) Reset() {
-	*x = ListTasksRequest{}
-	mi := &file_proto_daemon_proto_msgTypes[10]
+func (x *WorkspaceDiffResponse) Reset() {
+	*x = WorkspaceDiffResponse{}
+	mi := &file_proto_daemon_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksRequest) String() string {
+func (x *WorkspaceDiffResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTasksRequest) ProtoMessage() {}
+func (*WorkspaceDiffResponse) ProtoMessage() {}
 
-func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[10]
+func (x *WorkspaceDiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -781,48 +2804,69 @@ func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
-func (*ListTasksRequest) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use WorkspaceDiffResponse.ProtoReflect.Descriptor instead.
+func (*WorkspaceDiffResponse) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *ListTasksRequest) GetStateFilter() TaskStatusResponse_TaskState {
+func (x *WorkspaceDiffResponse) GetBaseCommit() string {
 	if x != nil {
-		return x.StateFilter
+		return x.BaseCommit
 	}
-	return TaskStatusResponse_PENDING
+	return ""
 }
 
-// TaskInfo contains information about a task
-type TaskInfo struct {
-	state            protoimpl.MessageState       `protogen:"open.v1"`
-	TaskId           string                       `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Prompt           string                       `protobuf:"bytes,2,opt,name=prompt,proto3" json:"prompt,omitempty"`
-	State            TaskStatusResponse_TaskState `protobuf:"varint,3,opt,name=state,proto3,enum=daemon.TaskStatusResponse_TaskState" json:"state,omitempty"`
-	StartedAt        int64                        `protobuf:"varint,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	FinishedAt       int64                        `protobuf:"varint,5,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
-	ExitCode         int32                        `protobuf:"varint,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
-	Error            string                       `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
-	WorkingDirectory string                       `protobuf:"bytes,8,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *WorkspaceDiffResponse) GetHeadCommit() string {
+	if x != nil {
+		return x.HeadCommit
+	}
+	return ""
 }
 
-func (x *TaskInfo) Reset() {
-	*x = TaskInfo{}
-	mi := &file_proto_daemon_proto_msgTypes[11]
+func (x *WorkspaceDiffResponse) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *WorkspaceDiffResponse) GetFiles() []*FileDiff {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// FileDiff is the change to one file of a workspace
+type FileDiff struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Path           string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	OldPath        string                 `protobuf:"bytes,2,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"` // Path before a rename
+	Status         FileDiff_Status        `protobuf:"varint,3,opt,name=status,proto3,enum=daemon.FileDiff_Status" json:"status,omitempty"`
+	Additions      int32                  `protobuf:"varint,4,opt,name=additions,proto3" json:"additions,omitempty"`
+	Deletions      int32                  `protobuf:"varint,5,opt,name=deletions,proto3" json:"deletions,omitempty"`
+	Binary         bool                   `protobuf:"varint,6,opt,name=binary,proto3" json:"binary,omitempty"`
+	Patch          string                 `protobuf:"bytes,7,opt,name=patch,proto3" json:"patch,omitempty"`                                          // Unified diff, set if include_patches was requested
+	PatchTruncated bool                   `protobuf:"varint,8,opt,name=patch_truncated,json=patchTruncated,proto3" json:"patch_truncated,omitempty"` // The patch was cut short or left out because the diff is too large
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FileDiff) Reset() {
+	*x = FileDiff{}
+	mi := &file_proto_daemon_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskInfo) String() string {
+func (x *FileDiff) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskInfo) ProtoMessage() {}
+func (*FileDiff) ProtoMessage() {}
 
-func (x *TaskInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[11]
+func (x *FileDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_daemon_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -833,155 +2877,170 @@ func (x *TaskInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskInfo.ProtoReflect.Descriptor instead.
-func (*TaskInfo) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use FileDiff.ProtoReflect.Descriptor instead.
+func (*FileDiff) Descriptor() ([]byte, []int) {
+	return file_proto_daemon_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *TaskInfo) GetTaskId() string {
+func (x *FileDiff) GetPath() string {
 	if x != nil {
-		return x.TaskId
+		return x.Path
 	}
 	return ""
 }
 
-func (x *TaskInfo) GetPrompt() string {
+func (x *FileDiff) GetOldPath() string {
 	if x != nil {
-		return x.Prompt
+		return x.OldPath
 	}
 	return ""
 }
 
-func (x *TaskInfo) GetState() TaskStatusResponse_TaskState {
-	if x != nil {
-		return x.State
-	}
-	return TaskStatusResponse_PENDING
-}
-
-func (x *TaskInfo) GetStartedAt() int64 {
+func (x *FileDiff) GetStatus() FileDiff_Status {
 	if x != nil {
-		return x.StartedAt
+		return x.Status
 	}
-	return 0
+	return FileDiff_MODIFIED
 }
 
-func (x *TaskInfo) GetFinishedAt() int64 {
+func (x *FileDiff) GetAdditions() int32 {
 	if x != nil {
-		return x.FinishedAt
+		return x.Additions
 	}
 	return 0
 }
 
-func (x *TaskInfo) GetExitCode() int32 {
+func (x *FileDiff) GetDeletions() int32 {
 	if x != nil {
-		return x.ExitCode
+		return x.Deletions
 	}
 	return 0
 }
 
-func (x *TaskInfo) GetError() string {
+func (x *FileDiff) GetBinary() bool {
 	if x != nil {
-		return x.Error
+		return x.Binary
 	}
-	return ""
+	return false
 }
 
-func (x *TaskInfo) GetWorkingDirectory() string {
+func (x *FileDiff) GetPatch() string {
 	if x != nil {
-		return x.WorkingDirectory
+		return x.Patch
 	}
 	return ""
 }
 
-// ListTasksResponse returns list of tasks
-type ListTasksResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tasks         []*TaskInfo            `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ListTasksResponse) Reset() {
-	*x = ListTasksResponse{}
-	mi := &file_proto_daemon_proto_msgTypes[12]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ListTasksResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ListTasksResponse) ProtoMessage() {}
-
-func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_daemon_proto_msgTypes[12]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
-func (*ListTasksResponse) Descriptor() ([]byte, []int) {
-	return file_proto_daemon_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *ListTasksResponse) GetTasks() []*TaskInfo {
+func (x *FileDiff) GetPatchTruncated() bool {
 	if x != nil {
-		return x.Tasks
+		return x.PatchTruncated
 	}
-	return nil
+	return false
 }
 
 var File_proto_daemon_proto protoreflect.FileDescriptor
 
 const file_proto_daemon_proto_rawDesc = "" +
 	"\n" +
-	"\x12proto/daemon.proto\x12\x06daemon\"0\n" +
+	"\x12proto/daemon.proto\x12\x06daemon\"\xce\x01\n" +
 	"\vInitRequest\x12!\n" +
-	"\fproject_type\x18\x01 \x01(\tR\vprojectType\"B\n" +
+	"\fproject_type\x18\x01 \x01(\tR\vprojectType\x12+\n" +
+	"\x11working_directory\x18\x02 \x01(\tR\x10workingDirectory\x12%\n" +
+	"\x0esetup_commands\x18\x03 \x03(\tR\rsetupCommands\x12#\n" +
+	"\rtest_commands\x18\x04 \x03(\tR\ftestCommands\x12#\n" +
+	"\rlint_commands\x18\x05 \x03(\tR\flintCommands\"q\n" +
 	"\fInitResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\r\n" +
-	"\vLogsRequest\"I\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\aproject\x18\x03 \x01(\v2\x13.daemon.ProjectInfoR\aproject\"\xb2\x02\n" +
+	"\x13ProjectInitResponse\x12<\n" +
+	"\x04type\x18\x01 \x01(\x0e2(.daemon.ProjectInitResponse.ResponseTypeR\x04type\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x1f\n" +
+	"\vis_finished\x18\x04 \x01(\bR\n" +
+	"isFinished\x12\x18\n" +
+	"\asuccess\x18\x05 \x01(\bR\asuccess\x12-\n" +
+	"\aproject\x18\x06 \x01(\v2\x13.daemon.ProjectInfoR\aproject\";\n" +
+	"\fResponseType\x12\n" +
+	"\n" +
+	"\x06STATUS\x10\x00\x12\n" +
+	"\n" +
+	"\x06OUTPUT\x10\x01\x12\t\n" +
+	"\x05ERROR\x10\x02\x12\b\n" +
+	"\x04DONE\x10\x03\"\x9d\x02\n" +
+	"\vProjectInfo\x12+\n" +
+	"\x11working_directory\x18\x01 \x01(\tR\x10workingDirectory\x12\x1e\n" +
+	"\n" +
+	"toolchains\x18\x02 \x03(\tR\n" +
+	"toolchains\x12)\n" +
+	"\x10install_commands\x18\x03 \x03(\tR\x0finstallCommands\x12%\n" +
+	"\x0ebuild_commands\x18\x04 \x03(\tR\rbuildCommands\x12#\n" +
+	"\rtest_commands\x18\x05 \x03(\tR\ftestCommands\x12%\n" +
+	"\x0esetup_commands\x18\x06 \x03(\tR\rsetupCommands\x12#\n" +
+	"\rlint_commands\x18\a \x03(\tR\flintCommands\"\x8d\x01\n" +
+	"\vLogsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x127\n" +
+	"\tmin_level\x18\x02 \x01(\x0e2\x1a.daemon.LogsResponse.LevelR\bminLevel\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\x03R\x05since\x12\x16\n" +
+	"\x06follow\x18\x04 \x01(\bR\x06follow\"\xd2\x01\n" +
 	"\fLogsResponse\x12\x1b\n" +
 	"\tlog_entry\x18\x01 \x01(\tR\blogEntry\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"\xb9\x02\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x120\n" +
+	"\x05level\x18\x03 \x01(\x0e2\x1a.daemon.LogsResponse.LevelR\x05level\x12\x17\n" +
+	"\atask_id\x18\x04 \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05event\x18\x05 \x01(\tR\x05event\"&\n" +
+	"\x05Level\x12\b\n" +
+	"\x04INFO\x10\x00\x12\b\n" +
+	"\x04WARN\x10\x01\x12\t\n" +
+	"\x05ERROR\x10\x02\"\xe3\x04\n" +
 	"\x11CreateTaskRequest\x12\x16\n" +
 	"\x06prompt\x18\x01 \x01(\tR\x06prompt\x12+\n" +
 	"\x11working_directory\x18\x02 \x01(\tR\x10workingDirectory\x12Y\n" +
 	"\x10environment_vars\x18\x03 \x03(\v2..daemon.CreateTaskRequest.EnvironmentVarsEntryR\x0fenvironmentVars\x12*\n" +
 	"\x11anthropic_api_key\x18\x04 \x01(\tR\x0fanthropicApiKey\x12\x14\n" +
-	"\x05model\x18\x05 \x01(\tR\x05model\x1aB\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x12$\n" +
+	"\x0eresume_task_id\x18\x06 \x01(\tR\fresumeTaskId\x12)\n" +
+	"\x10continue_session\x18\a \x01(\bR\x0fcontinueSession\x12'\n" +
+	"\x0ftimeout_seconds\x18\b \x01(\x05R\x0etimeoutSeconds\x120\n" +
+	"\x14idle_timeout_seconds\x18\t \x01(\x05R\x12idleTimeoutSeconds\x12'\n" +
+	"\x0fverify_commands\x18\n" +
+	" \x03(\tR\x0everifyCommands\x12(\n" +
+	"\x10max_fix_attempts\x18\v \x01(\x05R\x0emaxFixAttempts\x12)\n" +
+	"\x10credentials_name\x18\f \x01(\tR\x0fcredentialsName\x1aB\n" +
 	"\x14EnvironmentVarsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"a\n" +
 	"\x12CreateTaskResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x17\n" +
 	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"\xbf\x02\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xe9\x04\n" +
 	"\x14ExecuteClaudeRequest\x12\x16\n" +
 	"\x06prompt\x18\x01 \x01(\tR\x06prompt\x12+\n" +
 	"\x11working_directory\x18\x02 \x01(\tR\x10workingDirectory\x12\\\n" +
 	"\x10environment_vars\x18\x03 \x03(\v21.daemon.ExecuteClaudeRequest.EnvironmentVarsEntryR\x0fenvironmentVars\x12*\n" +
 	"\x11anthropic_api_key\x18\x04 \x01(\tR\x0fanthropicApiKey\x12\x14\n" +
-	"\x05model\x18\x05 \x01(\tR\x05model\x1aB\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x12$\n" +
+	"\x0eresume_task_id\x18\x06 \x01(\tR\fresumeTaskId\x12)\n" +
+	"\x10continue_session\x18\a \x01(\bR\x0fcontinueSession\x12'\n" +
+	"\x0ftimeout_seconds\x18\b \x01(\x05R\x0etimeoutSeconds\x120\n" +
+	"\x14idle_timeout_seconds\x18\t \x01(\x05R\x12idleTimeoutSeconds\x12'\n" +
+	"\x0fverify_commands\x18\n" +
+	" \x03(\tR\x0everifyCommands\x12(\n" +
+	"\x10max_fix_attempts\x18\v \x01(\x05R\x0emaxFixAttempts\x12)\n" +
+	"\x10credentials_name\x18\f \x01(\tR\x0fcredentialsName\x1aB\n" +
 	"\x14EnvironmentVarsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8c\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcc\x03\n" +
 	"\x15ExecuteClaudeResponse\x12>\n" +
 	"\x04type\x18\x01 \x01(\x0e2*.daemon.ExecuteClaudeResponse.ResponseTypeR\x04type\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1c\n" +
 	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x1b\n" +
 	"\texit_code\x18\x04 \x01(\x05R\bexitCode\x12\x1f\n" +
 	"\vis_finished\x18\x05 \x01(\bR\n" +
-	"isFinished\"=\n" +
+	"isFinished\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x03R\x06offset\x12\x17\n" +
+	"\atask_id\x18\a \x01(\tR\x06taskId\x12)\n" +
+	"\x05event\x18\b \x01(\v2\x13.daemon.ClaudeEventR\x05event\"\xa0\x01\n" +
 	"\fResponseType\x12\n" +
 	"\n" +
 	"\x06STDOUT\x10\x00\x12\n" +
@@ -989,9 +3048,54 @@ const file_proto_daemon_proto_rawDesc = "" +
 	"\x06STDERR\x10\x01\x12\n" +
 	"\n" +
 	"\x06STATUS\x10\x02\x12\t\n" +
-	"\x05ERROR\x10\x03\",\n" +
+	"\x05ERROR\x10\x03\x12\r\n" +
+	"\tASSISTANT\x10\x04\x12\f\n" +
+	"\bTOOL_USE\x10\x05\x12\x0f\n" +
+	"\vTOOL_RESULT\x10\x06\x12\t\n" +
+	"\x05USAGE\x10\a\x12\n" +
+	"\n" +
+	"\x06RESULT\x10\b\x12\n" +
+	"\n" +
+	"\x06SYSTEM\x10\t\x12\x10\n" +
+	"\fVERIFICATION\x10\n" +
+	"\"\x9c\x02\n" +
+	"\vClaudeEvent\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x120\n" +
+	"\btool_use\x18\x03 \x01(\v2\x15.daemon.ClaudeToolUseR\atoolUse\x129\n" +
+	"\vtool_result\x18\x04 \x01(\v2\x18.daemon.ClaudeToolResultR\n" +
+	"toolResult\x12)\n" +
+	"\x05usage\x18\x05 \x01(\v2\x13.daemon.ClaudeUsageR\x05usage\x12,\n" +
+	"\x06result\x18\x06 \x01(\v2\x14.daemon.ClaudeResultR\x06result\x12\x14\n" +
+	"\x05model\x18\a \x01(\tR\x05model\"R\n" +
+	"\rClaudeToolUse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"input_json\x18\x03 \x01(\tR\tinputJson\"g\n" +
+	"\x10ClaudeToolResult\x12\x1e\n" +
+	"\vtool_use_id\x18\x01 \x01(\tR\ttoolUseId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x19\n" +
+	"\bis_error\x18\x03 \x01(\bR\aisError\"\xf1\x01\n" +
+	"\vClaudeUsage\x12!\n" +
+	"\finput_tokens\x18\x01 \x01(\x03R\vinputTokens\x12#\n" +
+	"\routput_tokens\x18\x02 \x01(\x03R\foutputTokens\x12=\n" +
+	"\x1bcache_creation_input_tokens\x18\x03 \x01(\x03R\x18cacheCreationInputTokens\x125\n" +
+	"\x17cache_read_input_tokens\x18\x04 \x01(\x03R\x14cacheReadInputTokens\x12$\n" +
+	"\x0etotal_cost_usd\x18\x05 \x01(\x01R\ftotalCostUsd\"\x81\x01\n" +
+	"\fClaudeResult\x12\x18\n" +
+	"\asubtype\x18\x01 \x01(\tR\asubtype\x12\x19\n" +
+	"\bis_error\x18\x02 \x01(\bR\aisError\x12\x1f\n" +
+	"\vduration_ms\x18\x03 \x01(\x03R\n" +
+	"durationMs\x12\x1b\n" +
+	"\tnum_turns\x18\x04 \x01(\x05R\bnumTurns\"M\n" +
+	"\x11AttachTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1f\n" +
+	"\vfrom_offset\x18\x02 \x01(\x03R\n" +
+	"fromOffset\",\n" +
 	"\x11TaskStatusRequest\x12\x17\n" +
-	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\xe4\x02\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\x94\x06\n" +
 	"\x12TaskStatusResponse\x12:\n" +
 	"\x05state\x18\x01 \x01(\x0e2$.daemon.TaskStatusResponse.TaskStateR\x05state\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
@@ -1002,15 +3106,40 @@ const file_proto_daemon_proto_rawDesc = "" +
 	"finishedAt\x12\x14\n" +
 	"\x05error\x18\x06 \x01(\tR\x05error\x12\x16\n" +
 	"\x06prompt\x18\a \x01(\tR\x06prompt\x12+\n" +
-	"\x11working_directory\x18\b \x01(\tR\x10workingDirectory\"@\n" +
+	"\x11working_directory\x18\b \x01(\tR\x10workingDirectory\x12%\n" +
+	"\x0equeue_position\x18\t \x01(\x05R\rqueuePosition\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\n" +
+	" \x01(\tR\tsessionId\x12$\n" +
+	"\x0eresume_task_id\x18\v \x01(\tR\fresumeTaskId\x12)\n" +
+	"\x05usage\x18\f \x01(\v2\x13.daemon.ClaudeUsageR\x05usage\x12\x14\n" +
+	"\x05model\x18\r \x01(\tR\x05model\x12>\n" +
+	"\fverification\x18\x0e \x03(\v2\x1a.daemon.VerificationResultR\fverification\x12$\n" +
+	"\x0eparent_task_id\x18\x0f \x01(\tR\fparentTaskId\x12\x18\n" +
+	"\aattempt\x18\x10 \x01(\x05R\aattempt\x12 \n" +
+	"\ffix_task_ids\x18\x11 \x03(\tR\n" +
+	"fixTaskIds\x12\x16\n" +
+	"\x06result\x18\x12 \x01(\tR\x06result\"\x88\x01\n" +
 	"\tTaskState\x12\v\n" +
 	"\aPENDING\x10\x00\x12\v\n" +
 	"\aRUNNING\x10\x01\x12\r\n" +
 	"\tCOMPLETED\x10\x02\x12\n" +
 	"\n" +
-	"\x06FAILED\x10\x03\"[\n" +
-	"\x10ListTasksRequest\x12G\n" +
-	"\fstate_filter\x18\x01 \x01(\x0e2$.daemon.TaskStatusResponse.TaskStateR\vstateFilter\"\x97\x02\n" +
+	"\x06FAILED\x10\x03\x12\r\n" +
+	"\tCANCELLED\x10\x04\x12\x0f\n" +
+	"\vINTERRUPTED\x10\x05\x12\r\n" +
+	"\tTIMED_OUT\x10\x06\x12\x17\n" +
+	"\x13VERIFICATION_FAILED\x10\a\"\x8d\x01\n" +
+	"\x12VerificationResult\x12\x18\n" +
+	"\acommand\x18\x01 \x01(\tR\acommand\x12\x1b\n" +
+	"\texit_code\x18\x02 \x01(\x05R\bexitCode\x12\x1f\n" +
+	"\voutput_tail\x18\x03 \x01(\tR\n" +
+	"outputTail\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\"q\n" +
+	"\x10ListTasksRequest\x12L\n" +
+	"\fstate_filter\x18\x01 \x01(\x0e2$.daemon.TaskStatusResponse.TaskStateH\x00R\vstateFilter\x88\x01\x01B\x0f\n" +
+	"\r_state_filter\"\xfe\x04\n" +
 	"\bTaskInfo\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
 	"\x06prompt\x18\x02 \x01(\tR\x06prompt\x12:\n" +
@@ -1021,19 +3150,108 @@ const file_proto_daemon_proto_rawDesc = "" +
 	"finishedAt\x12\x1b\n" +
 	"\texit_code\x18\x06 \x01(\x05R\bexitCode\x12\x14\n" +
 	"\x05error\x18\a \x01(\tR\x05error\x12+\n" +
-	"\x11working_directory\x18\b \x01(\tR\x10workingDirectory\";\n" +
+	"\x11working_directory\x18\b \x01(\tR\x10workingDirectory\x12%\n" +
+	"\x0equeue_position\x18\t \x01(\x05R\rqueuePosition\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\n" +
+	" \x01(\tR\tsessionId\x12$\n" +
+	"\x0eresume_task_id\x18\v \x01(\tR\fresumeTaskId\x12)\n" +
+	"\x05usage\x18\f \x01(\v2\x13.daemon.ClaudeUsageR\x05usage\x12\x14\n" +
+	"\x05model\x18\r \x01(\tR\x05model\x12>\n" +
+	"\fverification\x18\x0e \x03(\v2\x1a.daemon.VerificationResultR\fverification\x12$\n" +
+	"\x0eparent_task_id\x18\x0f \x01(\tR\fparentTaskId\x12\x18\n" +
+	"\aattempt\x18\x10 \x01(\x05R\aattempt\x12 \n" +
+	"\ffix_task_ids\x18\x11 \x03(\tR\n" +
+	"fixTaskIds\x12\x16\n" +
+	"\x06result\x18\x12 \x01(\tR\x06result\";\n" +
 	"\x11ListTasksResponse\x12&\n" +
-	"\x05tasks\x18\x01 \x03(\v2\x10.daemon.TaskInfoR\x05tasks2x\n" +
-	"\x0eProjectService\x121\n" +
-	"\x04Init\x12\x13.daemon.InitRequest\x1a\x14.daemon.InitResponse\x123\n" +
-	"\x04Logs\x12\x13.daemon.LogsRequest\x1a\x14.daemon.LogsResponse0\x012\xe0\x02\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x10.daemon.TaskInfoR\x05tasks\"^\n" +
+	"\x11CancelTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x120\n" +
+	"\x14grace_period_seconds\x18\x02 \x01(\x05R\x12gracePeriodSeconds\"\x9d\x01\n" +
+	"\x12CancelTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
+	"\atask_id\x18\x03 \x01(\tR\x06taskId\x12:\n" +
+	"\x05state\x18\x04 \x01(\x0e2$.daemon.TaskStatusResponse.TaskStateR\x05state\"F\n" +
+	"\x0fMoveTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition\"m\n" +
+	"\x10MoveTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x0equeue_position\x18\x03 \x01(\x05R\rqueuePosition\"\x13\n" +
+	"\x11DaemonInfoRequest\"\xc0\x03\n" +
+	"\x12DaemonInfoResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1d\n" +
+	"\n" +
+	"git_commit\x18\x02 \x01(\tR\tgitCommit\x12\x1d\n" +
+	"\n" +
+	"build_time\x18\x03 \x01(\tR\tbuildTime\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\x03R\tstartedAt\x12%\n" +
+	"\x0euptime_seconds\x18\x05 \x01(\x03R\ruptimeSeconds\x12%\n" +
+	"\x0eclaude_version\x18\x06 \x01(\tR\rclaudeVersion\x12+\n" +
+	"\x11claude_configured\x18\a \x01(\bR\x10claudeConfigured\x12\x1c\n" +
+	"\tworkspace\x18\b \x01(\tR\tworkspace\x12#\n" +
+	"\rrunning_tasks\x18\t \x01(\x05R\frunningTasks\x12!\n" +
+	"\fqueued_tasks\x18\n" +
+	" \x01(\x05R\vqueuedTasks\x120\n" +
+	"\x14max_concurrent_tasks\x18\v \x01(\x05R\x12maxConcurrentTasks\x12 \n" +
+	"\vcredentials\x18\f \x03(\tR\vcredentials\"q\n" +
+	"\x15SetCredentialsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12*\n" +
+	"\x11anthropic_api_key\x18\x02 \x01(\tR\x0fanthropicApiKey\x12\x18\n" +
+	"\apersist\x18\x03 \x01(\bR\apersist\"L\n" +
+	"\x16SetCredentialsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x87\x01\n" +
+	"\x14WorkspaceDiffRequest\x12+\n" +
+	"\x11working_directory\x18\x01 \x01(\tR\x10workingDirectory\x12\x19\n" +
+	"\bbase_ref\x18\x02 \x01(\tR\abaseRef\x12'\n" +
+	"\x0finclude_patches\x18\x03 \x01(\bR\x0eincludePatches\"\x99\x01\n" +
+	"\x15WorkspaceDiffResponse\x12\x1f\n" +
+	"\vbase_commit\x18\x01 \x01(\tR\n" +
+	"baseCommit\x12\x1f\n" +
+	"\vhead_commit\x18\x02 \x01(\tR\n" +
+	"headCommit\x12\x16\n" +
+	"\x06branch\x18\x03 \x01(\tR\x06branch\x12&\n" +
+	"\x05files\x18\x04 \x03(\v2\x10.daemon.FileDiffR\x05files\"\xdb\x02\n" +
+	"\bFileDiff\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x19\n" +
+	"\bold_path\x18\x02 \x01(\tR\aoldPath\x12/\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x17.daemon.FileDiff.StatusR\x06status\x12\x1c\n" +
+	"\tadditions\x18\x04 \x01(\x05R\tadditions\x12\x1c\n" +
+	"\tdeletions\x18\x05 \x01(\x05R\tdeletions\x12\x16\n" +
+	"\x06binary\x18\x06 \x01(\bR\x06binary\x12\x14\n" +
+	"\x05patch\x18\a \x01(\tR\x05patch\x12'\n" +
+	"\x0fpatch_truncated\x18\b \x01(\bR\x0epatchTruncated\"\\\n" +
+	"\x06Status\x12\f\n" +
+	"\bMODIFIED\x10\x00\x12\t\n" +
+	"\x05ADDED\x10\x01\x12\v\n" +
+	"\aDELETED\x10\x02\x12\v\n" +
+	"\aRENAMED\x10\x03\x12\r\n" +
+	"\tUNTRACKED\x10\x04\x12\x10\n" +
+	"\fTYPE_CHANGED\x10\x052\x81\x01\n" +
+	"\x0eProjectService\x12:\n" +
+	"\x04Init\x12\x13.daemon.InitRequest\x1a\x1b.daemon.ProjectInitResponse0\x01\x123\n" +
+	"\x04Logs\x12\x13.daemon.LogsRequest\x1a\x14.daemon.LogsResponse0\x012\x95\x06\n" +
 	"\fAgentService\x121\n" +
 	"\x04Init\x12\x13.daemon.InitRequest\x1a\x14.daemon.InitResponse\x12C\n" +
 	"\n" +
 	"CreateTask\x12\x19.daemon.CreateTaskRequest\x1a\x1a.daemon.CreateTaskResponse\x12N\n" +
-	"\rExecuteClaude\x12\x1c.daemon.ExecuteClaudeRequest\x1a\x1d.daemon.ExecuteClaudeResponse0\x01\x12F\n" +
+	"\rExecuteClaude\x12\x1c.daemon.ExecuteClaudeRequest\x1a\x1d.daemon.ExecuteClaudeResponse0\x01\x12H\n" +
+	"\n" +
+	"AttachTask\x12\x19.daemon.AttachTaskRequest\x1a\x1d.daemon.ExecuteClaudeResponse0\x01\x12F\n" +
 	"\rGetTaskStatus\x12\x19.daemon.TaskStatusRequest\x1a\x1a.daemon.TaskStatusResponse\x12@\n" +
-	"\tListTasks\x12\x18.daemon.ListTasksRequest\x1a\x19.daemon.ListTasksResponseB\vZ\tcli/protob\x06proto3"
+	"\tListTasks\x12\x18.daemon.ListTasksRequest\x1a\x19.daemon.ListTasksResponse\x12C\n" +
+	"\n" +
+	"CancelTask\x12\x19.daemon.CancelTaskRequest\x1a\x1a.daemon.CancelTaskResponse\x12=\n" +
+	"\bMoveTask\x12\x17.daemon.MoveTaskRequest\x1a\x18.daemon.MoveTaskResponse\x12C\n" +
+	"\n" +
+	"DaemonInfo\x12\x19.daemon.DaemonInfoRequest\x1a\x1a.daemon.DaemonInfoResponse\x12O\n" +
+	"\x0eSetCredentials\x12\x1d.daemon.SetCredentialsRequest\x1a\x1e.daemon.SetCredentialsResponse\x12O\n" +
+	"\x10GetWorkspaceDiff\x12\x1c.daemon.WorkspaceDiffRequest\x1a\x1d.daemon.WorkspaceDiffResponseB\vZ\tcli/protob\x06proto3"
 
 var (
 	file_proto_daemon_proto_rawDescOnce sync.Once
@@ -1047,54 +3265,106 @@ func file_proto_daemon_proto_rawDescGZIP() []byte {
 	return file_proto_daemon_proto_rawDescData
 }
 
-var file_proto_daemon_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_daemon_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_proto_daemon_proto_goTypes = []any{
-	(ExecuteClaudeResponse_ResponseType)(0), // 0: daemon.ExecuteClaudeResponse.ResponseType
-	(TaskStatusResponse_TaskState)(0),       // 1: daemon.TaskStatusResponse.TaskState
-	(*InitRequest)(nil),                     // 2: daemon.InitRequest
-	(*InitResponse)(nil),                    // 3: daemon.InitResponse
-	(*LogsRequest)(nil),                     // 4: daemon.LogsRequest
-	(*LogsResponse)(nil),                    // 5: daemon.LogsResponse
-	(*CreateTaskRequest)(nil),               // 6: daemon.CreateTaskRequest
-	(*CreateTaskResponse)(nil),              // 7: daemon.CreateTaskResponse
-	(*ExecuteClaudeRequest)(nil),            // 8: daemon.ExecuteClaudeRequest
-	(*ExecuteClaudeResponse)(nil),           // 9: daemon.ExecuteClaudeResponse
-	(*TaskStatusRequest)(nil),               // 10: daemon.TaskStatusRequest
-	(*TaskStatusResponse)(nil),              // 11: daemon.TaskStatusResponse
-	(*ListTasksRequest)(nil),                // 12: daemon.ListTasksRequest
-	(*TaskInfo)(nil),                        // 13: daemon.TaskInfo
-	(*ListTasksResponse)(nil),               // 14: daemon.ListTasksResponse
-	nil,                                     // 15: daemon.CreateTaskRequest.EnvironmentVarsEntry
-	nil,                                     // 16: daemon.ExecuteClaudeRequest.EnvironmentVarsEntry
+	(ProjectInitResponse_ResponseType)(0),   // 0: daemon.ProjectInitResponse.ResponseType
+	(LogsResponse_Level)(0),                 // 1: daemon.LogsResponse.Level
+	(ExecuteClaudeResponse_ResponseType)(0), // 2: daemon.ExecuteClaudeResponse.ResponseType
+	(TaskStatusResponse_TaskState)(0),       // 3: daemon.TaskStatusResponse.TaskState
+	(FileDiff_Status)(0),                    // 4: daemon.FileDiff.Status
+	(*InitRequest)(nil),                     // 5: daemon.InitRequest
+	(*InitResponse)(nil),                    // 6: daemon.InitResponse
+	(*ProjectInitResponse)(nil),             // 7: daemon.ProjectInitResponse
+	(*ProjectInfo)(nil),                     // 8: daemon.ProjectInfo
+	(*LogsRequest)(nil),                     // 9: daemon.LogsRequest
+	(*LogsResponse)(nil),                    // 10: daemon.LogsResponse
+	(*CreateTaskRequest)(nil),               // 11: daemon.CreateTaskRequest
+	(*CreateTaskResponse)(nil),              // 12: daemon.CreateTaskResponse
+	(*ExecuteClaudeRequest)(nil),            // 13: daemon.ExecuteClaudeRequest
+	(*ExecuteClaudeResponse)(nil),           // 14: daemon.ExecuteClaudeResponse
+	(*ClaudeEvent)(nil),                     // 15: daemon.ClaudeEvent
+	(*ClaudeToolUse)(nil),                   // 16: daemon.ClaudeToolUse
+	(*ClaudeToolResult)(nil),                // 17: daemon.ClaudeToolResult
+	(*ClaudeUsage)(nil),                     // 18: daemon.ClaudeUsage
+	(*ClaudeResult)(nil),                    // 19: daemon.ClaudeResult
+	(*AttachTaskRequest)(nil),               // 20: daemon.AttachTaskRequest
+	(*TaskStatusRequest)(nil),               // 21: daemon.TaskStatusRequest
+	(*TaskStatusResponse)(nil),              // 22: daemon.TaskStatusResponse
+	(*VerificationResult)(nil),              // 23: daemon.VerificationResult
+	(*ListTasksRequest)(nil),                // 24: daemon.ListTasksRequest
+	(*TaskInfo)(nil),                        // 25: daemon.TaskInfo
+	(*ListTasksResponse)(nil),               // 26: daemon.ListTasksResponse
+	(*CancelTaskRequest)(nil),               // 27: daemon.CancelTaskRequest
+	(*CancelTaskResponse)(nil),              // 28: daemon.CancelTaskResponse
+	(*MoveTaskRequest)(nil),                 // 29: daemon.MoveTaskRequest
+	(*MoveTaskResponse)(nil),                // 30: daemon.MoveTaskResponse
+	(*DaemonInfoRequest)(nil),               // 31: daemon.DaemonInfoRequest
+	(*DaemonInfoResponse)(nil),              // 32: daemon.DaemonInfoResponse
+	(*SetCredentialsRequest)(nil),           // 33: daemon.SetCredentialsRequest
+	(*SetCredentialsResponse)(nil),          // 34: daemon.SetCredentialsResponse
+	(*WorkspaceDiffRequest)(nil),            // 35: daemon.WorkspaceDiffRequest
+	(*WorkspaceDiffResponse)(nil),           // 36: daemon.WorkspaceDiffResponse
+	(*FileDiff)(nil),                        // 37: daemon.FileDiff
+	nil,                                     // 38: daemon.CreateTaskRequest.EnvironmentVarsEntry
+	nil,                                     // 39: daemon.ExecuteClaudeRequest.EnvironmentVarsEntry
 }
 var file_proto_daemon_proto_depIdxs = []int32{
-	15, // 0: daemon.CreateTaskRequest.environment_vars:type_name -> daemon.CreateTaskRequest.EnvironmentVarsEntry
-	16, // 1: daemon.ExecuteClaudeRequest.environment_vars:type_name -> daemon.ExecuteClaudeRequest.EnvironmentVarsEntry
-	0,  // 2: daemon.ExecuteClaudeResponse.type:type_name -> daemon.ExecuteClaudeResponse.ResponseType
-	1,  // 3: daemon.TaskStatusResponse.state:type_name -> daemon.TaskStatusResponse.TaskState
-	1,  // 4: daemon.ListTasksRequest.state_filter:type_name -> daemon.TaskStatusResponse.TaskState
-	1,  // 5: daemon.TaskInfo.state:type_name -> daemon.TaskStatusResponse.TaskState
-	13, // 6: daemon.ListTasksResponse.tasks:type_name -> daemon.TaskInfo
-	2,  // 7: daemon.ProjectService.Init:input_type -> daemon.InitRequest
-	4,  // 8: daemon.ProjectService.Logs:input_type -> daemon.LogsRequest
-	2,  // 9: daemon.AgentService.Init:input_type -> daemon.InitRequest
-	6,  // 10: daemon.AgentService.CreateTask:input_type -> daemon.CreateTaskRequest
-	8,  // 11: daemon.AgentService.ExecuteClaude:input_type -> daemon.ExecuteClaudeRequest
-	10, // 12: daemon.AgentService.GetTaskStatus:input_type -> daemon.TaskStatusRequest
-	12, // 13: daemon.AgentService.ListTasks:input_type -> daemon.ListTasksRequest
-	3,  // 14: daemon.ProjectService.Init:output_type -> daemon.InitResponse
-	5,  // 15: daemon.ProjectService.Logs:output_type -> daemon.LogsResponse
-	3,  // 16: daemon.AgentService.Init:output_type -> daemon.InitResponse
-	7,  // 17: daemon.AgentService.CreateTask:output_type -> daemon.CreateTaskResponse
-	9,  // 18: daemon.AgentService.ExecuteClaude:output_type -> daemon.ExecuteClaudeResponse
-	11, // 19: daemon.AgentService.GetTaskStatus:output_type -> daemon.TaskStatusResponse
-	14, // 20: daemon.AgentService.ListTasks:output_type -> daemon.ListTasksResponse
-	14, // [14:21] is the sub-list for method output_type
-	7,  // [7:14] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	8,  // 0: daemon.InitResponse.project:type_name -> daemon.ProjectInfo
+	0,  // 1: daemon.ProjectInitResponse.type:type_name -> daemon.ProjectInitResponse.ResponseType
+	8,  // 2: daemon.ProjectInitResponse.project:type_name -> daemon.ProjectInfo
+	1,  // 3: daemon.LogsRequest.min_level:type_name -> daemon.LogsResponse.Level
+	1,  // 4: daemon.LogsResponse.level:type_name -> daemon.LogsResponse.Level
+	38, // 5: daemon.CreateTaskRequest.environment_vars:type_name -> daemon.CreateTaskRequest.EnvironmentVarsEntry
+	39, // 6: daemon.ExecuteClaudeRequest.environment_vars:type_name -> daemon.ExecuteClaudeRequest.EnvironmentVarsEntry
+	2,  // 7: daemon.ExecuteClaudeResponse.type:type_name -> daemon.ExecuteClaudeResponse.ResponseType
+	15, // 8: daemon.ExecuteClaudeResponse.event:type_name -> daemon.ClaudeEvent
+	16, // 9: daemon.ClaudeEvent.tool_use:type_name -> daemon.ClaudeToolUse
+	17, // 10: daemon.ClaudeEvent.tool_result:type_name -> daemon.ClaudeToolResult
+	18, // 11: daemon.ClaudeEvent.usage:type_name -> daemon.ClaudeUsage
+	19, // 12: daemon.ClaudeEvent.result:type_name -> daemon.ClaudeResult
+	3,  // 13: daemon.TaskStatusResponse.state:type_name -> daemon.TaskStatusResponse.TaskState
+	18, // 14: daemon.TaskStatusResponse.usage:type_name -> daemon.ClaudeUsage
+	23, // 15: daemon.TaskStatusResponse.verification:type_name -> daemon.VerificationResult
+	3,  // 16: daemon.ListTasksRequest.state_filter:type_name -> daemon.TaskStatusResponse.TaskState
+	3,  // 17: daemon.TaskInfo.state:type_name -> daemon.TaskStatusResponse.TaskState
+	18, // 18: daemon.TaskInfo.usage:type_name -> daemon.ClaudeUsage
+	23, // 19: daemon.TaskInfo.verification:type_name -> daemon.VerificationResult
+	25, // 20: daemon.ListTasksResponse.tasks:type_name -> daemon.TaskInfo
+	3,  // 21: daemon.CancelTaskResponse.state:type_name -> daemon.TaskStatusResponse.TaskState
+	37, // 22: daemon.WorkspaceDiffResponse.files:type_name -> daemon.FileDiff
+	4,  // 23: daemon.FileDiff.status:type_name -> daemon.FileDiff.Status
+	5,  // 24: daemon.ProjectService.Init:input_type -> daemon.InitRequest
+	9,  // 25: daemon.ProjectService.Logs:input_type -> daemon.LogsRequest
+	5,  // 26: daemon.AgentService.Init:input_type -> daemon.InitRequest
+	11, // 27: daemon.AgentService.CreateTask:input_type -> daemon.CreateTaskRequest
+	13, // 28: daemon.AgentService.ExecuteClaude:input_type -> daemon.ExecuteClaudeRequest
+	20, // 29: daemon.AgentService.AttachTask:input_type -> daemon.AttachTaskRequest
+	21, // 30: daemon.AgentService.GetTaskStatus:input_type -> daemon.TaskStatusRequest
+	24, // 31: daemon.AgentService.ListTasks:input_type -> daemon.ListTasksRequest
+	27, // 32: daemon.AgentService.CancelTask:input_type -> daemon.CancelTaskRequest
+	29, // 33: daemon.AgentService.MoveTask:input_type -> daemon.MoveTaskRequest
+	31, // 34: daemon.AgentService.DaemonInfo:input_type -> daemon.DaemonInfoRequest
+	33, // 35: daemon.AgentService.SetCredentials:input_type -> daemon.SetCredentialsRequest
+	35, // 36: daemon.AgentService.GetWorkspaceDiff:input_type -> daemon.WorkspaceDiffRequest
+	7,  // 37: daemon.ProjectService.Init:output_type -> daemon.ProjectInitResponse
+	10, // 38: daemon.ProjectService.Logs:output_type -> daemon.LogsResponse
+	6,  // 39: daemon.AgentService.Init:output_type -> daemon.InitResponse
+	12, // 40: daemon.AgentService.CreateTask:output_type -> daemon.CreateTaskResponse
+	14, // 41: daemon.AgentService.ExecuteClaude:output_type -> daemon.ExecuteClaudeResponse
+	14, // 42: daemon.AgentService.AttachTask:output_type -> daemon.ExecuteClaudeResponse
+	22, // 43: daemon.AgentService.GetTaskStatus:output_type -> daemon.TaskStatusResponse
+	26, // 44: daemon.AgentService.ListTasks:output_type -> daemon.ListTasksResponse
+	28, // 45: daemon.AgentService.CancelTask:output_type -> daemon.CancelTaskResponse
+	30, // 46: daemon.AgentService.MoveTask:output_type -> daemon.MoveTaskResponse
+	32, // 47: daemon.AgentService.DaemonInfo:output_type -> daemon.DaemonInfoResponse
+	34, // 48: daemon.AgentService.SetCredentials:output_type -> daemon.SetCredentialsResponse
+	36, // 49: daemon.AgentService.GetWorkspaceDiff:output_type -> daemon.WorkspaceDiffResponse
+	37, // [37:50] is the sub-list for method output_type
+	24, // [24:37] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_proto_daemon_proto_init() }
@@ -1102,13 +3372,14 @@ func file_proto_daemon_proto_init() {
 	if File_proto_daemon_proto != nil {
 		return
 	}
+	file_proto_daemon_proto_msgTypes[19].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_daemon_proto_rawDesc), len(file_proto_daemon_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   15,
+			NumEnums:      5,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
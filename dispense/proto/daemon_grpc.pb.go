@@ -29,7 +29,7 @@ const (
 //
 // ProjectService provides project-related operations
 type ProjectServiceClient interface {
-	Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitResponse, error)
+	Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProjectInitResponse], error)
 	Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogsResponse], error)
 }
 
@@ -41,19 +41,28 @@ func NewProjectServiceClient(cc grpc.ClientConnInterface) ProjectServiceClient {
 	return &projectServiceClient{cc}
 }
 
-func (c *projectServiceClient) Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitResponse, error) {
+func (c *projectServiceClient) Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProjectInitResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(InitResponse)
-	err := c.cc.Invoke(ctx, ProjectService_Init_FullMethodName, in, out, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &ProjectService_ServiceDesc.Streams[0], ProjectService_Init_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &grpc.GenericClientStream[InitRequest, ProjectInitResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProjectService_InitClient = grpc.ServerStreamingClient[ProjectInitResponse]
+
 func (c *projectServiceClient) Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogsResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &ProjectService_ServiceDesc.Streams[0], ProjectService_Logs_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &ProjectService_ServiceDesc.Streams[1], ProjectService_Logs_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +85,7 @@ type ProjectService_LogsClient = grpc.ServerStreamingClient[LogsResponse]
 //
 // ProjectService provides project-related operations
 type ProjectServiceServer interface {
-	Init(context.Context, *InitRequest) (*InitResponse, error)
+	Init(*InitRequest, grpc.ServerStreamingServer[ProjectInitResponse]) error
 	Logs(*LogsRequest, grpc.ServerStreamingServer[LogsResponse]) error
 	mustEmbedUnimplementedProjectServiceServer()
 }
@@ -88,8 +97,8 @@ type ProjectServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedProjectServiceServer struct{}
 
-func (UnimplementedProjectServiceServer) Init(context.Context, *InitRequest) (*InitResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Init not implemented")
+func (UnimplementedProjectServiceServer) Init(*InitRequest, grpc.ServerStreamingServer[ProjectInitResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Init not implemented")
 }
 func (UnimplementedProjectServiceServer) Logs(*LogsRequest, grpc.ServerStreamingServer[LogsResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
@@ -115,24 +124,17 @@ func RegisterProjectServiceServer(s grpc.ServiceRegistrar, srv ProjectServiceSer
 	s.RegisterService(&ProjectService_ServiceDesc, srv)
 }
 
-func _ProjectService_Init_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InitRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ProjectServiceServer).Init(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: ProjectService_Init_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProjectServiceServer).Init(ctx, req.(*InitRequest))
+func _ProjectService_Init_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InitRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return interceptor(ctx, in, info, handler)
+	return srv.(ProjectServiceServer).Init(m, &grpc.GenericServerStream[InitRequest, ProjectInitResponse]{ServerStream: stream})
 }
 
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProjectService_InitServer = grpc.ServerStreamingServer[ProjectInitResponse]
+
 func _ProjectService_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(LogsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -150,13 +152,13 @@ type ProjectService_LogsServer = grpc.ServerStreamingServer[LogsResponse]
 var ProjectService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "daemon.ProjectService",
 	HandlerType: (*ProjectServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
 		{
-			MethodName: "Init",
-			Handler:    _ProjectService_Init_Handler,
+			StreamName:    "Init",
+			Handler:       _ProjectService_Init_Handler,
+			ServerStreams: true,
 		},
-	},
-	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Logs",
 			Handler:       _ProjectService_Logs_Handler,
@@ -167,11 +169,17 @@ var ProjectService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	AgentService_Init_FullMethodName          = "/daemon.AgentService/Init"
-	AgentService_CreateTask_FullMethodName    = "/daemon.AgentService/CreateTask"
-	AgentService_ExecuteClaude_FullMethodName = "/daemon.AgentService/ExecuteClaude"
-	AgentService_GetTaskStatus_FullMethodName = "/daemon.AgentService/GetTaskStatus"
-	AgentService_ListTasks_FullMethodName     = "/daemon.AgentService/ListTasks"
+	AgentService_Init_FullMethodName             = "/daemon.AgentService/Init"
+	AgentService_CreateTask_FullMethodName       = "/daemon.AgentService/CreateTask"
+	AgentService_ExecuteClaude_FullMethodName    = "/daemon.AgentService/ExecuteClaude"
+	AgentService_AttachTask_FullMethodName       = "/daemon.AgentService/AttachTask"
+	AgentService_GetTaskStatus_FullMethodName    = "/daemon.AgentService/GetTaskStatus"
+	AgentService_ListTasks_FullMethodName        = "/daemon.AgentService/ListTasks"
+	AgentService_CancelTask_FullMethodName       = "/daemon.AgentService/CancelTask"
+	AgentService_MoveTask_FullMethodName         = "/daemon.AgentService/MoveTask"
+	AgentService_DaemonInfo_FullMethodName       = "/daemon.AgentService/DaemonInfo"
+	AgentService_SetCredentials_FullMethodName   = "/daemon.AgentService/SetCredentials"
+	AgentService_GetWorkspaceDiff_FullMethodName = "/daemon.AgentService/GetWorkspaceDiff"
 )
 
 // AgentServiceClient is the client API for AgentService service.
@@ -183,8 +191,14 @@ type AgentServiceClient interface {
 	Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitResponse, error)
 	CreateTask(ctx context.Context, in *CreateTaskRequest, opts ...grpc.CallOption) (*CreateTaskResponse, error)
 	ExecuteClaude(ctx context.Context, in *ExecuteClaudeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecuteClaudeResponse], error)
+	AttachTask(ctx context.Context, in *AttachTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecuteClaudeResponse], error)
 	GetTaskStatus(ctx context.Context, in *TaskStatusRequest, opts ...grpc.CallOption) (*TaskStatusResponse, error)
 	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error)
+	CancelTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*CancelTaskResponse, error)
+	MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*MoveTaskResponse, error)
+	DaemonInfo(ctx context.Context, in *DaemonInfoRequest, opts ...grpc.CallOption) (*DaemonInfoResponse, error)
+	SetCredentials(ctx context.Context, in *SetCredentialsRequest, opts ...grpc.CallOption) (*SetCredentialsResponse, error)
+	GetWorkspaceDiff(ctx context.Context, in *WorkspaceDiffRequest, opts ...grpc.CallOption) (*WorkspaceDiffResponse, error)
 }
 
 type agentServiceClient struct {
@@ -234,6 +248,25 @@ func (c *agentServiceClient) ExecuteClaude(ctx context.Context, in *ExecuteClaud
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type AgentService_ExecuteClaudeClient = grpc.ServerStreamingClient[ExecuteClaudeResponse]
 
+func (c *agentServiceClient) AttachTask(ctx context.Context, in *AttachTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecuteClaudeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentService_ServiceDesc.Streams[1], AgentService_AttachTask_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AttachTaskRequest, ExecuteClaudeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_AttachTaskClient = grpc.ServerStreamingClient[ExecuteClaudeResponse]
+
 func (c *agentServiceClient) GetTaskStatus(ctx context.Context, in *TaskStatusRequest, opts ...grpc.CallOption) (*TaskStatusResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TaskStatusResponse)
@@ -254,6 +287,56 @@ func (c *agentServiceClient) ListTasks(ctx context.Context, in *ListTasksRequest
 	return out, nil
 }
 
+func (c *agentServiceClient) CancelTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*CancelTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelTaskResponse)
+	err := c.cc.Invoke(ctx, AgentService_CancelTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*MoveTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveTaskResponse)
+	err := c.cc.Invoke(ctx, AgentService_MoveTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) DaemonInfo(ctx context.Context, in *DaemonInfoRequest, opts ...grpc.CallOption) (*DaemonInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DaemonInfoResponse)
+	err := c.cc.Invoke(ctx, AgentService_DaemonInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) SetCredentials(ctx context.Context, in *SetCredentialsRequest, opts ...grpc.CallOption) (*SetCredentialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetCredentialsResponse)
+	err := c.cc.Invoke(ctx, AgentService_SetCredentials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetWorkspaceDiff(ctx context.Context, in *WorkspaceDiffRequest, opts ...grpc.CallOption) (*WorkspaceDiffResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkspaceDiffResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetWorkspaceDiff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServiceServer is the server API for AgentService service.
 // All implementations must embed UnimplementedAgentServiceServer
 // for forward compatibility.
@@ -263,8 +346,14 @@ type AgentServiceServer interface {
 	Init(context.Context, *InitRequest) (*InitResponse, error)
 	CreateTask(context.Context, *CreateTaskRequest) (*CreateTaskResponse, error)
 	ExecuteClaude(*ExecuteClaudeRequest, grpc.ServerStreamingServer[ExecuteClaudeResponse]) error
+	AttachTask(*AttachTaskRequest, grpc.ServerStreamingServer[ExecuteClaudeResponse]) error
 	GetTaskStatus(context.Context, *TaskStatusRequest) (*TaskStatusResponse, error)
 	ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error)
+	CancelTask(context.Context, *CancelTaskRequest) (*CancelTaskResponse, error)
+	MoveTask(context.Context, *MoveTaskRequest) (*MoveTaskResponse, error)
+	DaemonInfo(context.Context, *DaemonInfoRequest) (*DaemonInfoResponse, error)
+	SetCredentials(context.Context, *SetCredentialsRequest) (*SetCredentialsResponse, error)
+	GetWorkspaceDiff(context.Context, *WorkspaceDiffRequest) (*WorkspaceDiffResponse, error)
 	mustEmbedUnimplementedAgentServiceServer()
 }
 
@@ -284,12 +373,30 @@ func (UnimplementedAgentServiceServer) CreateTask(context.Context, *CreateTaskRe
 func (UnimplementedAgentServiceServer) ExecuteClaude(*ExecuteClaudeRequest, grpc.ServerStreamingServer[ExecuteClaudeResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method ExecuteClaude not implemented")
 }
+func (UnimplementedAgentServiceServer) AttachTask(*AttachTaskRequest, grpc.ServerStreamingServer[ExecuteClaudeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AttachTask not implemented")
+}
 func (UnimplementedAgentServiceServer) GetTaskStatus(context.Context, *TaskStatusRequest) (*TaskStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTaskStatus not implemented")
 }
 func (UnimplementedAgentServiceServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
 }
+func (UnimplementedAgentServiceServer) CancelTask(context.Context, *CancelTaskRequest) (*CancelTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTask not implemented")
+}
+func (UnimplementedAgentServiceServer) MoveTask(context.Context, *MoveTaskRequest) (*MoveTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveTask not implemented")
+}
+func (UnimplementedAgentServiceServer) DaemonInfo(context.Context, *DaemonInfoRequest) (*DaemonInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DaemonInfo not implemented")
+}
+func (UnimplementedAgentServiceServer) SetCredentials(context.Context, *SetCredentialsRequest) (*SetCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCredentials not implemented")
+}
+func (UnimplementedAgentServiceServer) GetWorkspaceDiff(context.Context, *WorkspaceDiffRequest) (*WorkspaceDiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceDiff not implemented")
+}
 func (UnimplementedAgentServiceServer) mustEmbedUnimplementedAgentServiceServer() {}
 func (UnimplementedAgentServiceServer) testEmbeddedByValue()                      {}
 
@@ -358,6 +465,17 @@ func _AgentService_ExecuteClaude_Handler(srv interface{}, stream grpc.ServerStre
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type AgentService_ExecuteClaudeServer = grpc.ServerStreamingServer[ExecuteClaudeResponse]
 
+func _AgentService_AttachTask_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AttachTaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServiceServer).AttachTask(m, &grpc.GenericServerStream[AttachTaskRequest, ExecuteClaudeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_AttachTaskServer = grpc.ServerStreamingServer[ExecuteClaudeResponse]
+
 func _AgentService_GetTaskStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TaskStatusRequest)
 	if err := dec(in); err != nil {
@@ -394,6 +512,96 @@ func _AgentService_ListTasks_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_CancelTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).CancelTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_CancelTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).CancelTask(ctx, req.(*CancelTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_MoveTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).MoveTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_MoveTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).MoveTask(ctx, req.(*MoveTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_DaemonInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DaemonInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).DaemonInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_DaemonInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).DaemonInfo(ctx, req.(*DaemonInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_SetCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).SetCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_SetCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).SetCredentials(ctx, req.(*SetCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetWorkspaceDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetWorkspaceDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetWorkspaceDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetWorkspaceDiff(ctx, req.(*WorkspaceDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AgentService_ServiceDesc is the grpc.ServiceDesc for AgentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -417,6 +625,26 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListTasks",
 			Handler:    _AgentService_ListTasks_Handler,
 		},
+		{
+			MethodName: "CancelTask",
+			Handler:    _AgentService_CancelTask_Handler,
+		},
+		{
+			MethodName: "MoveTask",
+			Handler:    _AgentService_MoveTask_Handler,
+		},
+		{
+			MethodName: "DaemonInfo",
+			Handler:    _AgentService_DaemonInfo_Handler,
+		},
+		{
+			MethodName: "SetCredentials",
+			Handler:    _AgentService_SetCredentials_Handler,
+		},
+		{
+			MethodName: "GetWorkspaceDiff",
+			Handler:    _AgentService_GetWorkspaceDiff_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -424,6 +652,11 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _AgentService_ExecuteClaude_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "AttachTask",
+			Handler:       _AgentService_AttachTask_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/daemon.proto",
 }
@@ -0,0 +1,3 @@
+module workspacediff
+
+go 1.23.0
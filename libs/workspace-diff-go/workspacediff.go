@@ -0,0 +1,327 @@
+// Package workspacediff computes the changes in a git workspace since the
+// commit it started from. It is shared by the daemon, which diffs the
+// workspace inside a sandbox, and the CLI, which diffs local sandboxes whose
+// project is a git worktree on the host.
+package workspacediff
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status is how a file changed. The values match the FileDiff.Status enum of
+// the daemon protocol.
+type Status int
+
+const (
+	Modified Status = iota
+	Added
+	Deleted
+	Renamed
+	// Untracked files were added but had not been staged
+	Untracked
+	TypeChanged
+)
+
+var statusNames = []string{"MODIFIED", "ADDED", "DELETED", "RENAMED", "UNTRACKED", "TYPE_CHANGED"}
+
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return strconv.Itoa(int(s))
+	}
+	return statusNames[s]
+}
+
+// FileDiff is a changed file. OldPath is only set for renames. Patch is only
+// set if patches were asked for, and cut short if PatchTruncated is set.
+type FileDiff struct {
+	Path           string
+	OldPath        string
+	Status         Status
+	Additions      int
+	Deletions      int
+	Binary         bool
+	Patch          string
+	PatchTruncated bool
+}
+
+// Diff is the set of changes in a workspace. HeadCommit and Branch are empty
+// for repositories without commits and detached heads.
+type Diff struct {
+	BaseCommit string
+	HeadCommit string
+	Branch     string
+	Files      []*FileDiff
+}
+
+const (
+	// workspaceDiffTimeout limits the git commands computing a workspace diff
+	workspaceDiffTimeout = 2 * time.Minute
+	// maxFilePatchSize is the size a single file's patch is cut to
+	maxFilePatchSize = 256 * 1024
+	// maxTotalPatchSize keeps the response below the gRPC message size limit,
+	// patches of further files are left out
+	maxTotalPatchSize = 3 * 1024 * 1024
+	// emptyTreeHash is git's empty tree, the base of a repository without commits
+	emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+)
+
+// gitRunner runs git commands in a workspace
+type gitRunner struct {
+	ctx context.Context
+	dir string
+	env []string
+}
+
+// run runs git with args and returns its trimmed output
+func (g *gitRunner) run(args ...string) (string, error) {
+	output, err := g.output(args...)
+	return strings.TrimSpace(output), err
+}
+
+// output runs git with args and returns its output as it is
+func (g *gitRunner) output(args ...string) (string, error) {
+	cmd := exec.CommandContext(g.ctx, "git", args...)
+	cmd.Dir = g.dir
+	cmd.Env = append(os.Environ(), g.env...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// Compute returns the changes in the git repository at dir since baseRef, or
+// since the commit the workspace started from if baseRef is empty.
+// Committed, staged, unstaged and untracked changes are all included; ignored
+// files are not. The repository's index is left untouched.
+func Compute(ctx context.Context, dir, baseRef string, includePatches bool) (*Diff, error) {
+	ctx, cancel := context.WithTimeout(ctx, workspaceDiffTimeout)
+	defer cancel()
+
+	git := &gitRunner{ctx: ctx, dir: dir}
+	if _, err := git.run("rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%s is not a usable git repository: %w", dir, err)
+	}
+
+	resp := &Diff{}
+	resp.HeadCommit, _ = git.run("rev-parse", "--verify", "-q", "HEAD")
+	resp.Branch, _ = git.run("symbolic-ref", "--short", "-q", "HEAD")
+
+	base, err := resolveDiffBase(git, baseRef, resp.HeadCommit)
+	if err != nil {
+		return nil, err
+	}
+	resp.BaseCommit = base
+
+	// Untracked files are listed against the real index, before they are staged below
+	untrackedOutput, err := git.output("ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	untracked := make(map[string]bool)
+	for _, path := range strings.Split(untrackedOutput, "\x00") {
+		if path != "" {
+			untracked[path] = true
+		}
+	}
+
+	// Stage everything in a copy of the index, so that untracked files show
+	// up in the diff without changing what the user or Claude staged
+	index, err := os.CreateTemp("", "dispense-diff-index-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	index.Close()
+	defer os.Remove(index.Name())
+
+	if indexPath, err := git.run("rev-parse", "--git-path", "index"); err == nil {
+		if !filepath.IsAbs(indexPath) {
+			indexPath = filepath.Join(dir, indexPath)
+		}
+		if err := copyFile(indexPath, index.Name()); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to copy index: %w", err)
+		}
+	}
+	git.env = []string{"GIT_INDEX_FILE=" + index.Name()}
+	if _, err := git.run("add", "--all"); err != nil {
+		return nil, err
+	}
+
+	nameStatus, err := git.output("diff", "--cached", "--find-renames", "--name-status", "-z", base)
+	if err != nil {
+		return nil, err
+	}
+	numstat, err := git.output("diff", "--cached", "--find-renames", "--numstat", "-z", base)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Files = parseNameStatus(nameStatus, untracked)
+	applyNumstat(resp.Files, numstat)
+
+	if includePatches {
+		total := 0
+		for _, file := range resp.Files {
+			if total >= maxTotalPatchSize {
+				file.PatchTruncated = true
+				continue
+			}
+
+			args := []string{"diff", "--cached", "--find-renames", base, "--"}
+			if file.OldPath != "" {
+				args = append(args, file.OldPath)
+			}
+			patch, err := git.output(append(args, file.Path)...)
+			if err != nil {
+				return nil, err
+			}
+			if len(patch) > maxFilePatchSize {
+				patch = patch[:maxFilePatchSize]
+				file.PatchTruncated = true
+			}
+			file.Patch = patch
+			total += len(patch)
+		}
+	}
+
+	return resp, nil
+}
+
+// HeadCommit returns the commit checked out in the git repository at dir
+func HeadCommit(ctx context.Context, dir string) (string, error) {
+	git := &gitRunner{ctx: ctx, dir: dir}
+	return git.run("rev-parse", "--verify", "HEAD")
+}
+
+// resolveDiffBase returns the commit a workspace diff compares with. Without
+// baseRef it is where the branch forked from its upstream, which is the
+// branch the bundle was cloned from, or from the default branch of the
+// cloned repository for branches created in the sandbox.
+func resolveDiffBase(git *gitRunner, baseRef, head string) (string, error) {
+	if baseRef != "" {
+		base, err := git.run("rev-parse", "--verify", "-q", baseRef+"^{commit}")
+		if err != nil || base == "" {
+			return "", fmt.Errorf("unknown base commit %q", baseRef)
+		}
+		return base, nil
+	}
+
+	if head == "" {
+		return emptyTreeHash, nil
+	}
+
+	for _, upstream := range []string{"@{upstream}", "origin/HEAD"} {
+		if base, err := git.run("merge-base", "HEAD", upstream); err == nil && base != "" {
+			return base, nil
+		}
+	}
+
+	// Without an upstream only the changes that are not committed yet are known
+	return head, nil
+}
+
+// parseNameStatus parses the output of git diff --name-status -z into file
+// diffs. Added files that were not staged before are reported as untracked.
+func parseNameStatus(output string, untracked map[string]bool) []*FileDiff {
+	var files []*FileDiff
+	fields := strings.Split(output, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		code, path := fields[i], fields[i+1]
+		if code == "" {
+			break
+		}
+
+		file := &FileDiff{Path: path}
+		switch code[0] {
+		case 'A':
+			file.Status = Added
+			if untracked[path] {
+				file.Status = Untracked
+			}
+		case 'D':
+			file.Status = Deleted
+		case 'R':
+			if i+2 >= len(fields) {
+				return files
+			}
+			file.Status = Renamed
+			file.OldPath = path
+			file.Path = fields[i+2]
+			i++
+		case 'T':
+			file.Status = TypeChanged
+		default:
+			file.Status = Modified
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// applyNumstat sets the line counts of files from the output of git diff
+// --numstat -z. Binary files have no line counts.
+func applyNumstat(files []*FileDiff, output string) {
+	byPath := make(map[string]*FileDiff, len(files))
+	for _, file := range files {
+		byPath[file.Path] = file
+	}
+
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		// Renames leave the path empty and follow with the old and new path
+		path := parts[2]
+		if path == "" && i+2 < len(fields) {
+			path = fields[i+2]
+			i += 2
+		}
+
+		file, exists := byPath[path]
+		if !exists {
+			continue
+		}
+		if parts[0] == "-" && parts[1] == "-" {
+			file.Binary = true
+			continue
+		}
+		additions, _ := strconv.Atoi(parts[0])
+		deletions, _ := strconv.Atoi(parts[1])
+		file.Additions = additions
+		file.Deletions = deletions
+	}
+}
+
+// copyFile copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,125 @@
+package workspacediff
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	if files := parseNameStatus("", nil); len(files) != 0 {
+		t.Errorf("parseNameStatus() without changes = %v", files)
+	}
+
+	output := strings.Join([]string{
+		"M", "main.go",
+		"A", "added.go",
+		"A", "new.go",
+		"D", "old.go",
+		"T", "link",
+		"R087", "docs/old name.md", "docs/new name.md",
+		"",
+	}, "\x00")
+	want := []*FileDiff{
+		{Path: "main.go", Status: Modified},
+		{Path: "added.go", Status: Added},
+		{Path: "new.go", Status: Untracked},
+		{Path: "old.go", Status: Deleted},
+		{Path: "link", Status: TypeChanged},
+		{Path: "docs/new name.md", OldPath: "docs/old name.md", Status: Renamed},
+	}
+	if files := parseNameStatus(output, map[string]bool{"new.go": true}); !reflect.DeepEqual(files, want) {
+		t.Errorf("parseNameStatus() = %+v, want %+v", files, want)
+	}
+
+	// A rename cut off before its new path is left out
+	files := parseNameStatus(strings.Join([]string{"M", "main.go", "R100", "old.go"}, "\x00"), nil)
+	if len(files) != 1 || files[0].Path != "main.go" {
+		t.Errorf("parseNameStatus() of a truncated rename = %+v", files)
+	}
+}
+
+func TestApplyNumstat(t *testing.T) {
+	files := []*FileDiff{
+		{Path: "main.go"},
+		{Path: "logo.png"},
+		{Path: "docs/new name.md", OldPath: "docs/old name.md"},
+		{Path: "untouched.go"},
+	}
+	applyNumstat(files, strings.Join([]string{
+		"12\t3\tmain.go",
+		"-\t-\tlogo.png",
+		"1\t1\t", "docs/old name.md", "docs/new name.md",
+		"5\t0\tunknown.go",
+		"",
+	}, "\x00"))
+
+	want := []*FileDiff{
+		{Path: "main.go", Additions: 12, Deletions: 3},
+		{Path: "logo.png", Binary: true},
+		{Path: "docs/new name.md", OldPath: "docs/old name.md", Additions: 1, Deletions: 1},
+		{Path: "untouched.go"},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("applyNumstat() gave %+v, want %+v", files, want)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", args[0], err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("main.go", "package main\n")
+	git("add", "main.go")
+	git("commit", "-q", "-m", "initial")
+	base, err := HeadCommit(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("HeadCommit() failed: %v", err)
+	}
+
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("notes.txt", "todo\n")
+
+	diff, err := Compute(context.Background(), dir, base, true)
+	if err != nil {
+		t.Fatalf("Compute() failed: %v", err)
+	}
+	if diff.BaseCommit != base || diff.HeadCommit != base || len(diff.Files) != 2 {
+		t.Fatalf("Compute() = %+v", diff)
+	}
+	if main := diff.Files[0]; main.Path != "main.go" || main.Status != Modified || main.Additions != 2 || !strings.Contains(main.Patch, "+func main() {}") {
+		t.Errorf("main.go diff %+v", main)
+	}
+	if notes := diff.Files[1]; notes.Path != "notes.txt" || notes.Status != Untracked || notes.Additions != 1 {
+		t.Errorf("notes.txt diff %+v", notes)
+	}
+
+	// The untracked file was only staged in a copy of the index
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = dir
+	if output, _ := cmd.Output(); !strings.Contains(string(output), "?? notes.txt") {
+		t.Errorf("Compute() changed the index, git status reports %q", output)
+	}
+}